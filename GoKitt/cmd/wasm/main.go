@@ -4,6 +4,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -26,10 +28,17 @@ import (
 	"github.com/kittclouds/gokitt/pkg/reality/merger"
 	"github.com/kittclouds/gokitt/pkg/reality/pcst"
 	"github.com/kittclouds/gokitt/pkg/reality/projection"
+	"github.com/kittclouds/gokitt/pkg/reality/scancache"
 	"github.com/kittclouds/gokitt/pkg/reality/validator"
 	"github.com/kittclouds/gokitt/pkg/resorank"
 	"github.com/kittclouds/gokitt/pkg/sab"
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
+	"github.com/kittclouds/gokitt/pkg/scanner/frontmatter"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
 )
 
 // Version info
@@ -38,6 +47,7 @@ const Version = "0.6.0" // Observational Memory + Chat Service
 // Global state
 var pipeline *conductor.Conductor
 var searcher *resorank.Scorer
+var scanCache *scancache.Cache        // Per-note scan results for scanNote
 var docs *docstore.Store              // In-memory document store
 var sqlStore *store.SQLiteStore       // SQLite persistent store
 var graphMerger *merger.Merger        // Phase 3: Graph merger instance
@@ -48,11 +58,22 @@ var agentSvc *agent.Service           // Phase 6: Agent (tool-calling)
 var chatSvc *chat.ChatService         // Phase 7: Chat + Observational Memory
 var memorySvc *memory.Extractor       // Phase 7: Memory extraction
 
+// World whose narrative lexicon is compiled into the verb matcher ("" = global only)
+var lexiconWorld string
+
+// World whose discovery candidates and rejected words are loaded and saved
+var discoveryWorld string
+
+// Note versions whose frontmatter scanNote has mapped onto their entity
+var frontmatterVersions = make(map[string]int64)
+
 func main() {
 	var err error
 	pipeline, err = conductor.New()
 	if err != nil {
 		fmt.Println("[GoKitt] FATAL: Failed to initialize conductor:", err.Error())
+	} else {
+		scanCache = scancache.New(pipeline)
 	}
 
 	// Initialize Searcher
@@ -70,10 +91,21 @@ func main() {
 		"scan":              js.FuncOf(scan),
 		"scanImplicit":      js.FuncOf(scanImplicit),
 		"scanDiscovery":     js.FuncOf(scanDiscovery),
+		"scanSequence":      js.FuncOf(scanSequence),
+		"resetScanSession":  js.FuncOf(resetScanSession),
 		"rebuildDictionary": js.FuncOf(rebuildDictionary),
 		"indexDocument":     js.FuncOf(indexDocument),
 		"indexNote":         js.FuncOf(indexNote),
+		"removeDocument":    js.FuncOf(removeDocument),
 		"search":            js.FuncOf(search),
+		"searchSave":        js.FuncOf(searchSave),
+		"searchLoad":        js.FuncOf(searchLoad),
+		// Incremental dictionary updates
+		"dictionaryAddEntity":     js.FuncOf(dictionaryAddEntity),
+		"dictionaryRemoveEntity":  js.FuncOf(dictionaryRemoveEntity),
+		"dictionaryUpdateAliases": js.FuncOf(dictionaryUpdateAliases),
+		// Inline syntax patterns
+		"setSyntaxPatterns": js.FuncOf(setSyntaxPatterns),
 		// DocStore API
 		"hydrateNotes":      js.FuncOf(hydrateNotes),      // Bulk load notes on startup
 		"upsertNote":        js.FuncOf(upsertNote),        // Update single note
@@ -87,6 +119,7 @@ func main() {
 		"storeGetNote":          js.FuncOf(storeGetNote),
 		"storeDeleteNote":       js.FuncOf(storeDeleteNote),
 		"storeListNotes":        js.FuncOf(storeListNotes),
+		"storeSearchNotes":      js.FuncOf(storeSearchNotes),
 		"storeUpsertEntity":     js.FuncOf(storeUpsertEntity),
 		"storeGetEntity":        js.FuncOf(storeGetEntity),
 		"storeGetEntityByLabel": js.FuncOf(storeGetEntityByLabel),
@@ -96,6 +129,18 @@ func main() {
 		"storeGetEdge":          js.FuncOf(storeGetEdge),
 		"storeDeleteEdge":       js.FuncOf(storeDeleteEdge),
 		"storeListEdges":        js.FuncOf(storeListEdges),
+		// Entity attributes (parsed from frontmatter)
+		"storeGetEntityAttributes": js.FuncOf(storeGetEntityAttributes),
+		// Narrative lexicon (per-world custom verbs and relations)
+		"loadLexicon":            js.FuncOf(loadLexicon),
+		"storeSetLexicon":        js.FuncOf(storeSetLexicon),
+		"storeUpsertLexiconVerb": js.FuncOf(storeUpsertLexiconVerb),
+		"storeDeleteLexiconVerb": js.FuncOf(storeDeleteLexiconVerb),
+		"storeListLexicon":       js.FuncOf(storeListLexicon),
+		// Discovery feedback (persisted candidates, accept/reject)
+		"acceptCandidate":             js.FuncOf(acceptCandidate),
+		"rejectCandidate":             js.FuncOf(rejectCandidate),
+		"storeListDiscoveryStopwords": js.FuncOf(storeListDiscoveryStopwords),
 		// Store Export/Import (OPFS sync)
 		"storeExport": js.FuncOf(storeExport),
 		"storeImport": js.FuncOf(storeImport),
@@ -104,6 +149,10 @@ func main() {
 		"storeGetFolder":    js.FuncOf(storeGetFolder),
 		"storeDeleteFolder": js.FuncOf(storeDeleteFolder),
 		"storeListFolders":  js.FuncOf(storeListFolders),
+		// Store Embeddings (sqlite-vec KNN)
+		"storeUpsertEmbedding":  js.FuncOf(storeUpsertEmbedding),
+		"storeDeleteEmbedding":  js.FuncOf(storeDeleteEmbedding),
+		"storeSearchEmbeddings": js.FuncOf(storeSearchEmbeddings),
 		// Phase 3: Graph Merger API
 		"mergerInit":       js.FuncOf(mergerInit),
 		"mergerAddScanner": js.FuncOf(mergerAddScanner),
@@ -146,6 +195,8 @@ func main() {
 // ... existing helpers ...
 
 // indexDocument: [id string, metaJSON string, tokensJSON string]
+// Tokens are indexed as given; search stems its queries, so callers should
+// send stemmed terms for prose fields (see indexNote)
 func indexDocument(this js.Value, args []js.Value) interface{} {
 	if len(args) < 3 {
 		return errorResult("requires 3 args: id, metaJSON, tokensJSON")
@@ -162,6 +213,14 @@ func indexDocument(this js.Value, args []js.Value) interface{} {
 		return errorResult("tokens json: " + err.Error())
 	}
 
+	// With a store attached, embeddings also go to sqlite-vec for KNN search.
+	// The searcher keeps its copy for brute force when the KNN query fails.
+	if sqlStore != nil && len(meta.Embedding) > 0 {
+		if err := sqlStore.UpsertEmbedding(store.EmbeddingNote, id, meta.NarrativeID, meta.Embedding); err != nil {
+			return errorResult("embedding: " + err.Error())
+		}
+	}
+
 	searcher.IndexDocument(id, meta, tokens)
 
 	return successResult("indexed " + id)
@@ -199,11 +258,25 @@ func indexNote(this js.Value, args []js.Value) interface{} {
 	// 2. Transform to ResoRank Metadata
 	docLen := len(scanRes.Tokens)
 	if docLen == 0 {
+		// Drop any previous version so stale terms don't linger
+		searcher.RemoveDocument(id)
 		return successResult("indexed empty note " + id)
 	}
 
+	// Proper nouns go to the entity field, which is never stemmed
+	fieldOf := func(tok chunker.Token) string {
+		if tok.POS == chunker.ProperNoun {
+			return resorank.EntityField
+		}
+		return "content"
+	}
+	fieldLengths := make(map[string]int)
+	for _, tok := range scanRes.Tokens {
+		fieldLengths[fieldOf(tok)]++
+	}
+
 	docMeta := resorank.DocumentMetadata{
-		FieldLengths:    map[string]int{"content": docLen},
+		FieldLengths:    fieldLengths,
 		TotalTokenCount: docLen,
 		NarrativeID:     narrativeID,
 		FolderPath:      folderPath,
@@ -216,8 +289,9 @@ func indexNote(this js.Value, args []js.Value) interface{} {
 	maxSegs := searcher.Config.MaxSegments
 
 	for i, tok := range scanRes.Tokens {
-		// Normalized term (lowercase)
-		term := strings.ToLower(tok.Text)
+		// Analyzed term: lemmatized and stemmed prose, lowercased names
+		field := fieldOf(tok)
+		term := searcher.Term(field, tok.Text)
 
 		meta, exists := tokens[term]
 		if !exists {
@@ -227,11 +301,11 @@ func indexNote(this js.Value, args []js.Value) interface{} {
 			}
 		}
 
-		// Update stats for "content" field
-		occ := meta.FieldOccurrences["content"]
+		// Update stats for the token's field
+		occ := meta.FieldOccurrences[field]
 		occ.TF++
-		occ.FieldLength = docLen
-		meta.FieldOccurrences["content"] = occ
+		occ.FieldLength = fieldLengths[field]
+		meta.FieldOccurrences[field] = occ
 
 		// Segment Mask
 		segIdx := uint32(i / tokensPerSeg)
@@ -248,6 +322,28 @@ func indexNote(this js.Value, args []js.Value) interface{} {
 	return successResult("indexed " + id)
 }
 
+// removeDocument: [id string]
+// Removes a document and its postings from ResoRank
+func removeDocument(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("requires 1 arg: id")
+	}
+	if searcher == nil {
+		return errorResult("searcher not initialized")
+	}
+
+	id := args[0].String()
+	if !searcher.RemoveDocument(id) {
+		return errorResult("document not indexed: " + id)
+	}
+	if sqlStore != nil {
+		if err := sqlStore.DeleteEmbedding(store.EmbeddingNote, id); err != nil {
+			return errorResult("embedding: " + err.Error())
+		}
+	}
+	return successResult("removed " + id)
+}
+
 // search: [queryJSON string, limit int, vectorJSON string (optional), scopeJSON string (optional)]
 func search(this js.Value, args []js.Value) interface{} {
 	if len(args) < 2 {
@@ -277,12 +373,105 @@ func search(this js.Value, args []js.Value) interface{} {
 		}
 	}
 
-	results := searcher.SearchScoped(query, vector, limit, scope)
+	// Query words are analyzed the way indexNote analyzed the notes
+	results := searcher.SearchWords(query, vector, limit, scope)
 
 	bytes, _ := json.Marshal(results)
 	return string(bytes)
 }
 
+// defaultSearchIndexID is used when searchSave/searchLoad get no explicit ID
+const defaultSearchIndexID = "default"
+
+// searchSave: [indexID string (optional)]
+// Snapshots the ResoRank index into the SQLite store so it persists with storeExport
+func searchSave(this js.Value, args []js.Value) interface{} {
+	if searcher == nil || sqlStore == nil {
+		return errorResult("searcher or store not initialized")
+	}
+
+	id := defaultSearchIndexID
+	if len(args) > 0 && args[0].String() != "" {
+		id = args[0].String()
+	}
+
+	data, err := searcher.Snapshot()
+	if err != nil {
+		return errorResult("snapshot failed: " + err.Error())
+	}
+	if err := sqlStore.SaveSearchIndex(id, data); err != nil {
+		return errorResult("save failed: " + err.Error())
+	}
+	return successResult(fmt.Sprintf("saved %d bytes", len(data)))
+}
+
+// searchLoad: [indexID string (optional)]
+// Replaces the ResoRank index with a snapshot from the SQLite store.
+// Returns an error when no snapshot exists so callers can fall back to indexNote.
+func searchLoad(this js.Value, args []js.Value) interface{} {
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	id := defaultSearchIndexID
+	if len(args) > 0 && args[0].String() != "" {
+		id = args[0].String()
+	}
+
+	data, err := sqlStore.LoadSearchIndex(id)
+	if err != nil {
+		return errorResult("load failed: " + err.Error())
+	}
+	if data == nil {
+		return errorResult("no search index: " + id)
+	}
+
+	loaded, err := resorank.LoadScorer(data)
+	if err != nil {
+		return errorResult("snapshot invalid: " + err.Error())
+	}
+	searcher = loaded
+	attachVectorSource()
+	return successResult(fmt.Sprintf("loaded %d documents", len(loaded.DocumentIndex)))
+}
+
+// attachVectorSource puts the searcher in hybrid mode: vector candidates come
+// from the store's note embeddings (sqlite-vec KNN) instead of a full scan.
+// Documents embedded before the store was up are copied into it first, so
+// the KNN index sees every embedding the searcher holds.
+func attachVectorSource() {
+	if searcher == nil || sqlStore == nil {
+		return
+	}
+	st := sqlStore
+	for id, meta := range searcher.DocumentIndex {
+		if len(meta.Embedding) == 0 {
+			continue
+		}
+		if stored, err := st.GetEmbedding(store.EmbeddingNote, id); err == nil && stored != nil {
+			continue
+		}
+		if err := st.UpsertEmbedding(store.EmbeddingNote, id, meta.NarrativeID, meta.Embedding); err != nil {
+			fmt.Println("[GoKitt] WARNING: embedding not stored for "+id+":", err.Error())
+		}
+	}
+	searcher.VectorSource = resorank.VectorSourceFunc(func(query []float32, k int, scope *resorank.SearchScope) ([]resorank.VectorCandidate, error) {
+		narrativeID := ""
+		if scope != nil {
+			narrativeID = scope.NarrativeID
+		}
+		matches, err := st.SearchEmbeddings(store.EmbeddingNote, query, k, narrativeID)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]resorank.VectorCandidate, len(matches))
+		for i, m := range matches {
+			candidates[i] = resorank.VectorCandidate{DocID: m.ID, Similarity: m.Similarity()}
+		}
+		return candidates, nil
+	})
+}
+
 // ... existing helpers ...
 
 // getVersion returns the module version
@@ -290,8 +479,9 @@ func getVersion(this js.Value, args []js.Value) interface{} {
 	return Version
 }
 
-// initialize hydrates the scanner with entity data
-// Args: [entitiesJSON string] - optional JSON array of entities
+// initialize hydrates the scanner with entity data and, when the store is
+// up, the world's narrative lexicon and discovery registry
+// Args: [entitiesJSON string, worldId string] - both optional
 func initialize(this js.Value, args []js.Value) interface{} {
 	// Re-initialize to ensure clean state
 	if pipeline != nil {
@@ -302,6 +492,7 @@ func initialize(this js.Value, args []js.Value) interface{} {
 	if err != nil {
 		return errorResult(err.Error())
 	}
+	scanCache = scancache.New(pipeline)
 
 	// Build Aho-Corasick dictionary from entities if provided
 	if len(args) > 0 && args[0].String() != "" && args[0].String() != "[]" {
@@ -318,17 +509,27 @@ func initialize(this js.Value, args []js.Value) interface{} {
 				entities[i] = *e
 			}
 
-			dict, err := implicitmatcher.Compile(entities)
-			if err != nil {
-				return errorResult("aho-corasick compile: " + err.Error())
+			if err := compileDictionary(entities); err != nil {
+				return errorResult(err.Error())
 			}
-			pipeline.SetDictionary(dict)
-			pipeline.SeedDiscovery(entities)
 			fmt.Println("[GoKitt] ✅ Dictionary compiled:", len(entities), "entities")
 			fmt.Println("[GoKitt] ✅ Discovery seeded:", len(entities), "entities")
 		}
 	}
 
+	if len(args) > 1 {
+		lexiconWorld = args[1].String()
+		discoveryWorld = lexiconWorld
+	}
+	if sqlStore != nil {
+		if err := reloadLexicon(); err != nil {
+			return errorResult("lexicon: " + err.Error())
+		}
+		if err := reloadDiscovery(); err != nil {
+			return errorResult("discovery: " + err.Error())
+		}
+	}
+
 	return successResult("initialized")
 }
 
@@ -346,15 +547,29 @@ func isWordRune(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
 }
 
+// implicitOptions are scanImplicit's optional settings
+type implicitOptions struct {
+	Fuzzy       bool   `json:"fuzzy"`       // Also report misspelled and inflected names as possible mentions
+	NarrativeID string `json:"narrativeId"` // Only match this narrative's entities and global ones
+}
+
 // scanImplicit finds known entities in text using Aho-Corasick
-// Args: [text string]
-// Returns: JSON array of decoration spans with RUNE offsets (not byte offsets)
+// Args: [text string, options?: JSON {"fuzzy": bool, "narrativeId": string}]
+// Returns: JSON array of decoration spans with RUNE offsets (not byte offsets).
+// Each span has a confidence; fuzzy matches are flagged "possible". A name
+// several in-scope entities share that the paragraph doesn't settle is
+// flagged "ambiguous", with its "candidates" best first.
 func scanImplicit(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return "[]"
 	}
 	text := args[0].String()
 
+	var opts implicitOptions
+	if len(args) > 1 && args[1].String() != "" && args[1].String() != "null" {
+		_ = json.Unmarshal([]byte(args[1].String()), &opts)
+	}
+
 	if pipeline == nil {
 		return "[]"
 	}
@@ -364,10 +579,16 @@ func scanImplicit(this js.Value, args []js.Value) interface{} {
 		return "[]"
 	}
 
-	matches := dict.ScanWithInfo(text)
-	spans := make([]map[string]interface{}, 0, len(matches))
+	var matches []implicitmatcher.ScanHit
+	if opts.Fuzzy {
+		matches = dict.ScanWithInfoFuzzy(text)
+	} else {
+		matches = dict.ScanWithInfo(text)
+	}
+	scope := implicitmatcher.Scope{NarrativeID: opts.NarrativeID}
 
-	for _, m := range matches {
+	hits := make([]implicitmatcher.ScanHit, 0, len(matches))
+	for _, m := range scope.Filter(matches) {
 		// Check Word Boundaries using rune-aware decoding
 		// 1. Previous rune must be non-alphanumeric (or start of string)
 		if m.Start > 0 {
@@ -384,45 +605,74 @@ func scanImplicit(this js.Value, args []js.Value) interface{} {
 				continue
 			}
 		}
+		hits = append(hits, m)
+	}
+
+	spans := make([]map[string]interface{}, 0, len(hits))
+	for _, m := range hits {
+		from, to := paragraphAround(text, m.Start)
+		resolution := dict.Disambiguate(m.Entities, implicitmatcher.Evidence{
+			Scope:       scope,
+			CoOccurring: implicitmatcher.CoOccurring(hits, from, to),
+		})
+		best := resolution.Best
+		if best == nil {
+			continue
+		}
 
-		if len(m.Entities) > 0 {
-			best := dict.SelectBest(getEntityIDs(m.Entities))
-			if best != nil {
-				// Convert byte offsets → rune offsets for JavaScript
-				runeFrom := byteToRuneOffset(text, m.Start)
-				runeTo := byteToRuneOffset(text, m.End)
-
-				spans = append(spans, map[string]interface{}{
-					"type":     "entity_implicit",
-					"from":     runeFrom,
-					"to":       runeTo,
-					"label":    best.Label,
-					"kind":     best.Kind.String(),
-					"resolved": true,
-				})
+		// Convert byte offsets → rune offsets for JavaScript
+		runeFrom := byteToRuneOffset(text, m.Start)
+		runeTo := byteToRuneOffset(text, m.End)
+
+		span := map[string]interface{}{
+			"type":       "entity_implicit",
+			"from":       runeFrom,
+			"to":         runeTo,
+			"label":      best.Label,
+			"kind":       best.Kind.String(),
+			"resolved":   true,
+			"confidence": m.Confidence,
+			"possible":   m.Confidence < implicitmatcher.ExactConfidence,
+		}
+		if resolution.Ambiguous {
+			candidates := make([]map[string]interface{}, len(resolution.Candidates))
+			for i, c := range resolution.Candidates {
+				candidates[i] = map[string]interface{}{
+					"id":          c.ID,
+					"label":       c.Label,
+					"kind":        c.Kind.String(),
+					"narrativeId": c.NarrativeID,
+				}
 			}
+			span["ambiguous"] = true
+			span["candidates"] = candidates
 		}
+		spans = append(spans, span)
 	}
 
 	bytes, _ := json.Marshal(spans)
 	return string(bytes)
 }
 
-func isWordChar(b byte) bool {
-	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b == '_'
+// paragraphAround returns the byte range of the blank-line separated paragraph containing pos
+func paragraphAround(text string, pos int) (int, int) {
+	from, to := 0, len(text)
+	if i := strings.LastIndex(text[:pos], "\n\n"); i >= 0 {
+		from = i + 2
+	}
+	if i := strings.Index(text[pos:], "\n\n"); i >= 0 {
+		to = pos + i
+	}
+	return from, to
 }
 
-// getEntityIDs extracts IDs from EntityInfo slice
-func getEntityIDs(entities []*implicitmatcher.EntityInfo) []string {
-	ids := make([]string, len(entities))
-	for i, e := range entities {
-		ids[i] = e.ID
-	}
-	return ids
+func isWordChar(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b == '_'
 }
 
 // rebuildDictionary recompiles the Aho-Corasick dictionary with new entities
-// Call this when entities are added/removed from the registry
+// Call this to replace the whole registry; single changes can use
+// dictionaryAddEntity, dictionaryRemoveEntity and dictionaryUpdateAliases
 // Args: [entitiesJSON string] - JSON array of RegisteredEntity
 // Returns: success/error result
 func rebuildDictionary(this js.Value, args []js.Value) interface{} {
@@ -433,6 +683,9 @@ func rebuildDictionary(this js.Value, args []js.Value) interface{} {
 		return errorResult("pipeline not initialized")
 	}
 
+	// Cached scans were resolved against the old dictionary
+	scanCache.Clear()
+
 	entitiesJSON := args[0].String()
 	if entitiesJSON == "" || entitiesJSON == "[]" {
 		// No entities - clear dictionary
@@ -460,21 +713,262 @@ func rebuildDictionary(this js.Value, args []js.Value) interface{} {
 	}
 
 	// Compile new dictionary
+	if err := compileDictionary(entities); err != nil {
+		return errorResult(err.Error())
+	}
+	fmt.Printf("[GoKitt] ✅ Dictionary rebuilt: %d entities\n", len(entities))
+
+	return successResult(fmt.Sprintf("rebuilt with %d entities", len(entities)))
+}
+
+// compileDictionary builds the implicit matcher from entities and seeds discovery
+func compileDictionary(entities []implicitmatcher.RegisteredEntity) error {
 	dict, err := implicitmatcher.Compile(entities)
 	if err != nil {
-		return errorResult("aho-corasick compile: " + err.Error())
+		return fmt.Errorf("aho-corasick compile: %w", err)
 	}
-
 	pipeline.SetDictionary(dict)
 	pipeline.SeedDiscovery(entities)
-	fmt.Printf("[GoKitt] ✅ Dictionary rebuilt: %d entities\n", len(entities))
+	return nil
+}
 
-	return successResult(fmt.Sprintf("rebuilt with %d entities", len(entities)))
+// registerDictionaryEntity adds or replaces one entity in the current dictionary.
+// Aliases already known for the entity are kept alongside the new ones. An
+// entry with the same label in the same narrative is merged into it; same-named
+// entities of other narratives are left alone. An unchanged entity keeps the
+// dictionary, and the scan cache, as they are.
+func registerDictionaryEntity(e implicitmatcher.RegisteredEntity) error {
+	if pipeline == nil {
+		return nil
+	}
+
+	var current *implicitmatcher.RegisteredEntity
+	merged := false
+	if dict := pipeline.GetDictionary(); dict != nil {
+		for _, existing := range dict.Entities() {
+			if existing.ID != e.ID && (existing.NarrativeID != e.NarrativeID || !strings.EqualFold(existing.Label, e.Label)) {
+				continue
+			}
+			e.Aliases = frontmatter.MergeAliases(existing.Aliases, e.Aliases)
+			if existing.ID == e.ID {
+				current = &existing
+				continue
+			}
+			if err := dict.RemoveEntity(existing.ID); err != nil {
+				return err
+			}
+			pipeline.RemoveSeed(existing.ID)
+			merged = true
+		}
+	}
+	if current != nil && !merged && sameDictionaryEntity(*current, e) {
+		pipeline.AddSeed(e)
+		return nil
+	}
+	return addDictionaryEntity(e)
+}
+
+// sameDictionaryEntity reports whether registering e would leave the
+// dictionary's entry as it is
+func sameDictionaryEntity(existing, e implicitmatcher.RegisteredEntity) bool {
+	kind, ok := e.Kind.(string)
+	if !ok || implicitmatcher.ParseKind(kind) != existing.Kind {
+		return false
+	}
+	return existing.Label == e.Label && existing.NarrativeID == e.NarrativeID && equalStrings(existing.Aliases, e.Aliases)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// addDictionaryEntity adds or replaces one entity without recompiling the
+// dictionary, and adds it to the entities seeding discovery
+func addDictionaryEntity(e implicitmatcher.RegisteredEntity) error {
+	// Cached scans were resolved against the old dictionary
+	scanCache.Clear()
+
+	dict := pipeline.GetDictionary()
+	if dict == nil {
+		return compileDictionary([]implicitmatcher.RegisteredEntity{e})
+	}
+	if err := dict.AddEntity(e); err != nil {
+		return fmt.Errorf("aho-corasick update: %w", err)
+	}
+	pipeline.AddSeed(e)
+	return nil
+}
+
+// dictionaryAddEntity adds or replaces one entity in the implicit-matcher
+// dictionary, without the full recompile of rebuildDictionary
+// Args: [entityJSON string] - one RegisteredEntity
+func dictionaryAddEntity(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("dictionaryAddEntity requires 1 argument: entityJSON")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	var e implicitmatcher.RegisteredEntity
+	if err := json.Unmarshal([]byte(args[0].String()), &e); err != nil {
+		return errorResult("invalid entity json: " + err.Error())
+	}
+	if e.ID == "" || e.Label == "" {
+		return errorResult("entity requires id and label")
+	}
+	if err := addDictionaryEntity(e); err != nil {
+		return errorResult(err.Error())
+	}
+	return successResult(e.ID)
+}
+
+// setSyntaxPatterns replaces the world's inline syntax patterns
+// ("{{date:...}}", "%%GM note%%"), which the syntax scanner then matches
+// alongside wikilinks and tags. An empty array clears them.
+// Args: [patternsJSON string] - JSON array of syntax.InlinePattern
+// Returns: JSON object mapping each pattern name to its SyntaxKind
+func setSyntaxPatterns(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("setSyntaxPatterns requires 1 argument: patternsJSON")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	var patterns []syntax.InlinePattern
+	if err := json.Unmarshal([]byte(args[0].String()), &patterns); err != nil {
+		return errorResult("invalid patterns json: " + err.Error())
+	}
+
+	scanner := pipeline.GetSyntaxScanner()
+	previous := scanner.Patterns()
+	register := func(patterns []syntax.InlinePattern) (map[string]syntax.SyntaxKind, error) {
+		scanner.ClearPatterns()
+		kinds := make(map[string]syntax.SyntaxKind, len(patterns))
+		for _, p := range patterns {
+			kind, err := scanner.Register(p)
+			if err != nil {
+				return nil, err
+			}
+			kinds[p.Name] = kind
+		}
+		return kinds, nil
+	}
+
+	kinds, err := register(patterns)
+	if err != nil {
+		register(previous)
+		return errorResult(err.Error())
+	}
+	// Cached scans were matched with the old patterns
+	scanCache.Clear()
+
+	bytes, _ := json.Marshal(kinds)
+	return string(bytes)
+}
+
+// dictionaryRemoveEntity removes one entity from the implicit-matcher dictionary
+// Args: [entityId string]
+func dictionaryRemoveEntity(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("dictionaryRemoveEntity requires 1 argument: entityId")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	dict := pipeline.GetDictionary()
+	if dict == nil {
+		return successResult("no dictionary")
+	}
+	scanCache.Clear()
+	if err := dict.RemoveEntity(args[0].String()); err != nil {
+		return errorResult("aho-corasick update: " + err.Error())
+	}
+	pipeline.RemoveSeed(args[0].String())
+	return successResult(args[0].String())
+}
+
+// dictionaryUpdateAliases replaces one entity's aliases in the implicit-matcher dictionary
+// Args: [entityId string, aliasesJSON string]
+func dictionaryUpdateAliases(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return errorResult("dictionaryUpdateAliases requires 2 arguments: entityId, aliasesJSON")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	var aliases []string
+	if err := json.Unmarshal([]byte(args[1].String()), &aliases); err != nil {
+		return errorResult("invalid aliases json: " + err.Error())
+	}
+	dict := pipeline.GetDictionary()
+	if dict == nil {
+		return errorResult("dictionary not initialized")
+	}
+	scanCache.Clear()
+	if err := dict.UpdateAliases(args[0].String(), aliases); err != nil {
+		return errorResult(err.Error())
+	}
+	return successResult(args[0].String())
+}
+
+// scanOptions selects the scan session and how resolver context flows into a scan
+type scanOptions struct {
+	NarrativeID string `json:"narrativeId"`
+	Context     string `json:"context"` // "carry", "fork" or "reset" (default)
+}
+
+// readScanOptions reads an optional scanOptions JSON argument
+func readScanOptions(args []js.Value, i int) scanOptions {
+	var opts scanOptions
+	if len(args) > i && args[i].String() != "" && args[i].String() != "null" {
+		_ = json.Unmarshal([]byte(args[i].String()), &opts)
+	}
+	return opts
+}
+
+// parseScanOptions reads an optional scanOptions JSON argument into a session and mode
+func parseScanOptions(args []js.Value, i int) (*conductor.ScanSession, conductor.ContextMode) {
+	opts := readScanOptions(args, i)
+	return pipeline.Session(opts.NarrativeID), opts.mode()
+}
+
+// mode defaults to ContextReset, so a scan doesn't depend on what was scanned before
+func (o scanOptions) mode() conductor.ContextMode {
+	if o.Context == "" {
+		return conductor.ContextReset
+	}
+	return conductor.ParseContextMode(o.Context)
+}
+
+// noteNarrative returns the narrative of a stored note, "" if unknown
+func noteNarrative(noteID string) string {
+	if sqlStore == nil {
+		return ""
+	}
+	note, err := sqlStore.GetNote(noteID)
+	if err != nil || note == nil {
+		return ""
+	}
+	return note.NarrativeID
 }
 
 // scan processes text and returns result
-// Args: [text string, provenanceJSON string (optional)]
-// Returns: SLIM response with only graph data (nodes/edges) + timing
+// Args: [text string, provenanceJSON string (optional), optionsJSON string (optional)]
+// Options: {narrativeId, context: "carry" | "fork" | "reset" (default)}
+// Returns: SLIM response with only graph data (nodes/edges) + timing, plus
+// any implicit matches the narrative's entities leave ambiguous
 func scan(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return errorResult("scan requires at least 1 argument: text")
@@ -506,7 +1000,11 @@ func scan(this js.Value, args []js.Value) interface{} {
 	}
 
 	// 1. Scan (The Senses)
-	result := pipeline.Scan(text)
+	session, mode := parseScanOptions(args, 2)
+	result := session.ScanWith(text, mode)
+	if err := saveDiscovery(session); err != nil {
+		fmt.Println("[GoKitt] WARNING: Discovery save failed:", err.Error())
+	}
 
 	// 2. Reality (The Brain)
 	cstRoot := builder.Zip(text, result)
@@ -519,6 +1017,9 @@ func scan(this js.Value, args []js.Value) interface{} {
 	}
 
 	conceptGraph := projection.Project(cstRoot, pipeline.GetMatcher(), entityMap, text, prov)
+	projection.ProjectDialogue(conceptGraph, result.Dialogue, result.Entities, prov)
+	projection.ProjectPossessions(conceptGraph, result.Possessions, prov)
+	projection.NameEntities(conceptGraph, result.Entities)
 	conceptGraph.ToSerializable() // Populate edges for JSON output
 
 	// 4. PCST (The Summary) - Still computed, just not serialized
@@ -533,17 +1034,38 @@ func scan(this js.Value, args []js.Value) interface{} {
 
 	// OPTIMIZATION: Slim response - only fields JS actually uses
 	// Removes: scan, cst, pcst (unused by Angular)
-	slimNodes := make(map[string]interface{}, len(conceptGraph.Nodes))
-	for id, node := range conceptGraph.Nodes {
-		slimNodes[id] = map[string]interface{}{
+	response := map[string]interface{}{
+		"graph":     slimGraph(conceptGraph),
+		"timing_us": duration,
+	}
+	if len(result.Dialogue) > 0 {
+		response["dialogue"] = slimDialogue(text, result.Dialogue)
+	}
+	if len(result.Ambiguities) > 0 {
+		response["ambiguities"] = slimAmbiguities(text, result.Ambiguities)
+	}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	return string(jsonBytes)
+}
+
+// slimGraph converts a serialized concept graph to the node/edge fields JS reads
+func slimGraph(g *graph.ConceptGraph) map[string]interface{} {
+	nodes := make(map[string]interface{}, len(g.Nodes))
+	for id, node := range g.Nodes {
+		nodes[id] = map[string]interface{}{
 			"label": node.Label,
 			"kind":  node.Kind,
 		}
 	}
 
-	slimEdges := make([]interface{}, 0, len(conceptGraph.Edges))
-	for _, edge := range conceptGraph.Edges {
-		slimEdges = append(slimEdges, map[string]interface{}{
+	edges := make([]interface{}, 0, len(g.Edges))
+	for _, edge := range g.Edges {
+		edges = append(edges, map[string]interface{}{
 			"source":     edge.Source,
 			"target":     edge.Target,
 			"type":       edge.Relation,
@@ -551,12 +1073,108 @@ func scan(this js.Value, args []js.Value) interface{} {
 		})
 	}
 
+	return map[string]interface{}{
+		"nodes": nodes,
+		"edges": edges,
+	}
+}
+
+// slimDialogue converts utterances to JSON with RUNE offsets, plus per-speaker line counts
+func slimDialogue(text string, utterances []dialogue.Utterance) map[string]interface{} {
+	lines := make([]interface{}, 0, len(utterances))
+	for _, u := range utterances {
+		lines = append(lines, map[string]interface{}{
+			"speaker":     u.Speaker,
+			"listener":    u.Listener,
+			"start":       byteToRuneOffset(text, u.Range.Start),
+			"end":         byteToRuneOffset(text, u.Range.End),
+			"text":        text[u.Content.Start:u.Content.End],
+			"attribution": u.Attribution.String(),
+		})
+	}
+	return map[string]interface{}{
+		"lines":  lines,
+		"counts": dialogue.LineCounts(utterances),
+	}
+}
+
+// slimAmbiguities converts unsettled implicit matches to JSON with RUNE offsets
+func slimAmbiguities(text string, ambiguities []conductor.Ambiguity) []interface{} {
+	out := make([]interface{}, 0, len(ambiguities))
+	for _, a := range ambiguities {
+		out = append(out, map[string]interface{}{
+			"text":       a.Text,
+			"start":      byteToRuneOffset(text, a.Range.Start),
+			"end":        byteToRuneOffset(text, a.Range.End),
+			"chosen":     a.Chosen,
+			"candidates": a.Candidates,
+		})
+	}
+	return out
+}
+
+// scanSequence scans DocStore notes in order (e.g. chapters) so coreference
+// carries from each note into the next.
+// Args: [noteIdsJSON string, optionsJSON string (optional)]
+// Options: {narrativeId, context} - context decides where the sequence starts
+// and whether the session keeps its state afterwards ("carry") or not ("fork",
+// "reset"); by default it starts without pronoun history
+func scanSequence(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("scanSequence requires 1 arg: noteIdsJSON")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	var noteIDs []string
+	if err := json.Unmarshal([]byte(args[0].String()), &noteIDs); err != nil {
+		return errorResult("invalid note IDs JSON: " + err.Error())
+	}
+
+	texts := make([]string, len(noteIDs))
+	for i, id := range noteIDs {
+		texts[i] = docs.GetText(id)
+		if texts[i] == "" {
+			return errorResult("note not found in DocStore: " + id)
+		}
+	}
+
+	start := time.Now()
+	session, mode := parseScanOptions(args, 1)
+	results := session.ScanSequence(texts, mode)
+	if err := saveDiscovery(session); err != nil {
+		fmt.Println("[GoKitt] WARNING: Discovery save failed:", err.Error())
+	}
+
+	notes := make([]interface{}, 0, len(results))
+	for i, result := range results {
+		cstRoot := builder.Zip(texts[i], result)
+
+		entityMap := make(projection.EntityMap)
+		for _, ref := range result.ResolvedRefs {
+			entityMap[ref.Range.Start] = ref.EntityID
+		}
+
+		conceptGraph := projection.Project(cstRoot, pipeline.GetMatcher(), entityMap, texts[i], nil)
+		projection.ProjectDialogue(conceptGraph, result.Dialogue, result.Entities, nil)
+		projection.ProjectPossessions(conceptGraph, result.Possessions, nil)
+		projection.NameEntities(conceptGraph, result.Entities)
+		conceptGraph.ToSerializable()
+
+		note := map[string]interface{}{
+			"noteId": noteIDs[i],
+			"graph":  slimGraph(conceptGraph),
+		}
+		if len(result.Ambiguities) > 0 {
+			note["ambiguities"] = slimAmbiguities(texts[i], result.Ambiguities)
+		}
+		notes = append(notes, note)
+	}
+
 	response := map[string]interface{}{
-		"graph": map[string]interface{}{
-			"nodes": slimNodes,
-			"edges": slimEdges,
-		},
-		"timing_us": duration,
+		"notes":     notes,
+		"timing_us": time.Since(start).Microseconds(),
 	}
 
 	jsonBytes, err := json.Marshal(response)
@@ -567,26 +1185,62 @@ func scan(this js.Value, args []js.Value) interface{} {
 	return string(jsonBytes)
 }
 
-// scanDiscovery performs unsupervised NER ("The Virus")
-// Args: [text string]
-func scanDiscovery(this js.Value, args []js.Value) interface{} {
+// resetScanSession clears a narrative's scan context.
+// By default only pronoun history is cleared; drop=true also forgets the
+// narrative's registered entities and discovery candidates.
+// Args: [narrativeId string ("" for the default session), drop bool (optional)]
+func resetScanSession(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
-		return errorResult("scanDiscovery requires 1 argument: text")
+		return errorResult("resetScanSession requires 1 arg: narrativeId")
 	}
 	if pipeline == nil {
 		return errorResult("pipeline not initialized")
 	}
 
-	text := args[0].String()
-	// Scan the text with Discovery Engine (heuristic)
-	pipeline.ScanDiscovery(text)
+	narrativeID := args[0].String()
+	if len(args) > 1 && args[1].Truthy() {
+		pipeline.DropSession(narrativeID)
+		return successResult("dropped scan session " + narrativeID)
+	}
 
-	candidates := pipeline.GetCandidates()
-	jsonBytes, _ := json.Marshal(candidates)
-	return string(jsonBytes)
+	pipeline.Session(narrativeID).Reset()
+	return successResult("reset scan session " + narrativeID)
 }
 
-// Helper: Create error result
+// scanDiscovery performs unsupervised NER ("The Virus").
+// With the store up, the session's candidates are saved afterwards.
+// Args: [text string, narrativeId string (optional), noteId string (optional)]
+func scanDiscovery(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("scanDiscovery requires 1 argument: text")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	text := args[0].String()
+	session := pipeline.Session("")
+	if len(args) > 1 && args[1].String() != "" && args[1].String() != "null" {
+		session = pipeline.Session(args[1].String())
+	}
+
+	noteID := ""
+	if len(args) > 2 && args[2].String() != "null" {
+		noteID = args[2].String()
+	}
+
+	// Scan the text with Discovery Engine (heuristic)
+	session.ScanDiscoveryNote(noteID, text)
+	if err := saveDiscovery(session); err != nil {
+		fmt.Println("[GoKitt] WARNING: Discovery save failed:", err.Error())
+	}
+
+	candidates := session.GetCandidates()
+	jsonBytes, _ := json.Marshal(candidates)
+	return string(jsonBytes)
+}
+
+// Helper: Create error result
 func errorResult(msg string) interface{} {
 	result := map[string]interface{}{
 		"error": msg,
@@ -668,12 +1322,22 @@ func removeNote(this js.Value, args []js.Value) interface{} {
 
 	id := args[0].String()
 	docs.Remove(id)
+	delete(frontmatterVersions, id)
+	if scanCache != nil {
+		scanCache.Invalidate(id)
+	}
+	if graphMerger != nil {
+		graphMerger.RemoveScannerNote(id)
+	}
 	return successResult("removed " + id)
 }
 
 // scanNote scans a note from DocStore (not from JS).
 // This eliminates the JS→Go text transfer on each scan.
-// Args: [id string, provenanceJSON string (optional)]
+// Results are cached by note version; only changed paragraphs are rescanned.
+// Args: [id string, provenanceJSON string (optional), optionsJSON string (optional)]
+// Options: {narrativeId, context} - narrativeId defaults to the stored note's
+// narrative, context to "reset" (see scan)
 func scanNote(this js.Value, args []js.Value) interface{} {
 	if len(args) < 1 {
 		return errorResult("scanNote requires 1 arg: noteId")
@@ -685,8 +1349,8 @@ func scanNote(this js.Value, args []js.Value) interface{} {
 	noteId := args[0].String()
 
 	// Get text from DocStore (not from JS!)
-	text := docs.GetText(noteId)
-	if text == "" {
+	doc := docs.Get(noteId)
+	if doc == nil || doc.Text == "" {
 		return errorResult("note not found in DocStore: " + noteId)
 	}
 
@@ -711,58 +1375,57 @@ func scanNote(this js.Value, args []js.Value) interface{} {
 		}
 	}
 
-	// === SAME PIPELINE AS scan() ===
-	// 1. Scan (The Senses)
-	result := pipeline.Scan(text)
-
-	// 2. Reality (The Brain)
-	cstRoot := builder.Zip(text, result)
-
-	// 3. Graph (The World)
-	entityMap := make(projection.EntityMap)
-	for _, ref := range result.ResolvedRefs {
-		entityMap[ref.Range.Start] = ref.EntityID
+	// Scan → Zip → Project, reusing unchanged paragraphs from the last scan
+	opts := readScanOptions(args, 2)
+	if opts.NarrativeID == "" {
+		opts.NarrativeID = noteNarrative(noteId)
 	}
 
-	conceptGraph := projection.Project(cstRoot, pipeline.GetMatcher(), entityMap, text, prov)
-	conceptGraph.ToSerializable()
-
-	// 4. PCST (The Summary)
-	prizes := make(map[string]float64)
-	for id := range conceptGraph.Nodes {
-		prizes[id] = 1.0
+	// An entity note's frontmatter updates its entity first, so the aliases it
+	// declares already match in this scan; an unchanged version was synced already
+	if frontmatterVersions[noteId] != doc.Version {
+		if err := syncNoteFrontmatter(noteId, doc.Text); err != nil {
+			fmt.Println("[GoKitt] WARNING: frontmatter sync failed for "+noteId+":", err.Error())
+		} else {
+			frontmatterVersions[noteId] = doc.Version
+		}
 	}
-	solver := pcst.NewIpcstSolver(pcst.DefaultConfig())
-	_, _ = solver.Solve(conceptGraph, prizes, "")
+	cached := scanCache.ScanWith(noteId, doc.Version, doc.Text, prov, scancache.Options{
+		NarrativeID: opts.NarrativeID,
+		Context:     opts.mode(),
+	})
+	conceptGraph := cached.Graph
 
-	duration := time.Since(start).Microseconds()
+	if !cached.Cached {
+		if err := saveDiscovery(pipeline.Session(opts.NarrativeID)); err != nil {
+			fmt.Println("[GoKitt] WARNING: Discovery save failed:", err.Error())
+		}
+		conceptGraph.ToSerializable()
 
-	// Slim response
-	slimNodes := make(map[string]interface{}, len(conceptGraph.Nodes))
-	for id, node := range conceptGraph.Nodes {
-		slimNodes[id] = map[string]interface{}{
-			"label": node.Label,
-			"kind":  node.Kind,
+		// PCST (The Summary)
+		prizes := make(map[string]float64)
+		for id := range conceptGraph.Nodes {
+			prizes[id] = 1.0
 		}
+		solver := pcst.NewIpcstSolver(pcst.DefaultConfig())
+		_, _ = solver.Solve(conceptGraph, prizes, "")
 	}
 
-	slimEdges := make([]interface{}, 0, len(conceptGraph.Edges))
-	for _, edge := range conceptGraph.Edges {
-		slimEdges = append(slimEdges, map[string]interface{}{
-			"source":     edge.Source,
-			"target":     edge.Target,
-			"type":       edge.Relation,
-			"confidence": edge.Weight,
-		})
-	}
+	duration := time.Since(start).Microseconds()
 
 	response := map[string]interface{}{
-		"noteId": noteId,
-		"graph": map[string]interface{}{
-			"nodes": slimNodes,
-			"edges": slimEdges,
-		},
-		"timing_us": duration,
+		"noteId":     noteId,
+		"graph":      slimGraph(conceptGraph),
+		"cached":     cached.Cached,
+		"paragraphs": cached.Paragraphs,
+		"rescanned":  cached.Rescanned,
+		"timing_us":  duration,
+	}
+	if cached.Scan.Frontmatter != nil {
+		response["frontmatter"] = cached.Scan.Frontmatter
+	}
+	if len(cached.Scan.Dialogue) > 0 {
+		response["dialogue"] = slimDialogue(doc.Text, cached.Scan.Dialogue)
 	}
 
 	jsonBytes, err := json.Marshal(response)
@@ -802,8 +1465,8 @@ func validateRelations(this js.Value, args []js.Value) interface{} {
 		return errorResult("Failed to parse relations JSON: " + err.Error())
 	}
 
-	// Build CST from the note text
-	scanResult := pipeline.Scan(note.Text)
+	// Build CST from the note text, in the note's narrative
+	scanResult := pipeline.Session(noteNarrative(noteID)).ScanWith(note.Text, conductor.ContextReset)
 	cstRoot := builder.Zip(note.Text, scanResult)
 
 	// Create validator and validate
@@ -844,7 +1507,19 @@ func storeInit(this js.Value, args []js.Value) interface{} {
 	if err != nil {
 		return errorResult("failed to initialize SQLite store: " + err.Error())
 	}
+	attachVectorSource()
+	frontmatterVersions = make(map[string]int64) // Synced into the previous store
 	fmt.Println("[GoKitt] ✅ SQLite Store initialized")
+
+	// The pipeline may have come up first; restore what initialize couldn't
+	if pipeline != nil {
+		if err := reloadLexicon(); err != nil {
+			return errorResult("lexicon: " + err.Error())
+		}
+		if err := reloadDiscovery(); err != nil {
+			return errorResult("discovery: " + err.Error())
+		}
+	}
 	return successResult("store initialized")
 }
 
@@ -863,13 +1538,147 @@ func storeUpsertNote(this js.Value, args []js.Value) interface{} {
 		return errorResult("invalid note json: " + err.Error())
 	}
 
+	// Entity notes declare their kind in frontmatter; a bad block doesn't block the save
+	body := note.MarkdownContent
+	if body == "" {
+		body = note.Content
+	}
+	fm, err := frontmatter.Extract(body)
+	if err != nil {
+		fmt.Printf("[GoKitt] ⚠️ Note %s: %v\n", note.ID, err)
+	}
+	if fm.IsEntity() {
+		note.IsEntity = true
+		note.EntityKind = fm.Kind
+		if fm.Subtype != "" {
+			note.EntitySubtype = fm.Subtype
+		}
+	}
+
 	if err := sqlStore.UpsertNote(&note); err != nil {
 		return errorResult("upsert failed: " + err.Error())
 	}
 
+	if fm.IsEntity() {
+		entity, err := syncFrontmatterEntity(&note, fm)
+		if err != nil {
+			return errorResult("entity sync failed: " + err.Error())
+		}
+		return successResult("upserted " + note.ID + " (entity " + entity.ID + ")")
+	}
 	return successResult("upserted " + note.ID)
 }
 
+// syncNoteFrontmatter maps the frontmatter of a stored entity note onto its
+// entity, as storeUpsertNote does when the note is saved
+func syncNoteFrontmatter(noteID, text string) error {
+	if sqlStore == nil {
+		return nil
+	}
+	fm, err := frontmatter.Extract(text)
+	if err != nil || !fm.IsEntity() {
+		return err
+	}
+	note, err := sqlStore.GetNote(noteID)
+	if err != nil || note == nil {
+		return err
+	}
+	if fm.Label == "" && note.Title == "" {
+		return nil
+	}
+	_, err = syncFrontmatterEntity(note, fm)
+	return err
+}
+
+// syncFrontmatterEntity upserts the entity an entity note describes, replaces its
+// attributes and adds its aliases to the dictionary.
+// The entity is found by note ID, then by label in the note's narrative;
+// otherwise it is created with the note's ID. Nothing is written for an
+// entity or attributes the frontmatter leaves as they are.
+func syncFrontmatterEntity(note *store.Note, fm *frontmatter.Frontmatter) (*store.Entity, error) {
+	label := fm.Label
+	if label == "" {
+		label = note.Title
+	}
+
+	entity, err := sqlStore.GetEntity(note.ID)
+	if err == nil && entity == nil {
+		entity, err = sqlStore.GetEntityByLabelInNarrative(label, note.NarrativeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	changed := entity == nil
+	if entity == nil {
+		entity = &store.Entity{
+			ID:          note.ID,
+			FirstNote:   note.ID,
+			NarrativeID: note.NarrativeID,
+			CreatedBy:   "user",
+			CreatedAt:   now,
+		}
+	}
+	subtype := entity.Subtype
+	if fm.Subtype != "" {
+		subtype = fm.Subtype
+	}
+	aliases := frontmatter.MergeAliases(entity.Aliases, fm.Aliases)
+	if changed || entity.Label != label || entity.Kind != fm.Kind || entity.Subtype != subtype || !equalStrings(entity.Aliases, aliases) {
+		entity.Label, entity.Kind, entity.Subtype, entity.Aliases = label, fm.Kind, subtype, aliases
+		entity.UpdatedAt = now
+		if err := sqlStore.UpsertEntity(entity); err != nil {
+			return nil, err
+		}
+	}
+
+	attrs := make([]*store.EntityAttribute, len(fm.Attributes))
+	for i, a := range fm.Attributes {
+		attrs[i] = &store.EntityAttribute{EntityID: entity.ID, Key: a.Key, Type: string(a.Type), Value: a.Value}
+	}
+	stored, err := sqlStore.GetEntityAttributes(entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !sameAttributes(stored, attrs) {
+		if err := sqlStore.SetEntityAttributes(entity.ID, attrs); err != nil {
+			return nil, err
+		}
+	}
+
+	reg := fm.Registered(entity.ID, entity.Label, entity.NarrativeID)
+	reg.Aliases = entity.Aliases
+	if err := registerDictionaryEntity(reg); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// sameAttributes reports whether two attribute sets hold the same keys, types and values
+func sameAttributes(a, b []*store.EntityAttribute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byKey := make(map[string]*store.EntityAttribute, len(a))
+	for _, attr := range a {
+		byKey[attr.Key] = attr
+	}
+	for _, attr := range b {
+		other, ok := byKey[attr.Key]
+		if !ok || other.Type != attr.Type {
+			return false
+		}
+		// Stored values come back through JSON; compare them the same way
+		x, errX := json.Marshal(other.Value)
+		y, errY := json.Marshal(attr.Value)
+		if errX != nil || errY != nil || string(x) != string(y) {
+			return false
+		}
+	}
+	return true
+}
+
 // storeGetNote retrieves a note by ID.
 // Args: [id string]
 // Returns: Note JSON or null
@@ -932,6 +1741,37 @@ func storeListNotes(this js.Value, args []js.Value) interface{} {
 	return string(bytes)
 }
 
+// storeSearchNotes runs a persisted full-text (FTS5) query over notes.
+// Args: [query string, scopeJSON string (optional: worldId, narrativeId, folderId, history, limit)]
+// Returns: JSON array of results with snippets and match offsets
+func storeSearchNotes(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeSearchNotes requires 1+ args: query, [scopeJSON]")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var scope *store.NoteSearchScope
+	if len(args) > 1 && args[1].String() != "" && args[1].String() != "null" {
+		scope = &store.NoteSearchScope{}
+		if err := json.Unmarshal([]byte(args[1].String()), scope); err != nil {
+			return errorResult("invalid scope json: " + err.Error())
+		}
+	}
+
+	results, err := sqlStore.SearchNotes(args[0].String(), scope)
+	if err != nil {
+		return errorResult("search failed: " + err.Error())
+	}
+	if results == nil {
+		results = []*store.NoteSearchResult{}
+	}
+
+	bytes, _ := json.Marshal(results)
+	return string(bytes)
+}
+
 // storeUpsertEntity inserts or updates an entity.
 // Args: [entityJSON string]
 func storeUpsertEntity(this js.Value, args []js.Value) interface{} {
@@ -1039,6 +1879,400 @@ func storeListEntities(this js.Value, args []js.Value) interface{} {
 	return string(bytes)
 }
 
+// storeGetEntityAttributes returns an entity's typed attributes.
+// Args: [entityId string]
+// Returns: JSON array of {entityId, key, type, value}
+func storeGetEntityAttributes(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeGetEntityAttributes requires 1 arg: entityId")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	attrs, err := sqlStore.GetEntityAttributes(args[0].String())
+	if err != nil {
+		return errorResult("get failed: " + err.Error())
+	}
+	if attrs == nil {
+		attrs = []*store.EntityAttribute{}
+	}
+
+	bytes, _ := json.Marshal(attrs)
+	return string(bytes)
+}
+
+// =============================================================================
+// Narrative lexicon
+// =============================================================================
+
+// loadLexicon compiles a world's custom verbs into the verb matcher.
+// Global verbs (worldId "") always apply; the world's own override them.
+// Args: [worldId string]
+func loadLexicon(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("loadLexicon requires 1 arg: worldId")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if pipeline == nil {
+		return errorResult("conductor not initialized")
+	}
+
+	lexiconWorld = args[0].String()
+	if err := reloadLexicon(); err != nil {
+		return errorResult("load failed: " + err.Error())
+	}
+	return successResult("lexicon loaded")
+}
+
+// storeSetLexicon replaces a world's custom verbs.
+// Args: [worldId string, lexiconJSON string] - JSON array of {verb, event, relation, transitivity}
+func storeSetLexicon(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return errorResult("storeSetLexicon requires 2 args: worldId, lexiconJSON")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var verbs []*store.LexiconVerb
+	if err := json.Unmarshal([]byte(args[1].String()), &verbs); err != nil {
+		return errorResult("invalid lexicon json: " + err.Error())
+	}
+	for _, v := range verbs {
+		if err := lexiconEntry(v).Validate(); err != nil {
+			return errorResult(err.Error())
+		}
+	}
+
+	worldID := args[0].String()
+	if err := sqlStore.SetLexicon(worldID, verbs); err != nil {
+		return errorResult("set failed: " + err.Error())
+	}
+	return lexiconChanged(worldID, "lexicon set")
+}
+
+// storeUpsertLexiconVerb adds or replaces one custom verb.
+// Args: [verbJSON string] - {worldId, verb, event, relation, transitivity}
+func storeUpsertLexiconVerb(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeUpsertLexiconVerb requires 1 arg: verbJSON")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var verb store.LexiconVerb
+	if err := json.Unmarshal([]byte(args[0].String()), &verb); err != nil {
+		return errorResult("invalid verb json: " + err.Error())
+	}
+	if err := lexiconEntry(&verb).Validate(); err != nil {
+		return errorResult(err.Error())
+	}
+
+	if err := sqlStore.UpsertLexiconVerb(&verb); err != nil {
+		return errorResult("upsert failed: " + err.Error())
+	}
+	return lexiconChanged(verb.WorldID, "verb upserted")
+}
+
+// storeDeleteLexiconVerb removes one custom verb.
+// Args: [worldId string, verb string]
+func storeDeleteLexiconVerb(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return errorResult("storeDeleteLexiconVerb requires 2 args: worldId, verb")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	worldID := args[0].String()
+	if err := sqlStore.DeleteLexiconVerb(worldID, args[1].String()); err != nil {
+		return errorResult("delete failed: " + err.Error())
+	}
+	return lexiconChanged(worldID, "verb deleted")
+}
+
+// storeListLexicon returns a world's custom verbs.
+// Args: [worldId string]
+// Returns: JSON array of LexiconVerb
+func storeListLexicon(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeListLexicon requires 1 arg: worldId")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	verbs, err := sqlStore.ListLexiconVerbs(args[0].String())
+	if err != nil {
+		return errorResult("list failed: " + err.Error())
+	}
+	if verbs == nil {
+		verbs = []*store.LexiconVerb{}
+	}
+
+	bytes, _ := json.Marshal(verbs)
+	return string(bytes)
+}
+
+// reloadLexicon compiles the global and current-world verbs into the matcher
+// and drops cached scans, which were made with the old lexicon
+func reloadLexicon() error {
+	entries := make(map[string]narrative.LexiconEntry)
+	var order []string
+	for _, worldID := range []string{"", lexiconWorld} {
+		verbs, err := sqlStore.ListLexiconVerbs(worldID)
+		if err != nil {
+			return err
+		}
+		for _, v := range verbs {
+			if _, ok := entries[v.Verb]; !ok {
+				order = append(order, v.Verb)
+			}
+			entries[v.Verb] = lexiconEntry(v)
+		}
+		if lexiconWorld == "" {
+			break
+		}
+	}
+
+	lexicon := make([]narrative.LexiconEntry, len(order))
+	for i, verb := range order {
+		lexicon[i] = entries[verb]
+	}
+	if err := pipeline.GetMatcher().LoadLexicon(lexicon); err != nil {
+		return err
+	}
+	if scanCache != nil {
+		scanCache.Clear()
+	}
+	fmt.Println("[GoKitt] ✅ Narrative lexicon loaded:", len(lexicon), "verbs")
+	return nil
+}
+
+// lexiconChanged recompiles the matcher if worldID's verbs are in use
+func lexiconChanged(worldID, message string) interface{} {
+	if pipeline != nil && (worldID == "" || worldID == lexiconWorld) {
+		if err := reloadLexicon(); err != nil {
+			return errorResult("saved, but reload failed: " + err.Error())
+		}
+	}
+	return successResult(message)
+}
+
+func lexiconEntry(v *store.LexiconVerb) narrative.LexiconEntry {
+	return narrative.LexiconEntry{
+		Verb:         v.Verb,
+		Event:        v.Event,
+		Relation:     v.Relation,
+		Transitivity: v.Transitivity,
+	}
+}
+
+// =============================================================================
+// Discovery feedback - Persisted candidates and accept/reject decisions
+// =============================================================================
+
+// acceptCandidate turns a discovery candidate into an entity: it is stored,
+// added to the implicit dictionary, and promoted in every scan session.
+// An entity with the same label in the same narrative is reused.
+// Args: [narrativeId string ("" for the default session), token string, kind string (optional, defaults to the inferred kind)]
+// Returns: Entity JSON
+func acceptCandidate(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return errorResult("acceptCandidate requires 2 args: narrativeId, token")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	narrativeID := args[0].String()
+	session := pipeline.Session(narrativeID)
+	candidate, ok := session.GetCandidate(args[1].String())
+	if !ok {
+		return errorResult("unknown candidate: " + args[1].String())
+	}
+	kind := candidate.Kind
+	if len(args) > 2 && args[2].String() != "" && args[2].String() != "null" {
+		kind = args[2].String()
+	}
+	if kind == "" || kind == "UNKNOWN" {
+		return errorResult("candidate " + candidate.Token + " has no inferred kind; pass one")
+	}
+
+	entity, err := sqlStore.GetEntityByLabelInNarrative(candidate.Token, narrativeID)
+	if err != nil {
+		return errorResult("lookup failed: " + err.Error())
+	}
+	now := time.Now().UnixMilli()
+	if entity == nil {
+		entity = &store.Entity{
+			ID:            newEntityID(),
+			Label:         candidate.Token,
+			FirstNote:     candidate.FirstNote,
+			TotalMentions: candidate.Count,
+			NarrativeID:   narrativeID,
+			CreatedBy:     "user",
+			CreatedAt:     now,
+		}
+	}
+	entity.Kind = implicitmatcher.ParseKind(kind).String()
+	entity.UpdatedAt = now
+	if err := sqlStore.UpsertEntity(entity); err != nil {
+		return errorResult("upsert failed: " + err.Error())
+	}
+
+	// Compiling the dictionary also seeds discovery, promoting the name everywhere
+	err = registerDictionaryEntity(implicitmatcher.RegisteredEntity{
+		ID:          entity.ID,
+		Label:       entity.Label,
+		Aliases:     entity.Aliases,
+		Kind:        entity.Kind,
+		NarrativeID: entity.NarrativeID,
+	})
+	if err != nil {
+		return errorResult("dictionary compile failed: " + err.Error())
+	}
+	if err := saveDiscovery(session); err != nil {
+		return errorResult("accepted, but save failed: " + err.Error())
+	}
+
+	bytes, _ := json.Marshal(entity)
+	return string(bytes)
+}
+
+// rejectCandidate marks a token as not an entity for the current world:
+// discovery ignores it in every session, now and after a reload.
+// Args: [token string]
+func rejectCandidate(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("rejectCandidate requires 1 arg: token")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+	if pipeline == nil {
+		return errorResult("pipeline not initialized")
+	}
+
+	token := args[0].String()
+	if _, _, valid := discovery.Canonicalize(token); !valid {
+		return errorResult("invalid token: " + token)
+	}
+	stopword := &store.DiscoveryStopword{WorldID: discoveryWorld, Word: token, CreatedAt: time.Now().UnixMilli()}
+	if err := sqlStore.AddDiscoveryStopword(stopword); err != nil {
+		return errorResult("save failed: " + err.Error())
+	}
+	pipeline.RejectCandidates(token)
+	return successResult("rejected " + token)
+}
+
+// storeListDiscoveryStopwords returns a world's rejected words.
+// Args: [worldId string]
+// Returns: JSON array of DiscoveryStopword
+func storeListDiscoveryStopwords(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		return errorResult("storeListDiscoveryStopwords requires 1 arg: worldId")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	words, err := sqlStore.ListDiscoveryStopwords(args[0].String())
+	if err != nil {
+		return errorResult("list failed: " + err.Error())
+	}
+	if words == nil {
+		words = []*store.DiscoveryStopword{}
+	}
+
+	bytes, _ := json.Marshal(words)
+	return string(bytes)
+}
+
+// reloadDiscovery restores the current world's saved candidates into their
+// sessions, then applies its rejected words to every session
+func reloadDiscovery() error {
+	saved, err := sqlStore.ListDiscoveryCandidates(discoveryWorld)
+	if err != nil {
+		return err
+	}
+	byNarrative := make(map[string][]discovery.Candidate)
+	for _, c := range saved {
+		byNarrative[c.NarrativeID] = append(byNarrative[c.NarrativeID], discovery.Candidate{
+			Token:     c.Token,
+			Count:     c.Count,
+			Status:    c.Status,
+			Kind:      c.Kind,
+			Titles:    c.Titles,
+			PartOf:    c.PartOf,
+			FirstNote: c.FirstNote,
+			Contexts:  c.Contexts,
+		})
+	}
+	for narrativeID, candidates := range byNarrative {
+		pipeline.Session(narrativeID).RestoreCandidates(candidates)
+	}
+
+	stopwords, err := sqlStore.ListDiscoveryStopwords(discoveryWorld)
+	if err != nil {
+		return err
+	}
+	words := make([]string, len(stopwords))
+	for i, w := range stopwords {
+		words[i] = w.Word
+	}
+	pipeline.RejectCandidates(words...)
+
+	fmt.Println("[GoKitt] ✅ Discovery registry loaded:", len(saved), "candidates,", len(words), "rejected")
+	return nil
+}
+
+// saveDiscovery writes the session's candidates changed since the last save
+// to the store, if it is up
+func saveDiscovery(session *conductor.ScanSession) error {
+	if sqlStore == nil {
+		return nil
+	}
+	candidates := session.TakeChangedCandidates()
+	if len(candidates) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	rows := make([]*store.DiscoveryCandidate, len(candidates))
+	for i, c := range candidates {
+		kind := c.Kind
+		if kind == "UNKNOWN" {
+			kind = ""
+		}
+		rows[i] = &store.DiscoveryCandidate{
+			Token:     c.Token,
+			Count:     c.Count,
+			Status:    c.Status,
+			Kind:      kind,
+			FirstNote: c.FirstNote,
+			Contexts:  c.Contexts,
+			Titles:    c.Titles,
+			PartOf:    c.PartOf,
+			UpdatedAt: now,
+		}
+	}
+	return sqlStore.UpsertDiscoveryCandidates(discoveryWorld, session.NarrativeID(), rows)
+}
+
+// newEntityID returns a random ID for entities created from discovery
+func newEntityID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "entity-" + hex.EncodeToString(b)
+}
+
 // storeUpsertEdge inserts or updates an edge.
 // Args: [edgeJSON string]
 func storeUpsertEdge(this js.Value, args []js.Value) interface{} {
@@ -1257,6 +2491,80 @@ func storeListFolders(this js.Value, args []js.Value) interface{} {
 	return string(bytes)
 }
 
+// storeUpsertEmbedding stores the vector for a note, entity or memory.
+// Args: [kind string ("note"|"entity"|"memory"), id string, vectorJSON string, narrativeID string (optional)]
+func storeUpsertEmbedding(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return errorResult("storeUpsertEmbedding requires 3+ args: kind, id, vectorJSON, [narrativeID]")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var vector []float32
+	if err := json.Unmarshal([]byte(args[2].String()), &vector); err != nil {
+		return errorResult("invalid vector json: " + err.Error())
+	}
+	var narrativeID string
+	if len(args) > 3 && args[3].String() != "null" {
+		narrativeID = args[3].String()
+	}
+
+	kind := store.EmbeddingKind(args[0].String())
+	if err := sqlStore.UpsertEmbedding(kind, args[1].String(), narrativeID, vector); err != nil {
+		return errorResult("upsert failed: " + err.Error())
+	}
+	return successResult("upserted " + args[1].String())
+}
+
+// storeDeleteEmbedding removes the vector for an item.
+// Args: [kind string, id string]
+func storeDeleteEmbedding(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return errorResult("storeDeleteEmbedding requires 2 args: kind, id")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	if err := sqlStore.DeleteEmbedding(store.EmbeddingKind(args[0].String()), args[1].String()); err != nil {
+		return errorResult("delete failed: " + err.Error())
+	}
+	return successResult("deleted")
+}
+
+// storeSearchEmbeddings runs a KNN query over one embedding table.
+// Args: [kind string, vectorJSON string, k int, narrativeID string (optional)]
+// Returns: JSON array of {id, distance}, closest first
+func storeSearchEmbeddings(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return errorResult("storeSearchEmbeddings requires 3+ args: kind, vectorJSON, k, [narrativeID]")
+	}
+	if sqlStore == nil {
+		return errorResult("store not initialized")
+	}
+
+	var vector []float32
+	if err := json.Unmarshal([]byte(args[1].String()), &vector); err != nil {
+		return errorResult("invalid vector json: " + err.Error())
+	}
+	var narrativeID string
+	if len(args) > 3 && args[3].String() != "null" {
+		narrativeID = args[3].String()
+	}
+
+	matches, err := sqlStore.SearchEmbeddings(store.EmbeddingKind(args[0].String()), vector, args[2].Int(), narrativeID)
+	if err != nil {
+		return errorResult("search failed: " + err.Error())
+	}
+	if matches == nil {
+		matches = []store.VectorMatch{}
+	}
+
+	bytes, _ := json.Marshal(matches)
+	return string(bytes)
+}
+
 // =============================================================================
 // Phase 3: Graph Merger API
 // =============================================================================
@@ -1268,7 +2576,8 @@ func mergerInit(this js.Value, args []js.Value) interface{} {
 	return successResult("Merger initialized")
 }
 
-// mergerAddScanner adds edges from a scanner graph result
+// mergerAddScanner adds edges from a scanner graph result, replacing any
+// earlier scanner contribution from the same note
 // Args: [noteId string, graphJSON string]
 func mergerAddScanner(this js.Value, args []js.Value) interface{} {
 	if graphMerger == nil {
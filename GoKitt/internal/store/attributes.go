@@ -0,0 +1,87 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// entityAttributesSchema holds typed attributes, one row per entity and key.
+// Values are JSON so lists and objects round-trip unchanged.
+const entityAttributesSchema = `
+CREATE TABLE IF NOT EXISTS entity_attributes (
+    entity_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value_type TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (entity_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_attributes_key ON entity_attributes(key);
+`
+
+// SetEntityAttributes replaces every attribute of an entity.
+// An empty slice clears them.
+func (s *SQLiteStore) SetEntityAttributes(entityID string, attrs []*EntityAttribute) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM entity_attributes WHERE entity_id = ?", entityID); err != nil {
+		return err
+	}
+	for _, a := range attrs {
+		if err := insertEntityAttribute(tx, entityID, a); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetEntityAttributes returns an entity's attributes sorted by key.
+func (s *SQLiteStore) GetEntityAttributes(entityID string) ([]*EntityAttribute, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return queryEntityAttributes(s.db, "WHERE entity_id = ? ORDER BY key", entityID)
+}
+
+// Helpers
+
+func insertEntityAttribute(db sqlExecutor, entityID string, a *EntityAttribute) error {
+	value, err := json.Marshal(a.Value)
+	if err != nil {
+		return fmt.Errorf("attribute %s: %w", a.Key, err)
+	}
+	_, err = db.Exec(`
+		INSERT INTO entity_attributes (entity_id, key, value_type, value) VALUES (?, ?, ?, ?)
+		ON CONFLICT(entity_id, key) DO UPDATE SET value_type = excluded.value_type, value = excluded.value
+	`, entityID, a.Key, a.Type, string(value))
+	return err
+}
+
+func queryEntityAttributes(db sqlExecutor, where string, args ...any) ([]*EntityAttribute, error) {
+	rows, err := db.Query("SELECT entity_id, key, value_type, value FROM entity_attributes "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attrs []*EntityAttribute
+	for rows.Next() {
+		var a EntityAttribute
+		var value string
+		if err := rows.Scan(&a.EntityID, &a.Key, &a.Type, &value); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(value), &a.Value); err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", a.Key, err)
+		}
+		attrs = append(attrs, &a)
+	}
+	return attrs, rows.Err()
+}
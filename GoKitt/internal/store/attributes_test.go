@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityAttributes_TypedRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.UpsertEntity(&Entity{ID: "e1", Label: "Frodo", Kind: "CHARACTER", CreatedAt: 1, UpdatedAt: 1}))
+
+	require.NoError(t, store.SetEntityAttributes("e1", []*EntityAttribute{
+		{Key: "status", Type: "string", Value: "alive"},
+		{Key: "age", Type: "number", Value: 50.0},
+		{Key: "ringbearer", Type: "bool", Value: true},
+		{Key: "born", Type: "date", Value: "2968-09-22"},
+		{Key: "companions", Type: "list", Value: []any{"Sam", "Merry"}},
+	}))
+
+	attrs, err := store.GetEntityAttributes("e1")
+	require.NoError(t, err)
+	require.Len(t, attrs, 5)
+
+	byKey := make(map[string]*EntityAttribute)
+	for _, a := range attrs {
+		assert.Equal(t, "e1", a.EntityID)
+		byKey[a.Key] = a
+	}
+	assert.Equal(t, "age", attrs[0].Key, "sorted by key")
+	assert.Equal(t, 50.0, byKey["age"].Value)
+	assert.Equal(t, true, byKey["ringbearer"].Value)
+	assert.Equal(t, "date", byKey["born"].Type)
+	assert.Equal(t, []any{"Sam", "Merry"}, byKey["companions"].Value)
+
+	// Set replaces the whole bag
+	require.NoError(t, store.SetEntityAttributes("e1", []*EntityAttribute{{Key: "status", Type: "string", Value: "departed"}}))
+	attrs, err = store.GetEntityAttributes("e1")
+	require.NoError(t, err)
+	require.Len(t, attrs, 1)
+	assert.Equal(t, "departed", attrs[0].Value)
+
+	// Unknown entity has none
+	attrs, err = store.GetEntityAttributes("missing")
+	require.NoError(t, err)
+	assert.Empty(t, attrs)
+}
+
+func TestEntityAttributes_DeletedWithEntity(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.UpsertEntity(&Entity{ID: "e1", Label: "Frodo", Kind: "CHARACTER", CreatedAt: 1, UpdatedAt: 1}))
+	require.NoError(t, store.SetEntityAttributes("e1", []*EntityAttribute{{Key: "status", Type: "string", Value: "alive"}}))
+
+	require.NoError(t, store.DeleteEntity("e1"))
+	attrs, err := store.GetEntityAttributes("e1")
+	require.NoError(t, err)
+	assert.Empty(t, attrs)
+}
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// discoverySchema holds each world's discovery registry: candidate names per
+// narrative, and the words the user rejected, which discovery never counts again.
+// Keys are lower-cased so a candidate keeps one row whatever its capitalization.
+const discoverySchema = `
+CREATE TABLE IF NOT EXISTS discovery_candidates (
+    world_id TEXT NOT NULL DEFAULT '',
+    narrative_id TEXT NOT NULL DEFAULT '',
+    key TEXT NOT NULL,
+    token TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    status INTEGER NOT NULL DEFAULT 0,
+    kind TEXT NOT NULL DEFAULT '',
+    first_note TEXT NOT NULL DEFAULT '',
+    contexts TEXT NOT NULL DEFAULT '[]',
+    titles TEXT NOT NULL DEFAULT '[]',
+    part_of TEXT NOT NULL DEFAULT '',
+    updated_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (world_id, narrative_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS discovery_stopwords (
+    world_id TEXT NOT NULL DEFAULT '',
+    word TEXT NOT NULL,
+    created_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (world_id, word)
+);
+`
+
+// SaveDiscoveryCandidates replaces the candidates of one narrative in a world.
+// An empty slice clears them.
+func (s *SQLiteStore) SaveDiscoveryCandidates(worldID, narrativeID string, candidates []*DiscoveryCandidate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM discovery_candidates WHERE world_id = ? AND narrative_id = ?", worldID, narrativeID); err != nil {
+		return err
+	}
+	for _, c := range candidates {
+		c.WorldID, c.NarrativeID = worldID, narrativeID
+		if err := insertDiscoveryCandidate(tx, c); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpsertDiscoveryCandidates inserts or updates candidates of one narrative in
+// a world, leaving its other candidates as they are.
+func (s *SQLiteStore) UpsertDiscoveryCandidates(worldID, narrativeID string, candidates []*DiscoveryCandidate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range candidates {
+		c.WorldID, c.NarrativeID = worldID, narrativeID
+		if err := insertDiscoveryCandidate(tx, c); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListDiscoveryCandidates returns a world's candidates across its narratives,
+// sorted by narrative and name.
+func (s *SQLiteStore) ListDiscoveryCandidates(worldID string) ([]*DiscoveryCandidate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return queryDiscoveryCandidates(s.db, "WHERE world_id = ? ORDER BY narrative_id, key", worldID)
+}
+
+// AddDiscoveryStopword records a rejected word for a world.
+func (s *SQLiteStore) AddDiscoveryStopword(stopword *DiscoveryStopword) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return insertDiscoveryStopword(s.db, stopword)
+}
+
+// DeleteDiscoveryStopword lets discovery count a rejected word again.
+func (s *SQLiteStore) DeleteDiscoveryStopword(worldID, word string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM discovery_stopwords WHERE world_id = ? AND word = ?", worldID, normalizePhrase(word))
+	return err
+}
+
+// ListDiscoveryStopwords returns a world's rejected words sorted by word.
+func (s *SQLiteStore) ListDiscoveryStopwords(worldID string) ([]*DiscoveryStopword, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return queryDiscoveryStopwords(s.db, "WHERE world_id = ? ORDER BY word", worldID)
+}
+
+// Helpers
+
+func insertDiscoveryCandidate(db sqlExecutor, c *DiscoveryCandidate) error {
+	contexts, _ := json.Marshal(nonNil(c.Contexts))
+	titles, _ := json.Marshal(nonNil(c.Titles))
+	_, err := db.Exec(`
+		INSERT INTO discovery_candidates (world_id, narrative_id, key, token, count, status, kind,
+			first_note, contexts, titles, part_of, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(world_id, narrative_id, key) DO UPDATE SET
+			token = excluded.token, count = excluded.count, status = excluded.status, kind = excluded.kind,
+			first_note = excluded.first_note, contexts = excluded.contexts, titles = excluded.titles,
+			part_of = excluded.part_of, updated_at = excluded.updated_at
+	`, c.WorldID, c.NarrativeID, strings.ToLower(c.Token), c.Token, c.Count, c.Status, c.Kind,
+		c.FirstNote, string(contexts), string(titles), c.PartOf, c.UpdatedAt)
+	return err
+}
+
+func queryDiscoveryCandidates(db sqlExecutor, where string, args ...any) ([]*DiscoveryCandidate, error) {
+	rows, err := db.Query(`
+		SELECT world_id, narrative_id, token, count, status, kind, first_note, contexts, titles, part_of, updated_at
+		FROM discovery_candidates `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*DiscoveryCandidate
+	for rows.Next() {
+		var c DiscoveryCandidate
+		var contexts, titles string
+		if err := rows.Scan(&c.WorldID, &c.NarrativeID, &c.Token, &c.Count, &c.Status, &c.Kind,
+			&c.FirstNote, &contexts, &titles, &c.PartOf, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(contexts), &c.Contexts)
+		json.Unmarshal([]byte(titles), &c.Titles)
+		candidates = append(candidates, &c)
+	}
+	return candidates, rows.Err()
+}
+
+func insertDiscoveryStopword(db sqlExecutor, w *DiscoveryStopword) error {
+	w.Word = normalizePhrase(w.Word)
+	_, err := db.Exec(`
+		INSERT INTO discovery_stopwords (world_id, word, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(world_id, word) DO NOTHING
+	`, w.WorldID, w.Word, w.CreatedAt)
+	return err
+}
+
+func queryDiscoveryStopwords(db sqlExecutor, where string, args ...any) ([]*DiscoveryStopword, error) {
+	rows, err := db.Query("SELECT world_id, word, created_at FROM discovery_stopwords "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []*DiscoveryStopword
+	for rows.Next() {
+		var w DiscoveryStopword
+		if err := rows.Scan(&w.WorldID, &w.Word, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		words = append(words, &w)
+	}
+	return words, rows.Err()
+}
+
+// nonNil stores an empty list as [] rather than null
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveryCandidates_PerNarrative(t *testing.T) {
+	store := newTestStore(t)
+
+	require.NoError(t, store.SaveDiscoveryCandidates("w1", "n1", []*DiscoveryCandidate{
+		{Token: "Mira Ashdown", Count: 3, Status: 1, Kind: "CHARACTER", FirstNote: "note-1",
+			Contexts: []string{"Lady Mira Ashdown rode north."}, Titles: []string{"Lady"}},
+		{Token: "Ashdown", Count: 1, PartOf: "Mira Ashdown"},
+	}))
+	require.NoError(t, store.SaveDiscoveryCandidates("w1", "n2", []*DiscoveryCandidate{{Token: "Iron Citadel", Count: 2}}))
+	require.NoError(t, store.SaveDiscoveryCandidates("w2", "n1", []*DiscoveryCandidate{{Token: "Varen", Count: 1}}))
+
+	candidates, err := store.ListDiscoveryCandidates("w1")
+	require.NoError(t, err)
+	require.Len(t, candidates, 3)
+	assert.Equal(t, "Ashdown", candidates[0].Token)
+	assert.Empty(t, candidates[0].Contexts)
+	mira := candidates[1]
+	assert.Equal(t, "n1", mira.NarrativeID)
+	assert.Equal(t, "CHARACTER", mira.Kind)
+	assert.Equal(t, []string{"Lady Mira Ashdown rode north."}, mira.Contexts)
+	assert.Equal(t, []string{"Lady"}, mira.Titles)
+	assert.Equal(t, "Iron Citadel", candidates[2].Token)
+
+	// Saving replaces one narrative only
+	require.NoError(t, store.SaveDiscoveryCandidates("w1", "n1", nil))
+	candidates, err = store.ListDiscoveryCandidates("w1")
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "n2", candidates[0].NarrativeID)
+}
+
+func TestDiscoveryCandidates_Upsert(t *testing.T) {
+	store := newTestStore(t)
+
+	require.NoError(t, store.SaveDiscoveryCandidates("w1", "n1", []*DiscoveryCandidate{
+		{Token: "Mira Ashdown", Count: 1},
+		{Token: "Iron Citadel", Count: 2},
+	}))
+	// Only the changed candidate is written; the other stays
+	require.NoError(t, store.UpsertDiscoveryCandidates("w1", "n1", []*DiscoveryCandidate{{Token: "Mira Ashdown", Count: 3, Status: 1}}))
+
+	candidates, err := store.ListDiscoveryCandidates("w1")
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "Iron Citadel", candidates[0].Token)
+	assert.Equal(t, 2, candidates[0].Count)
+	assert.Equal(t, "Mira Ashdown", candidates[1].Token)
+	assert.Equal(t, 3, candidates[1].Count)
+	assert.Equal(t, 1, candidates[1].Status)
+}
+
+func TestDiscoveryStopwords_PerWorld(t *testing.T) {
+	store := newTestStore(t)
+
+	require.NoError(t, store.AddDiscoveryStopword(&DiscoveryStopword{WorldID: "w1", Word: "Dread  Lord", CreatedAt: 1}))
+	require.NoError(t, store.AddDiscoveryStopword(&DiscoveryStopword{WorldID: "w1", Word: "dread lord", CreatedAt: 2}))
+	require.NoError(t, store.AddDiscoveryStopword(&DiscoveryStopword{WorldID: "w2", Word: "Anyway"}))
+
+	words, err := store.ListDiscoveryStopwords("w1")
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	assert.Equal(t, "dread lord", words[0].Word)
+	assert.Equal(t, int64(1), words[0].CreatedAt)
+
+	require.NoError(t, store.DeleteDiscoveryStopword("w1", "Dread Lord"))
+	words, err = store.ListDiscoveryStopwords("w1")
+	require.NoError(t, err)
+	assert.Empty(t, words)
+
+	other, err := store.ListDiscoveryStopwords("w2")
+	require.NoError(t, err)
+	assert.Len(t, other, 1)
+}
@@ -0,0 +1,608 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// ExportFormatVersion is the version of the JSON layout written by Export.
+//
+//	0/1: current note versions, entities, edges and folders only (unversioned)
+//	2:   every table, including note history, threads, messages and memories
+//	3:   adds sqlite-vec embeddings
+//	4:   adds entity attributes
+//	5:   adds the narrative lexicon
+//	6:   adds discovery candidates and stopwords
+const ExportFormatVersion = 6
+
+// ExportData is the portable JSON form of the whole database.
+type ExportData struct {
+	FormatVersion int `json:"formatVersion"`
+	SchemaVersion int `json:"schemaVersion"`
+
+	Notes    []*Note   `json:"notes"` // Every version, not just current
+	Entities []*Entity `json:"entities"`
+	Edges    []*Edge   `json:"edges"`
+	Folders  []*Folder `json:"folders"`
+
+	EntityAttributes []*EntityAttribute `json:"entityAttributes,omitempty"`
+	Lexicon          []*LexiconVerb     `json:"lexicon,omitempty"`
+
+	DiscoveryCandidates []*DiscoveryCandidate `json:"discoveryCandidates,omitempty"`
+	DiscoveryStopwords  []*DiscoveryStopword  `json:"discoveryStopwords,omitempty"`
+
+	Threads        []*Thread        `json:"threads,omitempty"`
+	ThreadMessages []*ThreadMessage `json:"threadMessages,omitempty"`
+	Memories       []*Memory        `json:"memories,omitempty"`
+	MemoryThreads  []*MemoryThread  `json:"memoryThreads,omitempty"`
+
+	SearchIndexes []*SearchIndex `json:"searchIndexes,omitempty"`
+	Embeddings    []*Embedding   `json:"embeddings,omitempty"`
+}
+
+// exportTables lists every table covered by Export, in dependency-safe insert order.
+var exportTables = []string{
+	"notes", "entities", "entity_attributes", "edges", "folders",
+	"threads", "thread_messages", "memories", "memory_threads",
+	"search_indexes", "narrative_lexicon", "discovery_candidates", "discovery_stopwords",
+}
+
+// Export serializes all database tables to JSON bytes.
+// This is a portable export that doesn't depend on sqlite3 serialization APIs.
+func (s *SQLiteStore) Export() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data := ExportData{
+		FormatVersion: ExportFormatVersion,
+		SchemaVersion: SchemaVersion,
+	}
+
+	// Read everything from one snapshot
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("export begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	if data.Notes, err = exportNotes(tx); err != nil {
+		return nil, err
+	}
+	if data.Entities, err = exportEntities(tx); err != nil {
+		return nil, err
+	}
+	if data.EntityAttributes, err = queryEntityAttributes(tx, "ORDER BY entity_id, key"); err != nil {
+		return nil, fmt.Errorf("export entity attributes: %w", err)
+	}
+	if data.Lexicon, err = queryLexiconVerbs(tx, "ORDER BY world_id, verb"); err != nil {
+		return nil, fmt.Errorf("export lexicon: %w", err)
+	}
+	if data.DiscoveryCandidates, err = queryDiscoveryCandidates(tx, "ORDER BY world_id, narrative_id, key"); err != nil {
+		return nil, fmt.Errorf("export discovery candidates: %w", err)
+	}
+	if data.DiscoveryStopwords, err = queryDiscoveryStopwords(tx, "ORDER BY world_id, word"); err != nil {
+		return nil, fmt.Errorf("export discovery stopwords: %w", err)
+	}
+	if data.Edges, err = exportEdges(tx); err != nil {
+		return nil, err
+	}
+	if data.Folders, err = exportFolders(tx); err != nil {
+		return nil, err
+	}
+	if data.Threads, err = exportThreads(tx); err != nil {
+		return nil, err
+	}
+	if data.ThreadMessages, err = exportThreadMessages(tx); err != nil {
+		return nil, err
+	}
+	if data.Memories, err = exportMemories(tx); err != nil {
+		return nil, err
+	}
+	if data.MemoryThreads, err = exportMemoryThreads(tx); err != nil {
+		return nil, err
+	}
+	if data.SearchIndexes, err = exportSearchIndexes(tx); err != nil {
+		return nil, err
+	}
+	if data.Embeddings, err = exportEmbeddings(tx); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(data)
+}
+
+// Import restores the database state from an exported JSON byte slice.
+// Clears all existing data and re-inserts from the export inside a single
+// transaction; any failure rolls back and leaves the store untouched.
+// Exports from before ExportFormatVersion 2 are accepted (current notes only).
+func (s *SQLiteStore) Import(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	var importData ExportData
+	if err := json.Unmarshal(data, &importData); err != nil {
+		return fmt.Errorf("import unmarshal: %w", err)
+	}
+	if importData.FormatVersion > ExportFormatVersion {
+		return fmt.Errorf("import: export format %d is newer than supported %d",
+			importData.FormatVersion, ExportFormatVersion)
+	}
+	if importData.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("import: schema version %d is newer than supported %d",
+			importData.SchemaVersion, SchemaVersion)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("import begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Clear all tables
+	for _, table := range exportTables {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	// vec0 tables are dropped outright: the import may use another dimension
+	for _, kind := range embeddingKinds {
+		if err := dropEmbeddingTable(tx, kind); err != nil {
+			return err
+		}
+	}
+
+	if err := importRows(tx, &importData); err != nil {
+		return err
+	}
+
+	// Bring data from an older schema forward (exports before versioning are schema 1)
+	from := importData.SchemaVersion
+	if from == 0 {
+		from = 1
+	}
+	if err := applyMigrations(tx, from); err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("import commit: %w", err)
+	}
+	return nil
+}
+
+// =============================================================================
+// Export readers
+// =============================================================================
+
+func exportNotes(tx *sql.Tx) ([]*Note, error) {
+	rows, err := tx.Query(`
+		SELECT id, version, world_id, title, content, COALESCE(markdown_content, ''),
+			   COALESCE(folder_id, ''), COALESCE(entity_kind, ''), COALESCE(entity_subtype, ''),
+			   is_entity, is_pinned, favorite, COALESCE(owner_id, ''), COALESCE(narrative_id, ''),
+			   COALESCE("order", 0), created_at, updated_at, valid_from, valid_to, is_current,
+			   COALESCE(change_reason, '')
+		FROM notes ORDER BY id, version
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*Note
+	for rows.Next() {
+		var n Note
+		var isEntity, isPinned, favorite, isCurrent int
+		var validTo sql.NullInt64
+		if err := rows.Scan(
+			&n.ID, &n.Version, &n.WorldID, &n.Title, &n.Content, &n.MarkdownContent,
+			&n.FolderID, &n.EntityKind, &n.EntitySubtype,
+			&isEntity, &isPinned, &favorite, &n.OwnerID, &n.NarrativeID,
+			&n.Order, &n.CreatedAt, &n.UpdatedAt, &n.ValidFrom, &validTo, &isCurrent,
+			&n.ChangeReason,
+		); err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		n.IsEntity = isEntity != 0
+		n.IsPinned = isPinned != 0
+		n.Favorite = favorite != 0
+		n.IsCurrent = isCurrent != 0
+		if validTo.Valid {
+			n.ValidTo = &validTo.Int64
+		}
+		notes = append(notes, &n)
+	}
+	return notes, rows.Err()
+}
+
+func exportEntities(tx *sql.Tx) ([]*Entity, error) {
+	rows, err := tx.Query(`
+		SELECT id, label, kind, COALESCE(subtype, ''), COALESCE(aliases, ''), COALESCE(first_note, ''),
+			   total_mentions, created_at, updated_at, COALESCE(created_by, ''), COALESCE(narrative_id, '')
+		FROM entities ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export entities: %w", err)
+	}
+	defer rows.Close()
+
+	var entities []*Entity
+	for rows.Next() {
+		var e Entity
+		var aliasesJSON string
+		if err := rows.Scan(
+			&e.ID, &e.Label, &e.Kind, &e.Subtype, &aliasesJSON,
+			&e.FirstNote, &e.TotalMentions, &e.CreatedAt, &e.UpdatedAt,
+			&e.CreatedBy, &e.NarrativeID,
+		); err != nil {
+			return nil, fmt.Errorf("scan entity: %w", err)
+		}
+		if aliasesJSON != "" {
+			json.Unmarshal([]byte(aliasesJSON), &e.Aliases)
+		}
+		entities = append(entities, &e)
+	}
+	return entities, rows.Err()
+}
+
+func exportEdges(tx *sql.Tx) ([]*Edge, error) {
+	rows, err := tx.Query(`
+		SELECT id, source_id, target_id, rel_type, confidence, bidirectional,
+			   COALESCE(source_note, ''), created_at
+		FROM edges ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export edges: %w", err)
+	}
+	defer rows.Close()
+
+	var edges []*Edge
+	for rows.Next() {
+		var e Edge
+		var bidir int
+		if err := rows.Scan(
+			&e.ID, &e.SourceID, &e.TargetID, &e.RelType, &e.Confidence,
+			&bidir, &e.SourceNote, &e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan edge: %w", err)
+		}
+		e.Bidirectional = bidir == 1
+		edges = append(edges, &e)
+	}
+	return edges, rows.Err()
+}
+
+func exportFolders(tx *sql.Tx) ([]*Folder, error) {
+	rows, err := tx.Query(`
+		SELECT id, name, COALESCE(parent_id, ''), world_id, COALESCE(narrative_id, ''),
+			   folder_order, created_at, updated_at
+		FROM folders ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export folders: %w", err)
+	}
+	defer rows.Close()
+
+	var folders []*Folder
+	for rows.Next() {
+		var f Folder
+		if err := rows.Scan(
+			&f.ID, &f.Name, &f.ParentID, &f.WorldID, &f.NarrativeID,
+			&f.FolderOrder, &f.CreatedAt, &f.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan folder: %w", err)
+		}
+		folders = append(folders, &f)
+	}
+	return folders, rows.Err()
+}
+
+func exportThreads(tx *sql.Tx) ([]*Thread, error) {
+	rows, err := tx.Query(`
+		SELECT id, COALESCE(world_id, ''), COALESCE(narrative_id, ''), COALESCE(title, ''),
+			   created_at, updated_at
+		FROM threads ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export threads: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*Thread
+	for rows.Next() {
+		var t Thread
+		if err := rows.Scan(&t.ID, &t.WorldID, &t.NarrativeID, &t.Title, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan thread: %w", err)
+		}
+		threads = append(threads, &t)
+	}
+	return threads, rows.Err()
+}
+
+func exportThreadMessages(tx *sql.Tx) ([]*ThreadMessage, error) {
+	rows, err := tx.Query(`
+		SELECT id, thread_id, role, content, COALESCE(narrative_id, ''), created_at,
+			   COALESCE(updated_at, 0), is_streaming
+		FROM thread_messages ORDER BY thread_id, created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export thread messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*ThreadMessage
+	for rows.Next() {
+		var m ThreadMessage
+		var isStreaming int
+		if err := rows.Scan(
+			&m.ID, &m.ThreadID, &m.Role, &m.Content, &m.NarrativeID,
+			&m.CreatedAt, &m.UpdatedAt, &isStreaming,
+		); err != nil {
+			return nil, fmt.Errorf("scan thread message: %w", err)
+		}
+		m.IsStreaming = isStreaming != 0
+		messages = append(messages, &m)
+	}
+	return messages, rows.Err()
+}
+
+func exportMemories(tx *sql.Tx) ([]*Memory, error) {
+	rows, err := tx.Query(`
+		SELECT id, content, memory_type, confidence, COALESCE(source_role, ''),
+			   COALESCE(entity_id, ''), created_at, updated_at
+		FROM memories ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []*Memory
+	for rows.Next() {
+		var m Memory
+		var memoryType string
+		if err := rows.Scan(
+			&m.ID, &m.Content, &memoryType, &m.Confidence, &m.SourceRole,
+			&m.EntityID, &m.CreatedAt, &m.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan memory: %w", err)
+		}
+		m.MemoryType = MemoryType(memoryType)
+		memories = append(memories, &m)
+	}
+	return memories, rows.Err()
+}
+
+func exportMemoryThreads(tx *sql.Tx) ([]*MemoryThread, error) {
+	rows, err := tx.Query(`
+		SELECT memory_id, thread_id, COALESCE(message_id, ''), created_at
+		FROM memory_threads ORDER BY memory_id, thread_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("export memory threads: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*MemoryThread
+	for rows.Next() {
+		var mt MemoryThread
+		if err := rows.Scan(&mt.MemoryID, &mt.ThreadID, &mt.MessageID, &mt.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan memory thread: %w", err)
+		}
+		links = append(links, &mt)
+	}
+	return links, rows.Err()
+}
+
+func exportSearchIndexes(tx *sql.Tx) ([]*SearchIndex, error) {
+	rows, err := tx.Query("SELECT id, data, updated_at FROM search_indexes ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("export search indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []*SearchIndex
+	for rows.Next() {
+		var idx SearchIndex
+		if err := rows.Scan(&idx.ID, &idx.Data, &idx.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan search index: %w", err)
+		}
+		indexes = append(indexes, &idx)
+	}
+	return indexes, rows.Err()
+}
+
+func exportEmbeddings(tx *sql.Tx) ([]*Embedding, error) {
+	var embeddings []*Embedding
+	for _, kind := range embeddingKinds {
+		dims, err := embeddingDimensions(tx, kind)
+		if err != nil {
+			return nil, fmt.Errorf("export %s embeddings: %w", kind, err)
+		}
+		if dims == 0 {
+			continue
+		}
+		table, _ := embeddingTable(kind)
+		rows, err := tx.Query("SELECT id, narrative_id, embedding FROM " + table + " ORDER BY id")
+		if err != nil {
+			return nil, fmt.Errorf("export %s embeddings: %w", kind, err)
+		}
+		for rows.Next() {
+			e := Embedding{Kind: kind}
+			var blob []byte
+			if err := rows.Scan(&e.ID, &e.NarrativeID, &blob); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan %s embedding: %w", kind, err)
+			}
+			if e.Vector, err = deserializeFloat32(blob); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan %s embedding %s: %w", kind, e.ID, err)
+			}
+			embeddings = append(embeddings, &e)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return embeddings, nil
+}
+
+// =============================================================================
+// Import writers
+// =============================================================================
+
+// importRows inserts every table of an export into a cleared database.
+func importRows(tx *sql.Tx, data *ExportData) error {
+	// Notes: legacy exports only carry current versions without temporal fields
+	legacy := data.FormatVersion < 2
+	for _, n := range data.Notes {
+		version := n.Version
+		if version == 0 {
+			version = 1
+		}
+		validFrom := n.ValidFrom
+		if validFrom == 0 {
+			validFrom = n.CreatedAt
+		}
+		isCurrent := n.IsCurrent || legacy
+		validTo := n.ValidTo
+		if isCurrent {
+			validTo = nil
+		}
+		_, err := tx.Exec(`
+			INSERT INTO notes (id, version, world_id, title, content, markdown_content, folder_id, entity_kind,
+				entity_subtype, is_entity, is_pinned, favorite, owner_id, created_at, updated_at,
+				narrative_id, "order", valid_from, valid_to, is_current, change_reason)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, n.ID, version, n.WorldID, n.Title, n.Content, n.MarkdownContent, n.FolderID,
+			n.EntityKind, n.EntitySubtype, boolToInt(n.IsEntity), boolToInt(n.IsPinned),
+			boolToInt(n.Favorite), n.OwnerID, n.CreatedAt, n.UpdatedAt, n.NarrativeID, n.Order,
+			validFrom, validTo, boolToInt(isCurrent), n.ChangeReason)
+		if err != nil {
+			return fmt.Errorf("import note %s v%d: %w", n.ID, version, err)
+		}
+	}
+
+	for _, e := range data.Entities {
+		aliasesJSON, _ := json.Marshal(e.Aliases)
+		_, err := tx.Exec(`
+			INSERT INTO entities (id, label, kind, subtype, aliases, first_note, total_mentions,
+				created_at, updated_at, created_by, narrative_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, e.ID, e.Label, e.Kind, e.Subtype, string(aliasesJSON),
+			e.FirstNote, e.TotalMentions, e.CreatedAt, e.UpdatedAt, e.CreatedBy, e.NarrativeID)
+		if err != nil {
+			return fmt.Errorf("import entity %s: %w", e.ID, err)
+		}
+	}
+
+	for _, a := range data.EntityAttributes {
+		if err := insertEntityAttribute(tx, a.EntityID, a); err != nil {
+			return fmt.Errorf("import entity %s: %w", a.EntityID, err)
+		}
+	}
+
+	for _, v := range data.Lexicon {
+		if err := insertLexiconVerb(tx, v); err != nil {
+			return fmt.Errorf("import lexicon verb %s: %w", v.Verb, err)
+		}
+	}
+
+	for _, c := range data.DiscoveryCandidates {
+		if err := insertDiscoveryCandidate(tx, c); err != nil {
+			return fmt.Errorf("import discovery candidate %s: %w", c.Token, err)
+		}
+	}
+
+	for _, w := range data.DiscoveryStopwords {
+		if err := insertDiscoveryStopword(tx, w); err != nil {
+			return fmt.Errorf("import discovery stopword %s: %w", w.Word, err)
+		}
+	}
+
+	for _, e := range data.Edges {
+		_, err := tx.Exec(`
+			INSERT INTO edges (id, source_id, target_id, rel_type, confidence, bidirectional, source_note, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, e.ID, e.SourceID, e.TargetID, e.RelType, e.Confidence,
+			boolToInt(e.Bidirectional), e.SourceNote, e.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("import edge %s: %w", e.ID, err)
+		}
+	}
+
+	for _, f := range data.Folders {
+		_, err := tx.Exec(`
+			INSERT INTO folders (id, name, parent_id, world_id, narrative_id, folder_order, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, f.ID, f.Name, f.ParentID, f.WorldID, f.NarrativeID,
+			f.FolderOrder, f.CreatedAt, f.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("import folder %s: %w", f.ID, err)
+		}
+	}
+
+	for _, t := range data.Threads {
+		_, err := tx.Exec(`
+			INSERT INTO threads (id, world_id, narrative_id, title, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, t.ID, t.WorldID, t.NarrativeID, t.Title, t.CreatedAt, t.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("import thread %s: %w", t.ID, err)
+		}
+	}
+
+	for _, m := range data.ThreadMessages {
+		_, err := tx.Exec(`
+			INSERT INTO thread_messages (id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, m.ID, m.ThreadID, m.Role, m.Content, m.NarrativeID, m.CreatedAt, m.UpdatedAt, boolToInt(m.IsStreaming))
+		if err != nil {
+			return fmt.Errorf("import thread message %s: %w", m.ID, err)
+		}
+	}
+
+	for _, m := range data.Memories {
+		_, err := tx.Exec(`
+			INSERT INTO memories (id, content, memory_type, confidence, source_role, entity_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, m.ID, m.Content, string(m.MemoryType), m.Confidence, m.SourceRole,
+			m.EntityID, m.CreatedAt, m.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("import memory %s: %w", m.ID, err)
+		}
+	}
+
+	for _, mt := range data.MemoryThreads {
+		_, err := tx.Exec(`
+			INSERT INTO memory_threads (memory_id, thread_id, message_id, created_at)
+			VALUES (?, ?, ?, ?)
+		`, mt.MemoryID, mt.ThreadID, mt.MessageID, mt.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("import memory thread %s/%s: %w", mt.MemoryID, mt.ThreadID, err)
+		}
+	}
+
+	for _, idx := range data.SearchIndexes {
+		_, err := tx.Exec(`
+			INSERT INTO search_indexes (id, data, updated_at) VALUES (?, ?, ?)
+		`, idx.ID, idx.Data, idx.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("import search index %s: %w", idx.ID, err)
+		}
+	}
+
+	for _, e := range data.Embeddings {
+		if err := upsertEmbedding(tx, e.Kind, e.ID, e.NarrativeID, e.Vector); err != nil {
+			return fmt.Errorf("import %s embedding %s: %w", e.Kind, e.ID, err)
+		}
+	}
+
+	return nil
+}
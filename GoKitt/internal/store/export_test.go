@@ -0,0 +1,209 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedFullStore fills every table, including note history and chat data.
+func seedFullStore(t *testing.T, store *SQLiteStore) {
+	note := &Note{ID: "n1", WorldID: "w1", Title: "Draft", Content: "v1", CreatedAt: 1000, UpdatedAt: 1000}
+	require.NoError(t, store.CreateNote(note))
+	note.Title, note.Content, note.UpdatedAt = "Revised", "v2", 2000
+	require.NoError(t, store.UpdateNote(note, "edit"))
+	note.Title, note.Content, note.UpdatedAt = "Final", "v3", 3000
+	require.NoError(t, store.UpdateNote(note, "polish"))
+
+	require.NoError(t, store.UpsertEntity(&Entity{ID: "e1", Label: "Kitt", Kind: "CHARACTER", Aliases: []string{"K"}, CreatedAt: 1, UpdatedAt: 1}))
+	require.NoError(t, store.SetEntityAttributes("e1", []*EntityAttribute{
+		{Key: "faction", Type: "string", Value: "Rebels"},
+		{Key: "born", Type: "date", Value: "1982-09-26"},
+	}))
+	require.NoError(t, store.UpsertLexiconVerb(&LexiconVerb{WorldID: "w1", Verb: "enthrall", Event: "DECEIVES", Relation: "SWORN_TO"}))
+	require.NoError(t, store.SaveDiscoveryCandidates("w1", "n1", []*DiscoveryCandidate{{Token: "Iron Citadel", Count: 2, Kind: "PLACE", Contexts: []string{"They rode to the Iron Citadel."}}}))
+	require.NoError(t, store.AddDiscoveryStopword(&DiscoveryStopword{WorldID: "w1", Word: "anyway"}))
+	require.NoError(t, store.UpsertEdge(&Edge{ID: "r1", SourceID: "e1", TargetID: "e1", RelType: "KNOWS", Confidence: 0.5, CreatedAt: 1}))
+	require.NoError(t, store.UpsertFolder(&Folder{ID: "f1", Name: "Chapters", WorldID: "w1", CreatedAt: 1, UpdatedAt: 1}))
+
+	require.NoError(t, store.CreateThread(&Thread{ID: "t1", WorldID: "w1", Title: "Chat", CreatedAt: 10, UpdatedAt: 10}))
+	require.NoError(t, store.AddMessage(&ThreadMessage{ID: "m1", ThreadID: "t1", Role: "user", Content: "Hello", CreatedAt: 11}))
+	require.NoError(t, store.AddMessage(&ThreadMessage{ID: "m2", ThreadID: "t1", Role: "assistant", Content: "Hi", CreatedAt: 12}))
+	require.NoError(t, store.CreateMemory(&Memory{
+		ID: "mem1", Content: "User says hello", MemoryType: MemoryTypeFact, Confidence: 0.9,
+		SourceRole: "user", CreatedAt: 13, UpdatedAt: 13,
+	}, "t1", "m1"))
+
+	require.NoError(t, store.SaveSearchIndex("default", []byte{1, 2, 3}))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "n1", "narr-1", []float32{1, 0, 0}))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingMemory, "mem1", "", []float32{0, 1}))
+}
+
+func TestExportImport_FullFidelity(t *testing.T) {
+	src := newTestStore(t)
+	seedFullStore(t, src)
+
+	data, err := src.Export()
+	require.NoError(t, err)
+
+	var header ExportData
+	require.NoError(t, json.Unmarshal(data, &header))
+	assert.Equal(t, ExportFormatVersion, header.FormatVersion)
+	assert.Equal(t, SchemaVersion, header.SchemaVersion)
+
+	dst := newTestStore(t)
+	require.NoError(t, dst.Import(data))
+
+	// Note history survives
+	versions, err := dst.ListNoteVersions("n1")
+	require.NoError(t, err)
+	assert.Len(t, versions, 3)
+
+	atV2, err := dst.GetNoteAtTime("n1", 2500)
+	require.NoError(t, err)
+	require.NotNil(t, atV2)
+	assert.Equal(t, "Revised", atV2.Title)
+
+	v1, err := dst.GetNoteVersion("n1", 1)
+	require.NoError(t, err)
+	require.NotNil(t, v1)
+	assert.False(t, v1.IsCurrent)
+	require.NotNil(t, v1.ValidTo)
+	assert.Equal(t, int64(2000), *v1.ValidTo)
+
+	current, err := dst.GetNote("n1")
+	require.NoError(t, err)
+	assert.Equal(t, "Final", current.Title)
+	assert.Equal(t, "polish", current.ChangeReason)
+
+	require.NoError(t, dst.RestoreNoteVersion("n1", 1))
+	restored, err := dst.GetNote("n1")
+	require.NoError(t, err)
+	assert.Equal(t, "Draft", restored.Title)
+
+	// Chat data survives
+	thread, err := dst.GetThread("t1")
+	require.NoError(t, err)
+	require.NotNil(t, thread)
+	assert.Equal(t, "Chat", thread.Title)
+
+	messages, err := dst.GetThreadMessages("t1")
+	require.NoError(t, err)
+	assert.Len(t, messages, 2)
+
+	memories, err := dst.GetMemoriesForThread("t1")
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+	assert.Equal(t, "User says hello", memories[0].Content)
+
+	entity, err := dst.GetEntity("e1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"K"}, entity.Aliases)
+
+	attrs, err := dst.GetEntityAttributes("e1")
+	require.NoError(t, err)
+	require.Len(t, attrs, 2)
+	assert.Equal(t, "born", attrs[0].Key)
+	assert.Equal(t, "Rebels", attrs[1].Value)
+
+	lexicon, err := dst.ListLexiconVerbs("w1")
+	require.NoError(t, err)
+	require.Len(t, lexicon, 1)
+	assert.Equal(t, "SWORN_TO", lexicon[0].Relation)
+
+	candidates, err := dst.ListDiscoveryCandidates("w1")
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "n1", candidates[0].NarrativeID)
+	assert.Equal(t, []string{"They rode to the Iron Citadel."}, candidates[0].Contexts)
+
+	stopwords, err := dst.ListDiscoveryStopwords("w1")
+	require.NoError(t, err)
+	require.Len(t, stopwords, 1)
+
+	index, err := dst.LoadSearchIndex("default")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, index)
+
+	// Embeddings survive with their dimensions and partitions
+	vec, err := dst.GetEmbedding(EmbeddingMemory, "mem1")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0, 1}, vec)
+	matches, err := dst.SearchEmbeddings(EmbeddingNote, []float32{1, 0, 0}, 5, "narr-1")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "n1", matches[0].ID)
+
+	// Export of the restored store matches the original (before the restore above)
+	again := newTestStore(t)
+	require.NoError(t, again.Import(data))
+	data2, err := again.Export()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(data2))
+}
+
+func TestImport_RollsBackOnFailure(t *testing.T) {
+	store := newTestStore(t)
+	seedFullStore(t, store)
+
+	// Duplicate (id, version) violates the notes primary key mid-import
+	bad := ExportData{
+		FormatVersion: ExportFormatVersion,
+		Folders:       []*Folder{{ID: "f-new", Name: "New", WorldID: "w1"}},
+		Notes: []*Note{
+			{ID: "dup", Version: 1, WorldID: "w1", Title: "A", Content: "a", IsCurrent: true},
+			{ID: "dup", Version: 1, WorldID: "w1", Title: "B", Content: "b", IsCurrent: true},
+		},
+	}
+	data, err := json.Marshal(bad)
+	require.NoError(t, err)
+
+	require.Error(t, store.Import(data))
+
+	// Nothing was cleared or half-written
+	note, err := store.GetNote("n1")
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	assert.Equal(t, "Final", note.Title)
+
+	messages, err := store.GetThreadMessages("t1")
+	require.NoError(t, err)
+	assert.Len(t, messages, 2)
+
+	folder, err := store.GetFolder("f-new")
+	require.NoError(t, err)
+	assert.Nil(t, folder)
+}
+
+func TestImport_LegacyFormat(t *testing.T) {
+	// Pre-versioned exports only carried current notes, without temporal fields
+	legacy := `{
+		"notes": [{"id": "old", "version": 4, "worldId": "w1", "title": "Legacy", "content": "c", "createdAt": 500, "updatedAt": 900}],
+		"entities": [], "edges": [], "folders": []
+	}`
+
+	store := newTestStore(t)
+	require.NoError(t, store.Import([]byte(legacy)))
+
+	note, err := store.GetNote("old")
+	require.NoError(t, err)
+	require.NotNil(t, note)
+	assert.Equal(t, 4, note.Version)
+	assert.True(t, note.IsCurrent)
+	assert.Equal(t, int64(500), note.ValidFrom)
+}
+
+func TestImport_RejectsNewerFormat(t *testing.T) {
+	store := newTestStore(t)
+	seedFullStore(t, store)
+
+	data, err := json.Marshal(ExportData{FormatVersion: ExportFormatVersion + 1})
+	require.NoError(t, err)
+	require.Error(t, store.Import(data))
+
+	count, err := store.CountNotes()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,104 @@
+package store
+
+import "strings"
+
+// narrativeLexiconSchema holds each world's custom verbs.
+// Verbs are stored lower-cased with single spaces so lookups and upserts agree.
+const narrativeLexiconSchema = `
+CREATE TABLE IF NOT EXISTS narrative_lexicon (
+    world_id TEXT NOT NULL DEFAULT '',
+    verb TEXT NOT NULL,
+    event TEXT NOT NULL DEFAULT '',
+    relation TEXT NOT NULL,
+    transitivity TEXT NOT NULL DEFAULT '',
+    updated_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (world_id, verb)
+);
+`
+
+// UpsertLexiconVerb adds or replaces one custom verb of a world.
+func (s *SQLiteStore) UpsertLexiconVerb(verb *LexiconVerb) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return insertLexiconVerb(s.db, verb)
+}
+
+// DeleteLexiconVerb removes a custom verb from a world.
+func (s *SQLiteStore) DeleteLexiconVerb(worldID, verb string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM narrative_lexicon WHERE world_id = ? AND verb = ?", worldID, normalizePhrase(verb))
+	return err
+}
+
+// SetLexicon replaces every custom verb of a world.
+// An empty slice clears them.
+func (s *SQLiteStore) SetLexicon(worldID string, verbs []*LexiconVerb) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM narrative_lexicon WHERE world_id = ?", worldID); err != nil {
+		return err
+	}
+	for _, v := range verbs {
+		v.WorldID = worldID
+		if err := insertLexiconVerb(tx, v); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListLexiconVerbs returns a world's custom verbs sorted by verb.
+// World "" holds verbs shared by every world.
+func (s *SQLiteStore) ListLexiconVerbs(worldID string) ([]*LexiconVerb, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return queryLexiconVerbs(s.db, "WHERE world_id = ? ORDER BY verb", worldID)
+}
+
+// Helpers
+
+func normalizePhrase(verb string) string {
+	return strings.Join(strings.Fields(strings.ToLower(verb)), " ")
+}
+
+func insertLexiconVerb(db sqlExecutor, v *LexiconVerb) error {
+	v.Verb = normalizePhrase(v.Verb)
+	v.Relation = strings.ToUpper(strings.TrimSpace(v.Relation))
+	_, err := db.Exec(`
+		INSERT INTO narrative_lexicon (world_id, verb, event, relation, transitivity, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(world_id, verb) DO UPDATE SET
+			event = excluded.event, relation = excluded.relation,
+			transitivity = excluded.transitivity, updated_at = excluded.updated_at
+	`, v.WorldID, v.Verb, v.Event, v.Relation, v.Transitivity, v.UpdatedAt)
+	return err
+}
+
+func queryLexiconVerbs(db sqlExecutor, where string, args ...any) ([]*LexiconVerb, error) {
+	rows, err := db.Query("SELECT world_id, verb, event, relation, transitivity, updated_at FROM narrative_lexicon "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var verbs []*LexiconVerb
+	for rows.Next() {
+		var v LexiconVerb
+		if err := rows.Scan(&v.WorldID, &v.Verb, &v.Event, &v.Relation, &v.Transitivity, &v.UpdatedAt); err != nil {
+			return nil, err
+		}
+		verbs = append(verbs, &v)
+	}
+	return verbs, rows.Err()
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexicon_PerWorld(t *testing.T) {
+	store := newTestStore(t)
+
+	require.NoError(t, store.UpsertLexiconVerb(&LexiconVerb{WorldID: "w1", Verb: "Enthrall", Event: "DECEIVES", Relation: "deceives", Transitivity: "transitive"}))
+	require.NoError(t, store.UpsertLexiconVerb(&LexiconVerb{WorldID: "w1", Verb: "swear  fealty to", Relation: "SWORN_TO"}))
+	require.NoError(t, store.UpsertLexiconVerb(&LexiconVerb{WorldID: "w2", Verb: "hex", Relation: "CURSES"}))
+
+	verbs, err := store.ListLexiconVerbs("w1")
+	require.NoError(t, err)
+	require.Len(t, verbs, 2)
+	assert.Equal(t, "enthrall", verbs[0].Verb)
+	assert.Equal(t, "DECEIVES", verbs[0].Relation)
+	assert.Equal(t, "swear fealty to", verbs[1].Verb)
+
+	// Upserting the same verb replaces it
+	require.NoError(t, store.UpsertLexiconVerb(&LexiconVerb{WorldID: "w1", Verb: "enthrall", Relation: "RULES"}))
+	verbs, err = store.ListLexiconVerbs("w1")
+	require.NoError(t, err)
+	require.Len(t, verbs, 2)
+	assert.Equal(t, "RULES", verbs[0].Relation)
+
+	require.NoError(t, store.DeleteLexiconVerb("w1", "Swear Fealty To"))
+	verbs, err = store.ListLexiconVerbs("w1")
+	require.NoError(t, err)
+	assert.Len(t, verbs, 1)
+
+	// SetLexicon replaces one world only
+	require.NoError(t, store.SetLexicon("w1", []*LexiconVerb{{Verb: "beguile", Relation: "DECEIVES"}}))
+	verbs, err = store.ListLexiconVerbs("w1")
+	require.NoError(t, err)
+	require.Len(t, verbs, 1)
+	assert.Equal(t, "beguile", verbs[0].Verb)
+	assert.Equal(t, "w1", verbs[0].WorldID)
+
+	other, err := store.ListLexiconVerbs("w2")
+	require.NoError(t, err)
+	assert.Len(t, other, 1)
+}
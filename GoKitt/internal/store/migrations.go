@@ -0,0 +1,104 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SchemaVersion is the version of the SQLite schema this build writes.
+// Stored in PRAGMA user_version; equals the last migration's version.
+const SchemaVersion = 7
+
+// migration is one ordered schema step. Version N upgrades user_version N-1 to N.
+// Steps must be idempotent: databases created before version tracking report
+// user_version 0 even though some tables already exist, and Import replays
+// steps newer than the export's schema over freshly inserted rows.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations lists every schema step in order. Append only; never edit a shipped step.
+var migrations = []migration{
+	{1, "baseline schema", execStep(schemaV1)},
+	{2, "search index snapshots", execStep(`
+		CREATE TABLE IF NOT EXISTS search_indexes (
+			id TEXT PRIMARY KEY,
+			data BLOB NOT NULL,
+			updated_at INTEGER NOT NULL
+		);
+	`)},
+	// vec0 tables fix their dimension at creation, so they are created on the
+	// first stored vector (see ensureEmbeddingTable); this records each one.
+	{3, "vector embedding tables", execStep(`
+		CREATE TABLE IF NOT EXISTS embedding_tables (
+			kind TEXT PRIMARY KEY,
+			dimensions INTEGER NOT NULL
+		);
+	`)},
+	{4, "note full-text search", execStep(notesFTSSchema)},
+	{5, "entity attributes", execStep(entityAttributesSchema)},
+	{6, "narrative lexicon", execStep(narrativeLexiconSchema)},
+	{7, "discovery registry", execStep(discoverySchema)},
+}
+
+// migrate upgrades the database to SchemaVersion in a single transaction.
+func migrate(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := userVersion(tx)
+	if err != nil {
+		return err
+	}
+	if err := applyMigrations(tx, current); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyMigrations runs every step newer than from and records SchemaVersion.
+func applyMigrations(tx *sql.Tx, from int) error {
+	if from > SchemaVersion {
+		return fmt.Errorf("database schema %d is newer than supported %d", from, SchemaVersion)
+	}
+	for _, m := range migrations {
+		if m.version <= from {
+			continue
+		}
+		if err := m.up(tx); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+	}
+	// PRAGMA doesn't take bound parameters
+	_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion))
+	return err
+}
+
+// userVersion reads PRAGMA user_version.
+func userVersion(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (int, error) {
+	var version int
+	err := q.QueryRow("PRAGMA user_version").Scan(&version)
+	return version, err
+}
+
+// execStep returns a migration step that executes a fixed SQL script.
+func execStep(script string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(script)
+		return err
+	}
+}
+
+// CurrentSchemaVersion reports the database's PRAGMA user_version.
+func (s *SQLiteStore) CurrentSchemaVersion() (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return userVersion(s.db)
+}
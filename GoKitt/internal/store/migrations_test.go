@@ -0,0 +1,117 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_Ordered(t *testing.T) {
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.version, "migration %q out of order", m.name)
+	}
+	assert.Equal(t, SchemaVersion, migrations[len(migrations)-1].version)
+}
+
+func TestMigrations_FreshStoreIsCurrent(t *testing.T) {
+	store := newTestStore(t)
+	version, err := store.CurrentSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+
+	// Replaying every step over a current database is a no-op
+	tx, err := store.db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.NoError(t, applyMigrations(tx, 0))
+}
+
+// TestMigrations_UpgradeFixtures opens a fixture database for every past
+// schema version and checks that it upgrades without losing data.
+func TestMigrations_UpgradeFixtures(t *testing.T) {
+	for from := 0; from < SchemaVersion; from++ {
+		t.Run(fmt.Sprintf("v%d", from), func(t *testing.T) {
+			script, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("schema_v%d.sql", from)))
+			require.NoError(t, err, "every past schema needs a fixture")
+
+			path := filepath.Join(t.TempDir(), "fixture.db")
+			raw, err := sql.Open("sqlite3", path)
+			require.NoError(t, err)
+			_, err = raw.Exec(string(script))
+			require.NoError(t, err)
+			require.NoError(t, raw.Close())
+
+			store, err := NewSQLiteStoreWithDSN(path)
+			require.NoError(t, err)
+			defer store.Close()
+
+			version, err := store.CurrentSchemaVersion()
+			require.NoError(t, err)
+			assert.Equal(t, SchemaVersion, version)
+
+			// Existing rows survive
+			note, err := store.GetNote("note-1")
+			require.NoError(t, err)
+			require.NotNil(t, note)
+			assert.Equal(t, "New Title", note.Title)
+
+			versions, err := store.ListNoteVersions("note-1")
+			require.NoError(t, err)
+			assert.Len(t, versions, 2)
+
+			entity, err := store.GetEntity("entity-1")
+			require.NoError(t, err)
+			require.NotNil(t, entity)
+			assert.Equal(t, []string{"K"}, entity.Aliases)
+
+			messages, err := store.GetThreadMessages("thread-1")
+			require.NoError(t, err)
+			assert.Len(t, messages, 1)
+
+			// Tables added by later migrations are usable
+			require.NoError(t, store.SaveSearchIndex("default", []byte{1}))
+			require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "note-1", "", []float32{1, 0}))
+			require.NoError(t, store.SetEntityAttributes("entity-1", []*EntityAttribute{{Key: "status", Type: "string", Value: "active"}}))
+			require.NoError(t, store.UpsertLexiconVerb(&LexiconVerb{WorldID: "world-1", Verb: "enthrall", Relation: "DECEIVES"}))
+			require.NoError(t, store.SaveDiscoveryCandidates("world-1", "", []*DiscoveryCandidate{{Token: "Varen", Count: 1}}))
+			require.NoError(t, store.AddDiscoveryStopword(&DiscoveryStopword{WorldID: "world-1", Word: "anyway"}))
+
+			// Existing notes are backfilled into full-text search
+			hits, err := store.SearchNotes("new", nil)
+			require.NoError(t, err)
+			require.Len(t, hits, 1)
+			assert.Equal(t, 2, hits[0].Version)
+			hits, err = store.SearchNotes("old", &NoteSearchScope{History: true})
+			require.NoError(t, err)
+			assert.Len(t, hits, 1)
+		})
+	}
+}
+
+func TestMigrations_RejectNewerDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "future.db")
+	raw, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = raw.Exec(fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion+1))
+	require.NoError(t, err)
+	require.NoError(t, raw.Close())
+
+	_, err = NewSQLiteStoreWithDSN(path)
+	assert.Error(t, err)
+}
+
+func TestMigrations_ImportReplaysSteps(t *testing.T) {
+	// An export written at schema 1 predates the search_indexes table
+	store := newTestStore(t)
+	data := []byte(`{"formatVersion": 2, "schemaVersion": 1, "notes": [], "entities": [], "edges": [], "folders": []}`)
+	require.NoError(t, store.Import(data))
+
+	version, err := store.CurrentSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+}
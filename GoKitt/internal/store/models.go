@@ -46,6 +46,55 @@ type Entity struct {
 	UpdatedAt     int64    `json:"updatedAt"`
 }
 
+// EntityAttribute is one typed key/value on an entity, usually read from the
+// entity note's frontmatter (faction, status, born, ...).
+// Type is "string", "number", "bool", "date", "list" or "object"; Value is
+// stored as JSON and decodes to the matching Go type.
+type EntityAttribute struct {
+	EntityID string `json:"entityId"`
+	Key      string `json:"key"`
+	Type     string `json:"type"`
+	Value    any    `json:"value"`
+}
+
+// LexiconVerb is a world's own narrative verb, loaded into the verb matcher
+// on top of the built-in lexicon. Names follow the narrative package:
+// Event is an EventClass name ("BATTLE"), Relation a built-in or custom
+// RelationType name ("KILLS", "SWORN_TO").
+type LexiconVerb struct {
+	WorldID      string `json:"worldId"`
+	Verb         string `json:"verb"` // A word or phrasal verb ("swear fealty to")
+	Event        string `json:"event,omitempty"`
+	Relation     string `json:"relation"`
+	Transitivity string `json:"transitivity,omitempty"` // "transitive", "intransitive" or "ditransitive"
+	UpdatedAt    int64  `json:"updatedAt"`
+}
+
+// DiscoveryCandidate is a possible new entity tracked by discovery in one
+// narrative. Status follows the discovery package: 0 watching, 1 promoted,
+// 2 ignored; Kind is an entity kind name, empty while unknown.
+type DiscoveryCandidate struct {
+	WorldID     string   `json:"worldId"`
+	NarrativeID string   `json:"narrativeId,omitempty"`
+	Token       string   `json:"token"` // Display form ("Mira Ashdown")
+	Count       int      `json:"count"`
+	Status      int      `json:"status"`
+	Kind        string   `json:"kind,omitempty"`
+	FirstNote   string   `json:"firstNote,omitempty"` // Note the name was first seen in
+	Contexts    []string `json:"contexts,omitempty"`  // Example sentences
+	Titles      []string `json:"titles,omitempty"`
+	PartOf      string   `json:"partOf,omitempty"`
+	UpdatedAt   int64    `json:"updatedAt"`
+}
+
+// DiscoveryStopword is a word or name the user rejected as an entity;
+// discovery ignores it in every narrative of the world.
+type DiscoveryStopword struct {
+	WorldID   string `json:"worldId"`
+	Word      string `json:"word"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
 // Edge represents a relationship between two entities.
 // Maps 1:1 to Dexie Edge interface.
 type Edge struct {
@@ -130,6 +179,75 @@ type MemoryThread struct {
 	CreatedAt int64  `json:"createdAt"`
 }
 
+// SearchIndex is a persisted ResoRank snapshot (see resorank.Scorer.Snapshot).
+// The blob is opaque to the store; versioning lives in the snapshot header.
+type SearchIndex struct {
+	ID        string `json:"id"`
+	Data      []byte `json:"data"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// EmbeddingKind selects which vector table an embedding lives in.
+type EmbeddingKind string
+
+const (
+	EmbeddingNote   EmbeddingKind = "note"
+	EmbeddingEntity EmbeddingKind = "entity"
+	EmbeddingMemory EmbeddingKind = "memory"
+)
+
+// Embedding is a stored vector for a note, entity or memory.
+// Each kind has its own sqlite-vec table with a fixed dimension.
+type Embedding struct {
+	Kind        EmbeddingKind `json:"kind"`
+	ID          string        `json:"id"`
+	NarrativeID string        `json:"narrativeId,omitempty"` // Partition key for scoped KNN
+	Vector      []float32     `json:"vector"`
+}
+
+// VectorMatch is one nearest-neighbour result.
+// Distance is cosine distance: 0 for identical direction, up to 2 for opposite.
+type VectorMatch struct {
+	ID       string  `json:"id"`
+	Distance float64 `json:"distance"`
+}
+
+// Similarity converts the cosine distance back to cosine similarity.
+func (m VectorMatch) Similarity() float64 {
+	return 1 - m.Distance
+}
+
+// NoteSearchScope narrows SearchNotes. The zero value searches every current note.
+type NoteSearchScope struct {
+	WorldID     string `json:"worldId,omitempty"`
+	NarrativeID string `json:"narrativeId,omitempty"`
+	FolderID    string `json:"folderId,omitempty"` // Includes subfolders
+	History     bool   `json:"history,omitempty"`  // Search every version, not just current ones
+	Limit       int    `json:"limit,omitempty"`    // 0 means DefaultNoteSearchLimit
+}
+
+// TextSpan locates a match as byte offsets into a note field.
+type TextSpan struct {
+	Field string `json:"field"` // "title" or "content"
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// NoteSearchResult is one full-text hit from SearchNotes.
+// Content offsets refer to the indexed body: markdown_content, or content when that's empty.
+type NoteSearchResult struct {
+	NoteID      string     `json:"noteId"`
+	Version     int        `json:"version"`
+	IsCurrent   bool       `json:"isCurrent"`
+	Title       string     `json:"title"`
+	WorldID     string     `json:"worldId"`
+	NarrativeID string     `json:"narrativeId,omitempty"`
+	FolderID    string     `json:"folderId,omitempty"`
+	Snippet     string     `json:"snippet"` // Body excerpt with <mark> highlights
+	Score       float64    `json:"score"`   // Higher is better (negated bm25)
+	Matches     []TextSpan `json:"matches"`
+}
+
 // Storer defines the interface for data persistence.
 // SQLiteStore is the sole implementation, using in-memory SQLite for WASM.
 type Storer interface {
@@ -139,6 +257,7 @@ type Storer interface {
 	DeleteNote(id string) error
 	ListNotes(folderID string) ([]*Note, error)
 	CountNotes() (int, error)
+	SearchNotes(query string, scope *NoteSearchScope) ([]*NoteSearchResult, error)
 
 	// Notes - Version-aware operations
 	CreateNote(note *Note) error
@@ -152,10 +271,29 @@ type Storer interface {
 	UpsertEntity(entity *Entity) error
 	GetEntity(id string) (*Entity, error)
 	GetEntityByLabel(label string) (*Entity, error)
+	GetEntityByLabelInNarrative(label, narrativeID string) (*Entity, error)
 	DeleteEntity(id string) error
 	ListEntities(kind string) ([]*Entity, error)
 	CountEntities() (int, error)
 
+	// Entity attributes - Typed frontmatter values
+	SetEntityAttributes(entityID string, attrs []*EntityAttribute) error
+	GetEntityAttributes(entityID string) ([]*EntityAttribute, error)
+
+	// Narrative lexicon - Per-world custom verbs
+	UpsertLexiconVerb(verb *LexiconVerb) error
+	DeleteLexiconVerb(worldID, verb string) error
+	SetLexicon(worldID string, verbs []*LexiconVerb) error
+	ListLexiconVerbs(worldID string) ([]*LexiconVerb, error)
+
+	// Discovery registry - Per-world candidates and rejected words
+	SaveDiscoveryCandidates(worldID, narrativeID string, candidates []*DiscoveryCandidate) error
+	UpsertDiscoveryCandidates(worldID, narrativeID string, candidates []*DiscoveryCandidate) error
+	ListDiscoveryCandidates(worldID string) ([]*DiscoveryCandidate, error)
+	AddDiscoveryStopword(stopword *DiscoveryStopword) error
+	DeleteDiscoveryStopword(worldID, word string) error
+	ListDiscoveryStopwords(worldID string) ([]*DiscoveryStopword, error)
+
 	// Edges
 	UpsertEdge(edge *Edge) error
 	GetEdge(id string) (*Edge, error)
@@ -190,6 +328,18 @@ type Storer interface {
 	GetMemoriesForThread(threadID string) ([]*Memory, error)
 	ListMemoriesByType(memoryType MemoryType) ([]*Memory, error)
 
+	// Search indexes - Serialized ResoRank snapshots
+	SaveSearchIndex(id string, data []byte) error
+	LoadSearchIndex(id string) ([]byte, error)
+	DeleteSearchIndex(id string) error
+
+	// Embeddings - sqlite-vec KNN over notes, entities and memories
+	UpsertEmbedding(kind EmbeddingKind, id, narrativeID string, vector []float32) error
+	GetEmbedding(kind EmbeddingKind, id string) ([]float32, error)
+	DeleteEmbedding(kind EmbeddingKind, id string) error
+	ClearEmbeddings(kind EmbeddingKind) error
+	SearchEmbeddings(kind EmbeddingKind, query []float32, k int, narrativeID string) ([]VectorMatch, error)
+
 	// Export/Import (Database serialization for OPFS sync)
 	Export() ([]byte, error)
 	Import(data []byte) error
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultNoteSearchLimit caps SearchNotes results when the scope sets no limit.
+const DefaultNoteSearchLimit = 50
+
+// notesFTSSchema creates the FTS5 indexes over notes and the triggers that keep
+// them in sync with every write path (CRUD, versioning, Import).
+//
+// notes_fts holds current versions only; notes_history_fts holds every version.
+// Both share rowids with the notes table, which is never VACUUMed (that could
+// renumber implicit rowids). The trailing rebuild makes the step safe to replay.
+const notesFTSSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(
+    title, content, tokenize = 'unicode61 remove_diacritics 2'
+);
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_history_fts USING fts5(
+    title, content, tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes BEGIN
+    INSERT INTO notes_history_fts (rowid, title, content)
+    VALUES (NEW.rowid, NEW.title, COALESCE(NULLIF(NEW.markdown_content, ''), NEW.content));
+    INSERT INTO notes_fts (rowid, title, content)
+    SELECT NEW.rowid, NEW.title, COALESCE(NULLIF(NEW.markdown_content, ''), NEW.content)
+    WHERE NEW.is_current = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE ON notes BEGIN
+    DELETE FROM notes_fts WHERE rowid = OLD.rowid;
+    DELETE FROM notes_history_fts WHERE rowid = OLD.rowid;
+    INSERT INTO notes_history_fts (rowid, title, content)
+    VALUES (NEW.rowid, NEW.title, COALESCE(NULLIF(NEW.markdown_content, ''), NEW.content));
+    INSERT INTO notes_fts (rowid, title, content)
+    SELECT NEW.rowid, NEW.title, COALESCE(NULLIF(NEW.markdown_content, ''), NEW.content)
+    WHERE NEW.is_current = 1;
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes BEGIN
+    DELETE FROM notes_fts WHERE rowid = OLD.rowid;
+    DELETE FROM notes_history_fts WHERE rowid = OLD.rowid;
+END;
+
+DELETE FROM notes_fts;
+DELETE FROM notes_history_fts;
+INSERT INTO notes_history_fts (rowid, title, content)
+SELECT rowid, title, COALESCE(NULLIF(markdown_content, ''), content) FROM notes;
+INSERT INTO notes_fts (rowid, title, content)
+SELECT rowid, title, COALESCE(NULLIF(markdown_content, ''), content) FROM notes WHERE is_current = 1;
+`
+
+// Highlight markers used to recover match offsets; stripped before returning.
+const (
+	matchStart = '\x01'
+	matchEnd   = '\x02'
+)
+
+// SearchNotes runs a full-text query over note titles and bodies, best match first.
+// Each whitespace-separated word must appear (AND); a trailing * makes a word a
+// prefix match. FTS5 operators in the query are treated as plain text.
+func (s *SQLiteStore) SearchNotes(query string, scope *NoteSearchScope) ([]*NoteSearchResult, error) {
+	if scope == nil {
+		scope = &NoteSearchScope{}
+	}
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	limit := scope.Limit
+	if limit <= 0 {
+		limit = DefaultNoteSearchLimit
+	}
+
+	table := "notes_fts"
+	if scope.History {
+		table = "notes_history_fts"
+	}
+
+	// FTS5 auxiliary functions take the table name, so it can't be aliased
+	sqlQuery := fmt.Sprintf(`
+		SELECT n.id, n.version, n.is_current, n.world_id, COALESCE(n.narrative_id, ''),
+			   COALESCE(n.folder_id, ''),
+			   snippet(%[1]s, 1, '<mark>', '</mark>', '…', 16),
+			   highlight(%[1]s, 0, char(1), char(2)),
+			   highlight(%[1]s, 1, char(1), char(2)),
+			   bm25(%[1]s, 10.0, 1.0)
+		FROM %[1]s JOIN notes n ON n.rowid = %[1]s.rowid
+		WHERE %[1]s MATCH ?`, table)
+	args := []any{match}
+
+	if scope.WorldID != "" {
+		sqlQuery += " AND n.world_id = ?"
+		args = append(args, scope.WorldID)
+	}
+	if scope.NarrativeID != "" {
+		sqlQuery += " AND n.narrative_id = ?"
+		args = append(args, scope.NarrativeID)
+	}
+	if scope.FolderID != "" {
+		sqlQuery += ` AND n.folder_id IN (
+			WITH RECURSIVE sub(id) AS (
+				SELECT ? UNION SELECT f.id FROM folders f JOIN sub ON f.parent_id = sub.id
+			)
+			SELECT id FROM sub
+		)`
+		args = append(args, scope.FolderID)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY bm25(%s, 10.0, 1.0), n.id, n.version DESC LIMIT ?", table)
+	args = append(args, limit)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search notes: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*NoteSearchResult
+	for rows.Next() {
+		var r NoteSearchResult
+		var isCurrent int
+		var titleMarked, bodyMarked string
+		var rank float64
+		if err := rows.Scan(
+			&r.NoteID, &r.Version, &isCurrent, &r.WorldID, &r.NarrativeID, &r.FolderID,
+			&r.Snippet, &titleMarked, &bodyMarked, &rank,
+		); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		r.IsCurrent = isCurrent != 0
+		r.Score = -rank
+
+		var spans []TextSpan
+		r.Title, spans = stripHighlights("title", titleMarked)
+		r.Matches = spans
+		_, spans = stripHighlights("content", bodyMarked)
+		r.Matches = append(r.Matches, spans...)
+
+		results = append(results, &r)
+	}
+	return results, rows.Err()
+}
+
+// ftsQuery turns free text into an FTS5 query: every word becomes a quoted
+// string (so punctuation and operators can't cause syntax errors), keeping a
+// trailing * as a prefix match.
+func ftsQuery(query string) string {
+	words := strings.Fields(query)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
+
+// stripHighlights removes highlight markers and returns the original text
+// plus the byte offsets of each marked span.
+func stripHighlights(field, marked string) (string, []TextSpan) {
+	var text strings.Builder
+	text.Grow(len(marked))
+	var spans []TextSpan
+	start := -1
+	for i := 0; i < len(marked); i++ {
+		switch marked[i] {
+		case matchStart:
+			start = text.Len()
+		case matchEnd:
+			if start >= 0 {
+				spans = append(spans, TextSpan{Field: field, Start: start, End: text.Len()})
+				start = -1
+			}
+		default:
+			text.WriteByte(marked[i])
+		}
+	}
+	return text.String(), spans
+}
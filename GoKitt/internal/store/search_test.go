@@ -0,0 +1,108 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchNotes_CurrentVersions(t *testing.T) {
+	store := newTestStore(t)
+	note := &Note{ID: "n1", WorldID: "w1", Title: "The Dragon", Content: "{}", MarkdownContent: "A dragon sleeps under the castle.", CreatedAt: 1}
+	require.NoError(t, store.CreateNote(note))
+	require.NoError(t, store.CreateNote(&Note{ID: "n2", WorldID: "w1", Title: "Market", Content: "Plain body about bread", CreatedAt: 1}))
+
+	results, err := store.SearchNotes("dragon", nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	r := results[0]
+	assert.Equal(t, "n1", r.NoteID)
+	assert.Equal(t, "The Dragon", r.Title)
+	assert.True(t, r.IsCurrent)
+	assert.Contains(t, r.Snippet, "<mark>dragon</mark>")
+	assert.Greater(t, r.Score, 0.0)
+	assert.Equal(t, []TextSpan{
+		{Field: "title", Start: 4, End: 10},
+		{Field: "content", Start: 2, End: 8},
+	}, r.Matches)
+
+	// Falls back to content when there's no markdown
+	results, err = store.SearchNotes("bread", nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "n2", results[0].NoteID)
+
+	// Edits replace the current entry; the old text is only in history
+	note.Title, note.MarkdownContent = "The Lair", "A wyvern sleeps under the castle."
+	require.NoError(t, store.UpdateNote(note, "edit"))
+
+	results, err = store.SearchNotes("dragon sleeps", nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = store.SearchNotes("dragon sleeps", &NoteSearchScope{History: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Version)
+	assert.False(t, results[0].IsCurrent)
+
+	results, err = store.SearchNotes("wyv*", nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 2, results[0].Version)
+
+	// Deleting a note removes it from both indexes
+	require.NoError(t, store.DeleteNote("n1"))
+	results, err = store.SearchNotes("castle", &NoteSearchScope{History: true})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSearchNotes_Scope(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.UpsertFolder(&Folder{ID: "root", Name: "Root", WorldID: "w1"}))
+	require.NoError(t, store.UpsertFolder(&Folder{ID: "child", Name: "Child", ParentID: "root", WorldID: "w1"}))
+	require.NoError(t, store.UpsertFolder(&Folder{ID: "other", Name: "Other", WorldID: "w1"}))
+
+	for _, n := range []*Note{
+		{ID: "a", WorldID: "w1", NarrativeID: "story", FolderID: "root", Title: "A", Content: "harbor"},
+		{ID: "b", WorldID: "w1", NarrativeID: "story", FolderID: "child", Title: "B", Content: "harbor"},
+		{ID: "c", WorldID: "w1", FolderID: "other", Title: "C", Content: "harbor"},
+		{ID: "d", WorldID: "w2", Title: "D", Content: "harbor"},
+	} {
+		require.NoError(t, store.CreateNote(n))
+	}
+
+	ids := func(scope *NoteSearchScope) []string {
+		results, err := store.SearchNotes("harbor", scope)
+		require.NoError(t, err)
+		var out []string
+		for _, r := range results {
+			out = append(out, r.NoteID)
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, ids(nil))
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, ids(&NoteSearchScope{WorldID: "w1"}))
+	assert.ElementsMatch(t, []string{"a", "b"}, ids(&NoteSearchScope{NarrativeID: "story"}))
+	assert.ElementsMatch(t, []string{"a", "b"}, ids(&NoteSearchScope{FolderID: "root"}))
+	assert.ElementsMatch(t, []string{"b"}, ids(&NoteSearchScope{FolderID: "child"}))
+	assert.Len(t, ids(&NoteSearchScope{Limit: 2}), 2)
+}
+
+func TestSearchNotes_QuerySyntax(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.CreateNote(&Note{ID: "n1", WorldID: "w1", Title: "Notes", Content: `She said "NEAR" AND left`}))
+
+	// Operators and stray quotes are plain text, not syntax errors
+	for _, q := range []string{`"near`, `AND`, `left)`, `NEAR(`} {
+		_, err := store.SearchNotes(q, nil)
+		assert.NoError(t, err, q)
+	}
+
+	results, err := store.SearchNotes("   ", nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -20,8 +20,9 @@ type SQLiteStore struct {
 	db *sql.DB
 }
 
-// schema defines all tables for the unified data layer with temporal versioning.
-const schema = `
+// schemaV1 defines all tables for the unified data layer with temporal versioning.
+// This is the original schema; later changes are separate steps in migrations.go.
+const schemaV1 = `
 -- Notes (Temporal versioning pattern)
 -- Composite primary key (id, version) enables full version history
 CREATE TABLE IF NOT EXISTS notes (
@@ -178,10 +179,10 @@ func NewSQLiteStoreWithDSN(dsn string) (*SQLiteStore, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Create schema
-	if _, err := db.Exec(schema); err != nil {
+	// Create or upgrade schema
+	if err := migrate(db); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to create schema: %w", err)
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
 	return &SQLiteStore{db: db}, nil
@@ -643,13 +644,15 @@ func (s *SQLiteStore) RestoreNoteVersion(id string, version int) error {
 	return err
 }
 
-// DeleteNote removes all versions of a note.
+// DeleteNote removes all versions of a note and its embedding.
 func (s *SQLiteStore) DeleteNote(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.db.Exec("DELETE FROM notes WHERE id = ?", id)
-	return err
+	if _, err := s.db.Exec("DELETE FROM notes WHERE id = ?", id); err != nil {
+		return err
+	}
+	return deleteEmbedding(s.db, EmbeddingNote, id)
 }
 
 // ListNotes returns current versions of all notes, optionally filtered by folder.
@@ -815,6 +818,16 @@ func (s *SQLiteStore) GetEntity(id string) (*Entity, error) {
 
 // GetEntityByLabel finds an entity by its label (case-insensitive).
 func (s *SQLiteStore) GetEntityByLabel(label string) (*Entity, error) {
+	return s.getEntityWhere("LOWER(label) = LOWER(?)", label)
+}
+
+// GetEntityByLabelInNarrative finds an entity by its label (case-insensitive)
+// among the entities of one narrative; "" is the global entities.
+func (s *SQLiteStore) GetEntityByLabelInNarrative(label, narrativeID string) (*Entity, error) {
+	return s.getEntityWhere("LOWER(label) = LOWER(?) AND COALESCE(narrative_id, '') = ?", label, narrativeID)
+}
+
+func (s *SQLiteStore) getEntityWhere(where string, args ...interface{}) (*Entity, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -824,8 +837,7 @@ func (s *SQLiteStore) GetEntityByLabel(label string) (*Entity, error) {
 	err := s.db.QueryRow(`
 		SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
 			narrative_id, created_by, created_at, updated_at
-		FROM entities WHERE LOWER(label) = LOWER(?)
-	`, label).Scan(
+		FROM entities WHERE `+where, args...).Scan(
 		&entity.ID, &entity.Label, &entity.Kind, &entity.Subtype, &aliasesJSON,
 		&entity.FirstNote, &entity.TotalMentions, &entity.NarrativeID,
 		&entity.CreatedBy, &entity.CreatedAt, &entity.UpdatedAt,
@@ -849,13 +861,18 @@ func (s *SQLiteStore) GetEntityByLabel(label string) (*Entity, error) {
 	return &entity, nil
 }
 
-// DeleteEntity removes an entity by ID.
+// DeleteEntity removes an entity, its attributes and its embedding by ID.
 func (s *SQLiteStore) DeleteEntity(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.db.Exec("DELETE FROM entities WHERE id = ?", id)
-	return err
+	if _, err := s.db.Exec("DELETE FROM entities WHERE id = ?", id); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("DELETE FROM entity_attributes WHERE entity_id = ?", id); err != nil {
+		return err
+	}
+	return deleteEmbedding(s.db, EmbeddingEntity, id)
 }
 
 // ListEntities returns all entities, optionally filtered by kind.
@@ -1416,7 +1433,7 @@ func (s *SQLiteStore) GetMemory(id string) (*Memory, error) {
 	return &m, nil
 }
 
-// DeleteMemory removes a memory and its thread associations.
+// DeleteMemory removes a memory, its thread associations and its embedding.
 func (s *SQLiteStore) DeleteMemory(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -1427,8 +1444,10 @@ func (s *SQLiteStore) DeleteMemory(id string) error {
 	}
 
 	// Delete memory
-	_, err := s.db.Exec("DELETE FROM memories WHERE id = ?", id)
-	return err
+	if _, err := s.db.Exec("DELETE FROM memories WHERE id = ?", id); err != nil {
+		return err
+	}
+	return deleteEmbedding(s.db, EmbeddingMemory, id)
 }
 
 // GetMemoriesForThread returns all memories associated with a thread.
@@ -1505,210 +1524,45 @@ func (s *SQLiteStore) ListMemoriesByType(memoryType MemoryType) ([]*Memory, erro
 	return memories, rows.Err()
 }
 
-// Export serializes all database tables to JSON bytes.
-// This is a portable export that doesn't depend on sqlite3 serialization APIs.
-func (s *SQLiteStore) Export() ([]byte, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	type ExportData struct {
-		Notes    []*Note   `json:"notes"`
-		Entities []*Entity `json:"entities"`
-		Edges    []*Edge   `json:"edges"`
-		Folders  []*Folder `json:"folders"`
-	}
+// =============================================================================
+// Search Index Persistence
+// =============================================================================
 
-	var data ExportData
+// SaveSearchIndex stores (or replaces) a serialized search index snapshot.
+func (s *SQLiteStore) SaveSearchIndex(id string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Export notes - only current versions
-	noteRows, err := s.db.Query(`
-		SELECT id, version, world_id, title, content, markdown_content, folder_id, entity_kind,
-			   entity_subtype, is_entity, is_pinned, favorite, owner_id, created_at, updated_at,
-			   narrative_id, "order"
-		FROM notes WHERE is_current = 1
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("export notes: %w", err)
-	}
-	defer noteRows.Close()
-	for noteRows.Next() {
-		var n Note
-		var isEntity, isPinned, favorite int
-		if err := noteRows.Scan(
-			&n.ID, &n.Version, &n.WorldID, &n.Title, &n.Content, &n.MarkdownContent, &n.FolderID,
-			&n.EntityKind, &n.EntitySubtype, &isEntity, &isPinned, &favorite,
-			&n.OwnerID, &n.CreatedAt, &n.UpdatedAt, &n.NarrativeID, &n.Order,
-		); err != nil {
-			return nil, fmt.Errorf("scan note: %w", err)
-		}
-		n.IsEntity = isEntity == 1
-		n.IsPinned = isPinned == 1
-		n.Favorite = favorite == 1
-		n.IsCurrent = true
-		n.ValidFrom = n.CreatedAt
-		data.Notes = append(data.Notes, &n)
-	}
+	_, err := s.db.Exec(`
+		INSERT INTO search_indexes (id, data, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at
+	`, id, data, time.Now().UnixMilli())
+	return err
+}
 
-	// Export entities
-	entityRows, err := s.db.Query(`
-		SELECT id, label, kind, subtype, aliases, first_note, total_mentions,
-			   created_at, updated_at, created_by, narrative_id
-		FROM entities
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("export entities: %w", err)
-	}
-	defer entityRows.Close()
-	for entityRows.Next() {
-		var e Entity
-		var aliasesJSON string
-		if err := entityRows.Scan(
-			&e.ID, &e.Label, &e.Kind, &e.Subtype, &aliasesJSON,
-			&e.FirstNote, &e.TotalMentions, &e.CreatedAt, &e.UpdatedAt,
-			&e.CreatedBy, &e.NarrativeID,
-		); err != nil {
-			return nil, fmt.Errorf("scan entity: %w", err)
-		}
-		json.Unmarshal([]byte(aliasesJSON), &e.Aliases)
-		data.Entities = append(data.Entities, &e)
-	}
+// LoadSearchIndex returns a stored search index snapshot, or nil if none exists.
+func (s *SQLiteStore) LoadSearchIndex(id string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	// Export edges
-	edgeRows, err := s.db.Query(`
-		SELECT id, source_id, target_id, rel_type, confidence, bidirectional, source_note, created_at
-		FROM edges
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("export edges: %w", err)
-	}
-	defer edgeRows.Close()
-	for edgeRows.Next() {
-		var e Edge
-		var bidir int
-		if err := edgeRows.Scan(
-			&e.ID, &e.SourceID, &e.TargetID, &e.RelType, &e.Confidence,
-			&bidir, &e.SourceNote, &e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scan edge: %w", err)
-		}
-		e.Bidirectional = bidir == 1
-		data.Edges = append(data.Edges, &e)
+	var data []byte
+	err := s.db.QueryRow("SELECT data FROM search_indexes WHERE id = ?", id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-
-	// Export folders
-	folderRows, err := s.db.Query(`
-		SELECT id, name, parent_id, world_id, narrative_id, folder_order, created_at, updated_at
-		FROM folders
-	`)
 	if err != nil {
-		return nil, fmt.Errorf("export folders: %w", err)
-	}
-	defer folderRows.Close()
-	for folderRows.Next() {
-		var f Folder
-		if err := folderRows.Scan(
-			&f.ID, &f.Name, &f.ParentID, &f.WorldID, &f.NarrativeID,
-			&f.FolderOrder, &f.CreatedAt, &f.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scan folder: %w", err)
-		}
-		data.Folders = append(data.Folders, &f)
+		return nil, err
 	}
-
-	return json.Marshal(data)
+	return data, nil
 }
 
-// Import restores the database state from an exported JSON byte slice.
-// Clears all existing data and re-inserts from the export.
-func (s *SQLiteStore) Import(data []byte) error {
+// DeleteSearchIndex removes a stored search index snapshot.
+func (s *SQLiteStore) DeleteSearchIndex(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if len(data) == 0 {
-		return nil
-	}
-
-	type ExportData struct {
-		Notes    []*Note   `json:"notes"`
-		Entities []*Entity `json:"entities"`
-		Edges    []*Edge   `json:"edges"`
-		Folders  []*Folder `json:"folders"`
-	}
-
-	var importData ExportData
-	if err := json.Unmarshal(data, &importData); err != nil {
-		return fmt.Errorf("import unmarshal: %w", err)
-	}
-
-	// Clear all tables
-	for _, table := range []string{"edges", "entities", "folders", "notes"} {
-		if _, err := s.db.Exec("DELETE FROM " + table); err != nil {
-			return fmt.Errorf("clear %s: %w", table, err)
-		}
-	}
-
-	// Re-insert notes
-	for _, n := range importData.Notes {
-		version := n.Version
-		if version == 0 {
-			version = 1
-		}
-		validFrom := n.ValidFrom
-		if validFrom == 0 {
-			validFrom = n.CreatedAt
-		}
-		_, err := s.db.Exec(`
-			INSERT INTO notes (id, version, world_id, title, content, markdown_content, folder_id, entity_kind,
-				entity_subtype, is_entity, is_pinned, favorite, owner_id, created_at, updated_at,
-				narrative_id, "order", valid_from, is_current)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
-		`, n.ID, version, n.WorldID, n.Title, n.Content, n.MarkdownContent, n.FolderID,
-			n.EntityKind, n.EntitySubtype, boolToInt(n.IsEntity), boolToInt(n.IsPinned),
-			boolToInt(n.Favorite), n.OwnerID, n.CreatedAt, n.UpdatedAt, n.NarrativeID, n.Order, validFrom)
-		if err != nil {
-			return fmt.Errorf("import note %s: %w", n.ID, err)
-		}
-	}
-
-	// Re-insert entities
-	for _, e := range importData.Entities {
-		aliasesJSON, _ := json.Marshal(e.Aliases)
-		_, err := s.db.Exec(`
-			INSERT INTO entities (id, label, kind, subtype, aliases, first_note, total_mentions,
-				created_at, updated_at, created_by, narrative_id)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, e.ID, e.Label, e.Kind, e.Subtype, string(aliasesJSON),
-			e.FirstNote, e.TotalMentions, e.CreatedAt, e.UpdatedAt, e.CreatedBy, e.NarrativeID)
-		if err != nil {
-			return fmt.Errorf("import entity %s: %w", e.ID, err)
-		}
-	}
-
-	// Re-insert edges
-	for _, e := range importData.Edges {
-		_, err := s.db.Exec(`
-			INSERT INTO edges (id, source_id, target_id, rel_type, confidence, bidirectional, source_note, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		`, e.ID, e.SourceID, e.TargetID, e.RelType, e.Confidence,
-			boolToInt(e.Bidirectional), e.SourceNote, e.CreatedAt)
-		if err != nil {
-			return fmt.Errorf("import edge %s: %w", e.ID, err)
-		}
-	}
-
-	// Re-insert folders
-	for _, f := range importData.Folders {
-		_, err := s.db.Exec(`
-			INSERT INTO folders (id, name, parent_id, world_id, narrative_id, folder_order, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		`, f.ID, f.Name, f.ParentID, f.WorldID, f.NarrativeID,
-			f.FolderOrder, f.CreatedAt, f.UpdatedAt)
-		if err != nil {
-			return fmt.Errorf("import folder %s: %w", f.ID, err)
-		}
-	}
-
-	return nil
+	_, err := s.db.Exec("DELETE FROM search_indexes WHERE id = ?", id)
+	return err
 }
 
 // Compile-time interface check
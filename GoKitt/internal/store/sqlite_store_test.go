@@ -123,3 +123,52 @@ func TestFolderCRUD(t *testing.T) {
 		t.Errorf("Folder not deleted")
 	}
 }
+
+func TestSearchIndexPersistence(t *testing.T) {
+	s, err := NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	// Missing index returns nil, nil
+	data, err := s.LoadSearchIndex("main")
+	if err != nil || data != nil {
+		t.Fatalf("Expected nil for missing index, got %v, %v", data, err)
+	}
+
+	snapshot := []byte{'R', 'S', 'R', 'K', 0x01, 0x00, 0xff, 0x00}
+	if err := s.SaveSearchIndex("main", snapshot); err != nil {
+		t.Fatalf("SaveSearchIndex failed: %v", err)
+	}
+	replaced := append(snapshot, 0x42)
+	if err := s.SaveSearchIndex("main", replaced); err != nil {
+		t.Fatalf("SaveSearchIndex replace failed: %v", err)
+	}
+
+	// Snapshot survives Export/Import
+	exported, err := s.Export()
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	s2, err := NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("Failed to create second store: %v", err)
+	}
+	if err := s2.Import(exported); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	data, err = s2.LoadSearchIndex("main")
+	if err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+	if string(data) != string(replaced) {
+		t.Errorf("Expected %v, got %v", replaced, data)
+	}
+
+	if err := s2.DeleteSearchIndex("main"); err != nil {
+		t.Fatalf("DeleteSearchIndex failed: %v", err)
+	}
+	if data, _ := s2.LoadSearchIndex("main"); data != nil {
+		t.Errorf("Search index not deleted")
+	}
+}
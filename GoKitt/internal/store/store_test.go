@@ -247,6 +247,26 @@ func TestEntityGetByLabel(t *testing.T) {
 	assert.Nil(t, notFound)
 }
 
+func TestEntityGetByLabelInNarrative(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now().UnixMilli()
+	for _, e := range []*Entity{
+		{ID: "ash-kanto", Label: "Ash", Kind: "CHARACTER", NarrativeID: "kanto", CreatedAt: now, UpdatedAt: now},
+		{ID: "ash-shire", Label: "Ash", Kind: "CHARACTER", NarrativeID: "shire", CreatedAt: now, UpdatedAt: now},
+	} {
+		require.NoError(t, store.UpsertEntity(e))
+	}
+
+	shire, err := store.GetEntityByLabelInNarrative("ash", "shire")
+	require.NoError(t, err)
+	require.NotNil(t, shire)
+	assert.Equal(t, "ash-shire", shire.ID)
+
+	global, err := store.GetEntityByLabelInNarrative("ash", "")
+	require.NoError(t, err)
+	assert.Nil(t, global)
+}
+
 func TestEntityDelete(t *testing.T) {
 	store := newTestStore(t)
 	now := time.Now().UnixMilli()
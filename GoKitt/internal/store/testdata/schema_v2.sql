@@ -0,0 +1,167 @@
+-- Schema version 2: baseline tables plus search_indexes, tracked in PRAGMA user_version.
+
+-- Notes (Temporal versioning pattern)
+-- Composite primary key (id, version) enables full version history
+CREATE TABLE IF NOT EXISTS notes (
+    id TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    world_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    markdown_content TEXT,
+    folder_id TEXT,
+    entity_kind TEXT,
+    entity_subtype TEXT,
+    is_entity INTEGER DEFAULT 0,
+    is_pinned INTEGER DEFAULT 0,
+    favorite INTEGER DEFAULT 0,
+    owner_id TEXT,
+    narrative_id TEXT,
+    "order" REAL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    valid_from INTEGER NOT NULL,
+    valid_to INTEGER,
+    is_current INTEGER DEFAULT 1,
+    change_reason TEXT,
+    PRIMARY KEY (id, version)
+);
+
+-- Partial indexes for current versions (fast queries)
+CREATE INDEX IF NOT EXISTS idx_notes_current ON notes(id) WHERE is_current = 1;
+CREATE INDEX IF NOT EXISTS idx_notes_folder ON notes(folder_id) WHERE is_current = 1;
+CREATE INDEX IF NOT EXISTS idx_notes_narrative ON notes(narrative_id) WHERE is_current = 1;
+-- Index for history queries
+CREATE INDEX IF NOT EXISTS idx_notes_history ON notes(id, valid_from);
+
+-- Entities (Registry)
+CREATE TABLE IF NOT EXISTS entities (
+    id TEXT PRIMARY KEY,
+    label TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    subtype TEXT,
+    aliases TEXT,
+    first_note TEXT,
+    total_mentions INTEGER DEFAULT 0,
+    narrative_id TEXT,
+    created_by TEXT DEFAULT 'user',
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_entities_label ON entities(label);
+CREATE INDEX IF NOT EXISTS idx_entities_kind ON entities(kind);
+
+-- Edges (Graph)
+-- Note: No foreign keys - referential integrity managed at application level
+CREATE TABLE IF NOT EXISTS edges (
+    id TEXT PRIMARY KEY,
+    source_id TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    rel_type TEXT NOT NULL,
+    confidence REAL DEFAULT 1.0,
+    bidirectional INTEGER DEFAULT 0,
+    source_note TEXT,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_edges_source ON edges(source_id);
+CREATE INDEX IF NOT EXISTS idx_edges_target ON edges(target_id);
+
+-- Folders (Document hierarchy)
+CREATE TABLE IF NOT EXISTS folders (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    parent_id TEXT,
+    world_id TEXT NOT NULL,
+    narrative_id TEXT,
+    folder_order REAL DEFAULT 0,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_folders_parent ON folders(parent_id);
+CREATE INDEX IF NOT EXISTS idx_folders_world ON folders(world_id);
+
+-- =============================================================================
+-- Observational Memory Tables (Phase B)
+-- =============================================================================
+
+-- Threads: LLM conversation threads
+CREATE TABLE IF NOT EXISTS threads (
+    id TEXT PRIMARY KEY,
+    world_id TEXT,
+    narrative_id TEXT,
+    title TEXT,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_threads_world ON threads(world_id);
+CREATE INDEX IF NOT EXISTS idx_threads_narrative ON threads(narrative_id);
+
+-- ThreadMessages: Conversation history
+CREATE TABLE IF NOT EXISTS thread_messages (
+    id TEXT PRIMARY KEY,
+    thread_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    content TEXT NOT NULL,
+    narrative_id TEXT,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER,
+    is_streaming INTEGER DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_messages_thread ON thread_messages(thread_id);
+CREATE INDEX IF NOT EXISTS idx_thread_messages_narrative ON thread_messages(narrative_id);
+
+-- Memories: Extracted observations
+CREATE TABLE IF NOT EXISTS memories (
+    id TEXT PRIMARY KEY,
+    content TEXT NOT NULL,
+    memory_type TEXT NOT NULL,
+    confidence REAL DEFAULT 1.0,
+    source_role TEXT,
+    entity_id TEXT,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_memories_type ON memories(memory_type);
+CREATE INDEX IF NOT EXISTS idx_memories_entity ON memories(entity_id);
+
+-- MemoryThreads: Many-to-many junction table
+CREATE TABLE IF NOT EXISTS memory_threads (
+    memory_id TEXT NOT NULL,
+    thread_id TEXT NOT NULL,
+    message_id TEXT,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (memory_id, thread_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_threads_thread ON memory_threads(thread_id);
+CREATE INDEX IF NOT EXISTS idx_memory_threads_message ON memory_threads(message_id);
+
+-- Search index snapshots (migration 2)
+CREATE TABLE IF NOT EXISTS search_indexes (
+    id TEXT PRIMARY KEY,
+    data BLOB NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+-- Sample rows
+INSERT INTO notes (id, version, world_id, title, content, markdown_content, folder_id, entity_kind, entity_subtype,
+    is_entity, is_pinned, favorite, owner_id, narrative_id, "order", created_at, updated_at, valid_from, valid_to, is_current, change_reason)
+VALUES
+    ('note-1', 1, 'world-1', 'Old Title', 'old', '', '', '', '', 0, 0, 0, '', '', 0, 1000, 1000, 1000, 2000, 0, ''),
+    ('note-1', 2, 'world-1', 'New Title', 'new', '', '', '', '', 0, 0, 0, '', '', 0, 1000, 2000, 2000, NULL, 1, 'edit');
+INSERT INTO entities (id, label, kind, subtype, aliases, first_note, total_mentions, narrative_id, created_by, created_at, updated_at)
+VALUES ('entity-1', 'Kitt', 'CHARACTER', '', '["K"]', 'note-1', 3, '', 'user', 1000, 1000);
+INSERT INTO threads (id, world_id, narrative_id, title, created_at, updated_at)
+VALUES ('thread-1', 'world-1', '', 'Chat', 1000, 1000);
+INSERT INTO thread_messages (id, thread_id, role, content, narrative_id, created_at, updated_at, is_streaming)
+VALUES ('msg-1', 'thread-1', 'user', 'Hello', '', 1000, 0, 0);
+
+INSERT INTO search_indexes (id, data, updated_at) VALUES ('default', X'01', 1000);
+
+PRAGMA user_version = 2;
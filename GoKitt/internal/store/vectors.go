@@ -0,0 +1,271 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// embeddingTableNames maps each kind to its vec0 virtual table.
+var embeddingTableNames = map[EmbeddingKind]string{
+	EmbeddingNote:   "note_embeddings",
+	EmbeddingEntity: "entity_embeddings",
+	EmbeddingMemory: "memory_embeddings",
+}
+
+// embeddingKinds lists every kind in a stable order (export, import).
+var embeddingKinds = []EmbeddingKind{EmbeddingNote, EmbeddingEntity, EmbeddingMemory}
+
+// maxKNN is sqlite-vec's upper bound on k.
+const maxKNN = 4096
+
+// sqlExecutor is the subset of *sql.DB and *sql.Tx used by the vector helpers.
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func embeddingTable(kind EmbeddingKind) (string, error) {
+	table, ok := embeddingTableNames[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown embedding kind %q", kind)
+	}
+	return table, nil
+}
+
+// =============================================================================
+// Embedding CRUD
+// =============================================================================
+
+// UpsertEmbedding stores (or replaces) the vector for an item.
+// The first vector of a kind fixes that kind's dimension; later vectors must match
+// until ClearEmbeddings is called (e.g. after switching embedding models).
+func (s *SQLiteStore) UpsertEmbedding(kind EmbeddingKind, id, narrativeID string, vector []float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := upsertEmbedding(tx, kind, id, narrativeID, vector); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetEmbedding returns the stored vector for an item, or nil if none exists.
+func (s *SQLiteStore) GetEmbedding(kind EmbeddingKind, id string) ([]float32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	table, err := embeddingTable(kind)
+	if err != nil {
+		return nil, err
+	}
+	dims, err := embeddingDimensions(s.db, kind)
+	if err != nil || dims == 0 {
+		return nil, err
+	}
+
+	var blob []byte
+	err = s.db.QueryRow("SELECT embedding FROM "+table+" WHERE id = ?", id).Scan(&blob)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deserializeFloat32(blob)
+}
+
+// DeleteEmbedding removes the vector for an item.
+func (s *SQLiteStore) DeleteEmbedding(kind EmbeddingKind, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return deleteEmbedding(s.db, kind, id)
+}
+
+// ClearEmbeddings drops every vector of a kind and forgets its dimension.
+func (s *SQLiteStore) ClearEmbeddings(kind EmbeddingKind) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := dropEmbeddingTable(tx, kind); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SearchEmbeddings returns the k nearest items to query by cosine distance,
+// closest first. A non-empty narrativeID restricts the search to that partition.
+// Returns nil when nothing of this kind has been embedded yet.
+func (s *SQLiteStore) SearchEmbeddings(kind EmbeddingKind, query []float32, k int, narrativeID string) ([]VectorMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	table, err := embeddingTable(kind)
+	if err != nil {
+		return nil, err
+	}
+	if k <= 0 || len(query) == 0 {
+		return nil, nil
+	}
+	if k > maxKNN {
+		k = maxKNN
+	}
+
+	dims, err := embeddingDimensions(s.db, kind)
+	if err != nil || dims == 0 {
+		return nil, err
+	}
+	if len(query) != dims {
+		return nil, fmt.Errorf("%s query has %d dimensions, table has %d", kind, len(query), dims)
+	}
+
+	sqlQuery := "SELECT id, distance FROM " + table + " WHERE embedding MATCH ? AND k = ?"
+	args := []any{serializeFloat32(query), k}
+	if narrativeID != "" {
+		sqlQuery += " AND narrative_id = ?"
+		args = append(args, narrativeID)
+	}
+	sqlQuery += " ORDER BY distance"
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []VectorMatch
+	for rows.Next() {
+		var m VectorMatch
+		if err := rows.Scan(&m.ID, &m.Distance); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// =============================================================================
+// Helpers (shared with Export/Import and the cascading deletes)
+// =============================================================================
+
+// embeddingDimensions returns the dimension of a kind's vec0 table, or 0 if
+// the table hasn't been created yet.
+func embeddingDimensions(q sqlExecutor, kind EmbeddingKind) (int, error) {
+	var dims int
+	err := q.QueryRow("SELECT dimensions FROM embedding_tables WHERE kind = ?", string(kind)).Scan(&dims)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return dims, err
+}
+
+// ensureEmbeddingTable creates the vec0 table for a kind on first use and
+// checks the dimension against it afterwards.
+func ensureEmbeddingTable(tx *sql.Tx, kind EmbeddingKind, dims int) error {
+	table, err := embeddingTable(kind)
+	if err != nil {
+		return err
+	}
+	existing, err := embeddingDimensions(tx, kind)
+	if err != nil {
+		return err
+	}
+	if existing != 0 {
+		if existing != dims {
+			return fmt.Errorf("%s embedding has %d dimensions, table has %d (ClearEmbeddings to change models)",
+				kind, dims, existing)
+		}
+		return nil
+	}
+
+	// vec0 column types don't take bound parameters
+	if _, err := tx.Exec(fmt.Sprintf(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS %s USING vec0(
+			id TEXT PRIMARY KEY,
+			narrative_id TEXT PARTITION KEY,
+			embedding FLOAT[%d] distance_metric=cosine
+		)
+	`, table, dims)); err != nil {
+		return fmt.Errorf("create %s: %w", table, err)
+	}
+	_, err = tx.Exec("INSERT INTO embedding_tables (kind, dimensions) VALUES (?, ?)", string(kind), dims)
+	return err
+}
+
+func upsertEmbedding(tx *sql.Tx, kind EmbeddingKind, id, narrativeID string, vector []float32) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("%s embedding %s is empty", kind, id)
+	}
+	if err := ensureEmbeddingTable(tx, kind, len(vector)); err != nil {
+		return err
+	}
+	table, _ := embeddingTable(kind)
+
+	// vec0 has no upsert: replace by delete + insert
+	if _, err := tx.Exec("DELETE FROM "+table+" WHERE id = ?", id); err != nil {
+		return err
+	}
+	_, err := tx.Exec("INSERT INTO "+table+" (id, narrative_id, embedding) VALUES (?, ?, ?)",
+		id, narrativeID, serializeFloat32(vector))
+	return err
+}
+
+func deleteEmbedding(q sqlExecutor, kind EmbeddingKind, id string) error {
+	table, err := embeddingTable(kind)
+	if err != nil {
+		return err
+	}
+	dims, err := embeddingDimensions(q, kind)
+	if err != nil || dims == 0 {
+		return err
+	}
+	_, err = q.Exec("DELETE FROM "+table+" WHERE id = ?", id)
+	return err
+}
+
+func dropEmbeddingTable(tx *sql.Tx, kind EmbeddingKind) error {
+	table, err := embeddingTable(kind)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+		return fmt.Errorf("drop %s: %w", table, err)
+	}
+	_, err = tx.Exec("DELETE FROM embedding_tables WHERE kind = ?", string(kind))
+	return err
+}
+
+// serializeFloat32 encodes a vector in sqlite-vec's float32 blob format (little-endian).
+func serializeFloat32(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func deserializeFloat32(blob []byte) ([]float32, error) {
+	if len(blob)%4 != 0 {
+		return nil, fmt.Errorf("embedding blob length %d is not a multiple of 4", len(blob))
+	}
+	vector := make([]float32, len(blob)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[4*i:]))
+	}
+	return vector, nil
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddings_KNN(t *testing.T) {
+	store := newTestStore(t)
+
+	// Nothing embedded yet
+	matches, err := store.SearchEmbeddings(EmbeddingNote, []float32{1, 0, 0}, 5, "")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "a", "n1", []float32{1, 0, 0}))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "b", "n1", []float32{0, 1, 0}))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "c", "n2", []float32{0.9, 0.1, 0}))
+
+	matches, err = store.SearchEmbeddings(EmbeddingNote, []float32{1, 0, 0}, 2, "")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "a", matches[0].ID)
+	assert.Equal(t, "c", matches[1].ID)
+	assert.InDelta(t, 1.0, matches[0].Similarity(), 1e-6)
+
+	// Narrative partition
+	matches, err = store.SearchEmbeddings(EmbeddingNote, []float32{1, 0, 0}, 5, "n1")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "a", matches[0].ID)
+	assert.Equal(t, "b", matches[1].ID)
+
+	// Replace moves the vector
+	require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "b", "n1", []float32{1, 0, 0}))
+	vec, err := store.GetEmbedding(EmbeddingNote, "b")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, 0, 0}, vec)
+
+	// Kinds are independent
+	matches, err = store.SearchEmbeddings(EmbeddingEntity, []float32{1, 0, 0}, 5, "")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestEmbeddings_Dimensions(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.UpsertEmbedding(EmbeddingEntity, "e1", "", []float32{1, 0}))
+
+	assert.Error(t, store.UpsertEmbedding(EmbeddingEntity, "e2", "", []float32{1, 0, 0}))
+	_, err := store.SearchEmbeddings(EmbeddingEntity, []float32{1, 0, 0}, 5, "")
+	assert.Error(t, err)
+	assert.Error(t, store.UpsertEmbedding(EmbeddingEntity, "e3", "", nil))
+	assert.Error(t, store.UpsertEmbedding("bogus", "x", "", []float32{1}))
+
+	// Clearing lets a new model use another dimension
+	require.NoError(t, store.ClearEmbeddings(EmbeddingEntity))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingEntity, "e2", "", []float32{1, 0, 0}))
+	vec, err := store.GetEmbedding(EmbeddingEntity, "e1")
+	require.NoError(t, err)
+	assert.Nil(t, vec)
+}
+
+func TestEmbeddings_DeletedWithOwner(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.UpsertNote(&Note{ID: "n1", WorldID: "w1", Title: "T", Content: "c"}))
+	require.NoError(t, store.UpsertEntity(&Entity{ID: "e1", Label: "Kitt", Kind: "CHARACTER"}))
+	require.NoError(t, store.CreateMemory(&Memory{ID: "m1", Content: "fact", MemoryType: MemoryTypeFact}, "", ""))
+
+	require.NoError(t, store.UpsertEmbedding(EmbeddingNote, "n1", "", []float32{1, 0}))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingEntity, "e1", "", []float32{1, 0}))
+	require.NoError(t, store.UpsertEmbedding(EmbeddingMemory, "m1", "", []float32{1, 0}))
+
+	require.NoError(t, store.DeleteNote("n1"))
+	require.NoError(t, store.DeleteEntity("e1"))
+	require.NoError(t, store.DeleteMemory("m1"))
+
+	for _, kind := range embeddingKinds {
+		matches, err := store.SearchEmbeddings(kind, []float32{1, 0}, 5, "")
+		require.NoError(t, err)
+		assert.Empty(t, matches, kind)
+	}
+
+	// Deleting an item that was never embedded is fine
+	require.NoError(t, store.DeleteEmbedding(EmbeddingNote, "missing"))
+}
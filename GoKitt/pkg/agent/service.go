@@ -67,7 +67,7 @@ func NewService(b *batch.Service) *Service {
 // ChatWithTools performs a non-streaming LLM call that may return tool_calls.
 // This replaces openrouter.service.ts chatWithTools().
 //
-// The actual HTTP call goes through batch.Service which uses syscall/js fetch.
+// The actual HTTP call goes through batch.Service and its Transport.
 func (s *Service) ChatWithTools(
 	ctx context.Context,
 	messages []Message,
@@ -0,0 +1,52 @@
+//go:build !js || !wasm
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/batch"
+)
+
+func TestChatWithToolsOverHTTP(t *testing.T) {
+	var gotMessages []Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []Message       `json:"messages"`
+			Tools    json.RawMessage `json:"tools"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotMessages = body.Messages
+		w.Write([]byte(`{"choices":[{"message":{"content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"search_notes","arguments":"{\"query\":\"dragon\"}"}}]}}]}`))
+	}))
+	defer server.Close()
+
+	svc := NewService(batch.NewService(batch.Config{
+		Provider:          batch.ProviderOpenRouter,
+		OpenRouterAPIKey:  "sk-test",
+		OpenRouterModel:   "test/model",
+		OpenRouterBaseURL: server.URL,
+	}))
+
+	question := "Where is the dragon?"
+	result, err := svc.ChatWithTools(
+		context.Background(),
+		[]Message{{Role: "user", Content: &question}},
+		[]ToolDefinition{{Type: "function", Function: ToolFunctionSchema{Name: "search_notes", Parameters: json.RawMessage(`{}`)}}},
+		"You are helpful.",
+	)
+	if err != nil {
+		t.Fatalf("ChatWithTools failed: %v", err)
+	}
+
+	if len(gotMessages) != 2 || gotMessages[0].Role != "system" {
+		t.Errorf("expected system prompt prepended, got %+v", gotMessages)
+	}
+	if len(result.ToolCalls) != 1 || result.ToolCalls[0].Function.Name != "search_notes" {
+		t.Errorf("unexpected tool calls: %+v", result.ToolCalls)
+	}
+}
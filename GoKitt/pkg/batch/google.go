@@ -1,15 +1,15 @@
-//go:build js && wasm
-// +build js,wasm
-
 package batch
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"syscall/js"
 )
 
+// DefaultGoogleBaseURL is the Google GenAI REST endpoint.
+const DefaultGoogleBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
 // googleRequest represents the request body for Google GenAI API.
 type googleRequest struct {
 	Contents          []googleContent         `json:"contents"`
@@ -27,8 +27,9 @@ type googlePart struct {
 }
 
 type googleGenerationConfig struct {
-	Temperature     float64 `json:"temperature,omitempty"`
-	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	Temperature      float64 `json:"temperature,omitempty"`
+	MaxOutputTokens  int     `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType string  `json:"responseMimeType,omitempty"`
 }
 
 // googleResponse represents the response from Google GenAI API.
@@ -47,12 +48,41 @@ type googleResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// callGoogle makes a non-streaming request to Google GenAI API.
-func (s *Service) callGoogle(_ context.Context, userPrompt, systemPrompt string) (string, error) {
+// GoogleBackend calls the Google GenAI generateContent API.
+type GoogleBackend struct {
+	baseURL   string
+	apiKey    string
+	model     string
+	transport Transport
+}
+
+// NewGoogleBackend creates a Google GenAI backend. An empty baseURL uses DefaultGoogleBaseURL.
+func NewGoogleBackend(baseURL, apiKey, model string, transport Transport) *GoogleBackend {
+	if baseURL == "" {
+		baseURL = DefaultGoogleBaseURL
+	}
+	return &GoogleBackend{baseURL: baseURL, apiKey: apiKey, model: model, transport: transport}
+}
+
+func newGoogleBackend(config Config, transport Transport) (Backend, error) {
+	if config.GoogleAPIKey == "" {
+		return nil, ErrNotConfigured
+	}
+	return NewGoogleBackend(config.GoogleBaseURL, config.GoogleAPIKey, config.GoogleModel, transport), nil
+}
+
+// Model returns the configured model identifier.
+func (b *GoogleBackend) Model() string {
+	return b.model
+}
+
+// Complete makes a non-streaming request to Google GenAI API.
+func (b *GoogleBackend) Complete(ctx context.Context, r Request) (string, error) {
 	url := fmt.Sprintf(
-		"https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
-		s.config.GoogleModel,
-		s.config.GoogleAPIKey,
+		"%s/models/%s:generateContent?key=%s",
+		b.baseURL,
+		b.model,
+		b.apiKey,
 	)
 
 	// Build request body
@@ -60,19 +90,22 @@ func (s *Service) callGoogle(_ context.Context, userPrompt, systemPrompt string)
 		Contents: []googleContent{
 			{
 				Role:  "user",
-				Parts: []googlePart{{Text: userPrompt}},
+				Parts: []googlePart{{Text: r.UserPrompt}},
 			},
 		},
 		GenerationConfig: &googleGenerationConfig{
-			Temperature:     0.3,
-			MaxOutputTokens: 4096,
+			Temperature:     r.Temperature,
+			MaxOutputTokens: r.MaxTokens,
 		},
 	}
+	if r.JSONMode {
+		req.GenerationConfig.ResponseMimeType = "application/json"
+	}
 
 	// Add system prompt if provided
-	if systemPrompt != "" {
+	if r.SystemPrompt != "" {
 		req.SystemInstruction = &googleContent{
-			Parts: []googlePart{{Text: systemPrompt}},
+			Parts: []googlePart{{Text: r.SystemPrompt}},
 		}
 	}
 
@@ -81,8 +114,12 @@ func (s *Service) callGoogle(_ context.Context, userPrompt, systemPrompt string)
 		return "", fmt.Errorf("batch: failed to marshal Google request: %w", err)
 	}
 
-	// Use browser fetch via syscall/js
-	response, err := s.jsFetch(url, string(reqBody))
+	response, err := b.transport.Post(ctx, url, nil, string(reqBody))
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		// Google reports API errors as JSON bodies on non-2xx responses
+		response, err = httpErr.Body, nil
+	}
 	if err != nil {
 		return "", fmt.Errorf("batch: Google API request failed: %w", err)
 	}
@@ -106,69 +143,3 @@ func (s *Service) callGoogle(_ context.Context, userPrompt, systemPrompt string)
 	text := resp.Candidates[0].Content.Parts[0].Text
 	return text, nil
 }
-
-// jsFetch performs a fetch request using the browser's fetch API.
-func (s *Service) jsFetch(url, body string) (string, error) {
-	// Get fetch function from global scope
-	fetch := js.Global().Get("fetch")
-	if fetch.IsUndefined() {
-		return "", fmt.Errorf("batch: fetch not available")
-	}
-
-	// Create headers object
-	headers := js.Global().Get("Object").New()
-	headers.Set("Content-Type", "application/json")
-
-	// Create options object
-	options := js.Global().Get("Object").New()
-	options.Set("method", "POST")
-	options.Set("headers", headers)
-	options.Set("body", body)
-
-	// Call fetch
-	promise := fetch.Invoke(url, options)
-
-	// Wait for response using a channel
-	resultCh := make(chan struct {
-		response string
-		err      error
-	})
-
-	// Set up promise handlers
-	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		response := args[0]
-
-		// Get response text
-		textPromise := response.Call("text")
-
-		textThen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-			text := args[0].String()
-			resultCh <- struct {
-				response string
-				err      error
-			}{response: text, err: nil}
-			return nil
-		})
-		defer textThen.Release()
-
-		textPromise.Call("then", textThen)
-		return nil
-	})
-	defer then.Release()
-
-	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		errMsg := args[0].Get("message").String()
-		resultCh <- struct {
-			response string
-			err      error
-		}{response: "", err: fmt.Errorf("%s", errMsg)}
-		return nil
-	})
-	defer catch.Release()
-
-	promise.Call("then", then).Call("catch", catch)
-
-	// Wait for result
-	result := <-resultCh
-	return result.response, result.err
-}
@@ -0,0 +1,197 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultOpenRouterBaseURL is the OpenRouter API endpoint.
+	DefaultOpenRouterBaseURL = "https://openrouter.ai/api/v1"
+	// DefaultOpenAIBaseURL is the default OpenAI-compatible endpoint (a local llama.cpp/Ollama server).
+	DefaultOpenAIBaseURL = "http://localhost:8080/v1"
+)
+
+// chatRequest represents an OpenAI chat-completions request body.
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	Temperature    float64         `json:"temperature"`
+	MaxTokens      int             `json:"max_tokens"`
+	Stream         bool            `json:"stream"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+// chatResponse represents an OpenAI chat-completions response.
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// ChatCompletionsBackend speaks the OpenAI chat-completions protocol.
+// Serves OpenRouter as well as self-hosted llama.cpp, Ollama and vLLM endpoints.
+type ChatCompletionsBackend struct {
+	name      string            // For error messages, e.g. "OpenRouter"
+	url       string            // Full chat-completions endpoint
+	model     string            // Model identifier sent with each request
+	headers   map[string]string // Auth and attribution headers
+	transport Transport
+}
+
+// NewChatCompletionsBackend creates a backend for an OpenAI-compatible endpoint.
+// An empty apiKey sends no Authorization header (typical for local servers).
+func NewChatCompletionsBackend(baseURL, apiKey, model string, transport Transport) *ChatCompletionsBackend {
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	return &ChatCompletionsBackend{
+		name:      "OpenAI-compatible",
+		url:       strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		model:     model,
+		headers:   headers,
+		transport: transport,
+	}
+}
+
+// NewOpenRouterBackend creates a chat-completions backend with OpenRouter's headers.
+func NewOpenRouterBackend(baseURL, apiKey, model string, transport Transport) *ChatCompletionsBackend {
+	if baseURL == "" {
+		baseURL = DefaultOpenRouterBaseURL
+	}
+	return &ChatCompletionsBackend{
+		name:      "OpenRouter",
+		url:       strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		model:     model,
+		headers:   OpenRouterHeaders(apiKey),
+		transport: transport,
+	}
+}
+
+func newOpenRouterBackend(config Config, transport Transport) (Backend, error) {
+	if config.OpenRouterAPIKey == "" {
+		return nil, ErrNotConfigured
+	}
+	return NewOpenRouterBackend(config.OpenRouterBaseURL, config.OpenRouterAPIKey, config.OpenRouterModel, transport), nil
+}
+
+func newOpenAIBackend(config Config, transport Transport) (Backend, error) {
+	if config.OpenAIModel == "" {
+		return nil, ErrNotConfigured
+	}
+	return NewChatCompletionsBackend(config.OpenAIBaseURL, config.OpenAIAPIKey, config.OpenAIModel, transport), nil
+}
+
+// Model returns the configured model identifier.
+func (b *ChatCompletionsBackend) Model() string {
+	return b.model
+}
+
+// Complete makes a non-streaming chat-completions request and returns the message text.
+func (b *ChatCompletionsBackend) Complete(ctx context.Context, req Request) (string, error) {
+	// Build messages
+	messages := make([]chatMessage, 0, 2)
+	if req.SystemPrompt != "" {
+		messages = append(messages, chatMessage{
+			Role:    "system",
+			Content: req.SystemPrompt,
+		})
+	}
+	messages = append(messages, chatMessage{
+		Role:    "user",
+		Content: req.UserPrompt,
+	})
+
+	// Build request body
+	body := chatRequest{
+		Model:       b.model,
+		Messages:    messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      false, // EXPLICITLY NO STREAMING
+	}
+	if req.JSONMode {
+		body.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("batch: failed to marshal %s request: %w", b.name, err)
+	}
+
+	response, err := b.transport.Post(ctx, b.url, b.headers, string(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("batch: %s API request failed: %w", b.name, err)
+	}
+
+	// Parse response
+	var resp chatResponse
+	if err := json.Unmarshal([]byte(response), &resp); err != nil {
+		return "", fmt.Errorf("batch: failed to parse %s response: %w", b.name, err)
+	}
+
+	// Check for API error
+	if resp.Error != nil {
+		return "", fmt.Errorf("batch: %s API error %d: %s", b.name, resp.Error.Code, resp.Error.Message)
+	}
+
+	// Extract text from response
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("batch: empty response from %s", b.name)
+	}
+
+	text := resp.Choices[0].Message.Content
+	if text == "" {
+		return "", fmt.Errorf("batch: empty content in %s response", b.name)
+	}
+
+	return text, nil
+}
+
+// CompleteWithTools sends messages and tool schemas as-is and returns the raw response.
+func (b *ChatCompletionsBackend) CompleteWithTools(ctx context.Context, messages interface{}, tools interface{}) (string, error) {
+	// Build full request body
+	reqMap := map[string]interface{}{
+		"model":       b.model,
+		"messages":    messages,
+		"temperature": 0.7,
+		"max_tokens":  2048,
+		"stream":      false,
+	}
+	if tools != nil {
+		reqMap["tools"] = tools
+	}
+
+	reqBody, err := json.Marshal(reqMap)
+	if err != nil {
+		return "", fmt.Errorf("batch: failed to marshal tool request: %w", err)
+	}
+
+	raw, err := b.transport.Post(ctx, b.url, b.headers, string(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("batch: %s tool API request failed: %w", b.name, err)
+	}
+
+	return raw, nil
+}
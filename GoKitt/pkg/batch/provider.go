@@ -0,0 +1,85 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrNotConfigured is returned when the selected provider lacks credentials or a model.
+var ErrNotConfigured = errors.New("batch: provider not configured")
+
+// Request is a single non-streaming completion request.
+type Request struct {
+	SystemPrompt string
+	UserPrompt   string
+	Temperature  float64
+	MaxTokens    int
+	JSONMode     bool // Ask for a JSON object response where the backend supports it
+}
+
+// Backend is the implementation behind a Provider name.
+type Backend interface {
+	Complete(ctx context.Context, req Request) (string, error)
+	Model() string
+}
+
+// ToolBackend is implemented by backends that support OpenAI-style tool calling.
+// CompleteWithTools returns the raw chat-completions JSON so tool_calls survive.
+type ToolBackend interface {
+	Backend
+	CompleteWithTools(ctx context.Context, messages interface{}, tools interface{}) (string, error)
+}
+
+// BackendFactory builds a backend from config.
+// Returns ErrNotConfigured when the config lacks what the backend needs.
+type BackendFactory func(config Config, transport Transport) (Backend, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[Provider]BackendFactory)
+)
+
+// RegisterProvider makes a backend available under name, replacing any previous factory.
+func RegisterProvider(name Provider, factory BackendFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// LookupProvider returns the factory registered under name.
+func LookupProvider(name Provider) (BackendFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// Providers lists registered provider names in sorted order.
+func Providers() []Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]Provider, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// newBackend resolves a provider name to a configured backend.
+func newBackend(config Config, transport Transport) (Backend, error) {
+	factory, ok := LookupProvider(config.Provider)
+	if !ok {
+		return nil, fmt.Errorf("batch: unknown provider %q", config.Provider)
+	}
+	return factory(config, transport)
+}
+
+func init() {
+	RegisterProvider(ProviderGoogle, newGoogleBackend)
+	RegisterProvider(ProviderOpenRouter, newOpenRouterBackend)
+	RegisterProvider(ProviderOpenAI, newOpenAIBackend)
+}
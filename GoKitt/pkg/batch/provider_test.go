@@ -0,0 +1,95 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegisterCustomProvider(t *testing.T) {
+	fake := NewScriptedBackend("custom reply")
+	RegisterProvider("custom-test", func(config Config, _ Transport) (Backend, error) {
+		return fake, nil
+	})
+
+	found := false
+	for _, name := range Providers() {
+		if name == "custom-test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("custom provider not listed in %v", Providers())
+	}
+
+	svc := NewService(Config{Provider: "custom-test"})
+	if !svc.IsConfigured() {
+		t.Fatal("expected custom provider to be configured")
+	}
+	text, err := svc.Complete(context.Background(), "hello", "system")
+	if err != nil || text != "custom reply" {
+		t.Fatalf("unexpected result %q, %v", text, err)
+	}
+
+	reqs := fake.Requests()
+	if len(reqs) != 1 || reqs[0].UserPrompt != "hello" || reqs[0].SystemPrompt != "system" {
+		t.Errorf("unexpected recorded requests %+v", reqs)
+	}
+}
+
+func TestUnknownAndUnconfiguredProviders(t *testing.T) {
+	svc := NewService(Config{Provider: "nope"})
+	if svc.IsConfigured() {
+		t.Error("unknown provider should not be configured")
+	}
+	if _, err := svc.Complete(context.Background(), "hi", ""); err == nil || !strings.Contains(err.Error(), "unknown provider") {
+		t.Errorf("expected unknown provider error, got %v", err)
+	}
+
+	svc.UpdateConfig(Config{Provider: ProviderOpenRouter})
+	if _, err := svc.Complete(context.Background(), "hi", ""); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+
+	// OpenAI-compatible endpoints only need a model; the key is optional
+	svc.UpdateConfig(Config{Provider: ProviderOpenAI, OpenAIModel: "llama3"})
+	if !svc.IsConfigured() || svc.GetCurrentModel() != "llama3" {
+		t.Errorf("expected openai provider configured with llama3, got %q", svc.GetCurrentModel())
+	}
+}
+
+func TestScriptedBackendIsDeterministic(t *testing.T) {
+	fake := NewScriptedBackend("first", "second")
+	fake.Push(ScriptedReply{Err: errors.New("boom")})
+	svc := NewServiceWithBackend(fake)
+
+	for _, want := range []string{"first", "second"} {
+		got, err := svc.Complete(context.Background(), "q", "")
+		if err != nil || got != want {
+			t.Fatalf("expected %q, got %q (%v)", want, got, err)
+		}
+	}
+	if _, err := svc.Complete(context.Background(), "q", ""); err == nil || err.Error() != "boom" {
+		t.Errorf("expected scripted error, got %v", err)
+	}
+	if _, err := svc.Complete(context.Background(), "q", ""); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("expected ErrScriptExhausted, got %v", err)
+	}
+
+	fake.Push(ScriptedReply{Text: `{"choices":[]}`})
+	raw, err := svc.CompleteWithTools(context.Background(), []string{"m"}, nil)
+	if err != nil || raw != `{"choices":[]}` {
+		t.Errorf("unexpected tool reply %q, %v", raw, err)
+	}
+	if len(fake.ToolRequests()) != 1 {
+		t.Errorf("expected 1 recorded tool request")
+	}
+}
+
+func TestToolCallingRequiresToolBackend(t *testing.T) {
+	svc := NewService(Config{Provider: ProviderGoogle, GoogleAPIKey: "k", GoogleModel: "m"})
+	if _, err := svc.CompleteWithTools(context.Background(), nil, nil); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported tool calling error, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrScriptExhausted is returned by ScriptedBackend once every reply has been used.
+var ErrScriptExhausted = errors.New("batch: scripted backend has no more replies")
+
+// ScriptedReply is one canned response from a ScriptedBackend.
+type ScriptedReply struct {
+	Text string
+	Err  error
+}
+
+// ScriptedBackend is a deterministic fake for tests.
+// It returns its replies in order and records every request it receives.
+type ScriptedBackend struct {
+	mu       sync.Mutex
+	model    string
+	replies  []ScriptedReply
+	requests []Request
+	toolReqs []ScriptedToolRequest
+}
+
+// ScriptedToolRequest records a CompleteWithTools call.
+type ScriptedToolRequest struct {
+	Messages interface{}
+	Tools    interface{}
+}
+
+// NewScriptedBackend creates a fake that answers with texts in order.
+func NewScriptedBackend(texts ...string) *ScriptedBackend {
+	b := &ScriptedBackend{model: "scripted"}
+	for _, text := range texts {
+		b.replies = append(b.replies, ScriptedReply{Text: text})
+	}
+	return b
+}
+
+// Push appends a reply (text or error) to the script.
+func (b *ScriptedBackend) Push(reply ScriptedReply) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.replies = append(b.replies, reply)
+}
+
+// Model returns the fake model name.
+func (b *ScriptedBackend) Model() string {
+	return b.model
+}
+
+// Complete records the request and returns the next scripted reply.
+func (b *ScriptedBackend) Complete(_ context.Context, req Request) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests = append(b.requests, req)
+	return b.next()
+}
+
+// CompleteWithTools records the call and returns the next scripted reply as the raw response.
+func (b *ScriptedBackend) CompleteWithTools(_ context.Context, messages interface{}, tools interface{}) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.toolReqs = append(b.toolReqs, ScriptedToolRequest{Messages: messages, Tools: tools})
+	return b.next()
+}
+
+// Requests returns the Complete requests received so far.
+func (b *ScriptedBackend) Requests() []Request {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Request(nil), b.requests...)
+}
+
+// ToolRequests returns the CompleteWithTools calls received so far.
+func (b *ScriptedBackend) ToolRequests() []ScriptedToolRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]ScriptedToolRequest(nil), b.toolReqs...)
+}
+
+// Remaining reports how many replies are left.
+func (b *ScriptedBackend) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.replies)
+}
+
+func (b *ScriptedBackend) next() (string, error) {
+	if len(b.replies) == 0 {
+		return "", ErrScriptExhausted
+	}
+	reply := b.replies[0]
+	b.replies = b.replies[1:]
+	return reply.Text, reply.Err
+}
@@ -1,27 +1,28 @@
 // Package batch provides non-streaming LLM completion services.
 // Used for entity extraction, relation extraction, and other batch operations.
 //
-// Supports two providers:
+// Providers are looked up by name in a registry (see RegisterProvider).
+// Built in:
 //   - Google GenAI (generativelanguage.googleapis.com)
 //   - OpenRouter (openrouter.ai)
+//   - OpenAI-compatible chat completions (llama.cpp, Ollama, vLLM, ...)
 //
-// All HTTP calls use syscall/js to leverage the browser's fetch API,
-// avoiding CORS issues in WASM environment.
+// HTTP calls go through a Transport: the browser's fetch API in WASM
+// (avoiding CORS issues), net/http in native builds.
 package batch
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 )
 
-// Provider type for LLM providers.
+// Provider names a registered LLM backend.
 type Provider string
 
 const (
 	ProviderGoogle     Provider = "google"
 	ProviderOpenRouter Provider = "openrouter"
+	ProviderOpenAI     Provider = "openai" // Any OpenAI-compatible chat-completions server
 )
 
 // Config holds batch LLM settings passed from TypeScript.
@@ -31,21 +32,54 @@ type Config struct {
 	GoogleModel      string   `json:"googleModel"`
 	OpenRouterAPIKey string   `json:"openRouterApiKey"`
 	OpenRouterModel  string   `json:"openRouterModel"`
+
+	// OpenAI-compatible endpoint (self-hosted models); the key is optional
+	OpenAIBaseURL string `json:"openAiBaseUrl,omitempty"`
+	OpenAIAPIKey  string `json:"openAiApiKey,omitempty"`
+	OpenAIModel   string `json:"openAiModel,omitempty"`
+
+	// Optional endpoint overrides (proxies, local mock servers)
+	GoogleBaseURL     string `json:"googleBaseUrl,omitempty"`
+	OpenRouterBaseURL string `json:"openRouterBaseUrl,omitempty"`
 }
 
 // Service handles non-streaming LLM completions.
 type Service struct {
-	config Config
+	config     Config
+	transport  Transport
+	backend    Backend
+	backendErr error
 }
 
 // NewService creates a batch service with config from TypeScript.
+// Uses the platform's DefaultTransport.
 func NewService(config Config) *Service {
-	return &Service{config: config}
+	return NewServiceWithTransport(config, DefaultTransport())
+}
+
+// NewServiceWithTransport creates a batch service that sends requests through t.
+func NewServiceWithTransport(config Config, t Transport) *Service {
+	s := &Service{config: config, transport: t}
+	s.resolveBackend()
+	return s
+}
+
+// NewServiceWithBackend creates a batch service around an existing backend,
+// bypassing the registry (e.g. a ScriptedBackend in tests).
+func NewServiceWithBackend(b Backend) *Service {
+	return &Service{transport: DefaultTransport(), backend: b}
+}
+
+// SetTransport replaces the transport used for API calls.
+func (s *Service) SetTransport(t Transport) {
+	s.transport = t
+	s.resolveBackend()
 }
 
 // UpdateConfig updates the service configuration.
 func (s *Service) UpdateConfig(config Config) {
 	s.config = config
+	s.resolveBackend()
 }
 
 // GetConfig returns the current configuration.
@@ -53,87 +87,69 @@ func (s *Service) GetConfig() Config {
 	return s.config
 }
 
+// Backend returns the active backend, or nil if the provider isn't configured.
+func (s *Service) Backend() Backend {
+	return s.backend
+}
+
+// resolveBackend rebuilds the backend from the registry for the current config.
+func (s *Service) resolveBackend() {
+	s.backend, s.backendErr = newBackend(s.config, s.transport)
+}
+
 // IsConfigured checks if the current provider has valid credentials.
 func (s *Service) IsConfigured() bool {
-	switch s.config.Provider {
-	case ProviderGoogle:
-		return s.config.GoogleAPIKey != ""
-	case ProviderOpenRouter:
-		return s.config.OpenRouterAPIKey != ""
-	default:
-		return false
-	}
+	return s.backend != nil
 }
 
 // GetCurrentModel returns the model for the current provider.
 func (s *Service) GetCurrentModel() string {
-	switch s.config.Provider {
-	case ProviderGoogle:
-		return s.config.GoogleModel
-	case ProviderOpenRouter:
-		return s.config.OpenRouterModel
-	default:
+	if s.backend == nil {
 		return ""
 	}
+	return s.backend.Model()
+}
+
+// configError explains why there is no backend.
+func (s *Service) configError() error {
+	if s.backendErr != nil {
+		return s.backendErr
+	}
+	return ErrNotConfigured
 }
 
 // Complete makes a non-streaming LLM completion request.
 // Returns the full response text.
 func (s *Service) Complete(ctx context.Context, userPrompt, systemPrompt string) (string, error) {
-	if !s.IsConfigured() {
-		return "", errors.New("batch: provider not configured")
-	}
+	return s.CompleteRequest(ctx, Request{
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Temperature:  0.3,
+		MaxTokens:    4096,
+	})
+}
 
-	switch s.config.Provider {
-	case ProviderGoogle:
-		return s.callGoogle(ctx, userPrompt, systemPrompt)
-	case ProviderOpenRouter:
-		return s.callOpenRouter(ctx, userPrompt, systemPrompt)
-	default:
-		return "", errors.New("batch: unknown provider")
+// CompleteRequest makes a completion request with explicit generation settings.
+func (s *Service) CompleteRequest(ctx context.Context, req Request) (string, error) {
+	if !s.IsConfigured() {
+		return "", s.configError()
 	}
+	return s.backend.Complete(ctx, req)
 }
 
 // CompleteWithTools makes a non-streaming LLM request with tool schemas.
 // Accepts any messages/tools structure and returns the raw JSON response
 // for the caller to parse (preserves tool_calls in response).
 //
-// Only OpenRouter is supported for tool calling.
+// Only backends implementing ToolBackend support tool calling.
 func (s *Service) CompleteWithTools(ctx context.Context, messages interface{}, tools interface{}) (string, error) {
 	if !s.IsConfigured() {
-		return "", errors.New("batch: provider not configured")
-	}
-
-	if s.config.Provider != ProviderOpenRouter {
-		return "", errors.New("batch: tool calling only supported via OpenRouter")
-	}
-
-	// Build full request body
-	reqMap := map[string]interface{}{
-		"model":       s.config.OpenRouterModel,
-		"messages":    messages,
-		"temperature": 0.7,
-		"max_tokens":  2048,
-		"stream":      false,
-	}
-	if tools != nil {
-		reqMap["tools"] = tools
+		return "", s.configError()
 	}
 
-	reqBody, err := json.Marshal(reqMap)
-	if err != nil {
-		return "", fmt.Errorf("batch: failed to marshal tool request: %w", err)
+	toolBackend, ok := s.backend.(ToolBackend)
+	if !ok {
+		return "", fmt.Errorf("batch: tool calling not supported by provider %q", s.config.Provider)
 	}
-
-	// Use the same jsFetchWithAuth that callOpenRouter uses
-	raw, err := s.jsFetchWithAuth(
-		"https://openrouter.ai/api/v1/chat/completions",
-		string(reqBody),
-		s.config.OpenRouterAPIKey,
-	)
-	if err != nil {
-		return "", fmt.Errorf("batch: OpenRouter tool API request failed: %w", err)
-	}
-
-	return raw, nil
+	return toolBackend.CompleteWithTools(ctx, messages, tools)
 }
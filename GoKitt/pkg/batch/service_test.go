@@ -0,0 +1,175 @@
+//go:build !js || !wasm
+
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenRouterCompleteOverHTTP(t *testing.T) {
+	var gotAuth, gotPath string
+	var gotReq chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotReq)
+		w.Write([]byte(`{"choices":[{"message":{"content":"pong"}}]}`))
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{
+		Provider:          ProviderOpenRouter,
+		OpenRouterAPIKey:  "sk-test",
+		OpenRouterModel:   "test/model",
+		OpenRouterBaseURL: server.URL + "/api/v1",
+	})
+
+	text, err := svc.Complete(context.Background(), "ping", "be terse")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if text != "pong" {
+		t.Errorf("expected 'pong', got %q", text)
+	}
+	if gotAuth != "Bearer sk-test" {
+		t.Errorf("expected bearer auth, got %q", gotAuth)
+	}
+	if gotPath != "/api/v1/chat/completions" {
+		t.Errorf("unexpected path %q", gotPath)
+	}
+	if gotReq.Model != "test/model" || len(gotReq.Messages) != 2 || gotReq.Messages[0].Role != "system" {
+		t.Errorf("unexpected request body: %+v", gotReq)
+	}
+}
+
+func TestGoogleCompleteOverHTTP(t *testing.T) {
+	var gotPath, gotKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.URL.Query().Get("key")
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"hello"}]}}]}`))
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{
+		Provider:      ProviderGoogle,
+		GoogleAPIKey:  "g-key",
+		GoogleModel:   "gemini-test",
+		GoogleBaseURL: server.URL,
+	})
+
+	text, err := svc.Complete(context.Background(), "hi", "")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if text != "hello" {
+		t.Errorf("expected 'hello', got %q", text)
+	}
+	if gotPath != "/models/gemini-test:generateContent" || gotKey != "g-key" {
+		t.Errorf("unexpected request %s?key=%s", gotPath, gotKey)
+	}
+}
+
+func TestGoogleAPIErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":400,"message":"API key not valid","status":"INVALID_ARGUMENT"}}`))
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{Provider: ProviderGoogle, GoogleAPIKey: "bad", GoogleModel: "m", GoogleBaseURL: server.URL})
+
+	_, err := svc.Complete(context.Background(), "hi", "")
+	if err == nil || !strings.Contains(err.Error(), "API key not valid") {
+		t.Errorf("expected Google API error message, got %v", err)
+	}
+}
+
+func TestHTTPTransportStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPTransport(nil).Post(context.Background(), server.URL, nil, "{}")
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected *HTTPError, got %v", err)
+	}
+	if httpErr.StatusCode != http.StatusTooManyRequests || !strings.Contains(httpErr.Body, "rate limited") {
+		t.Errorf("unexpected error %+v", httpErr)
+	}
+}
+
+func TestCompleteWithToolsReturnsRawResponse(t *testing.T) {
+	const response = `{"choices":[{"message":{"content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"f","arguments":"{}"}}]}}]}`
+	var gotTools bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		_, gotTools = body["tools"]
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{
+		Provider:          ProviderOpenRouter,
+		OpenRouterAPIKey:  "sk-test",
+		OpenRouterModel:   "test/model",
+		OpenRouterBaseURL: server.URL,
+	})
+
+	raw, err := svc.CompleteWithTools(context.Background(), []map[string]string{{"role": "user", "content": "hi"}}, []string{"tool"})
+	if err != nil {
+		t.Fatalf("CompleteWithTools failed: %v", err)
+	}
+	if raw != response {
+		t.Errorf("expected raw response passthrough, got %q", raw)
+	}
+	if !gotTools {
+		t.Error("expected tools in request body")
+	}
+}
+
+func TestOpenAICompatibleProviderOverHTTP(t *testing.T) {
+	var gotAuth, gotPath string
+	var gotReq chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"ok\":true}"}}]}`))
+	}))
+	defer server.Close()
+
+	svc := NewService(Config{
+		Provider:      ProviderOpenAI,
+		OpenAIBaseURL: server.URL + "/v1/",
+		OpenAIModel:   "qwen2.5:7b",
+	})
+
+	text, err := svc.CompleteRequest(context.Background(), Request{UserPrompt: "json please", JSONMode: true})
+	if err != nil {
+		t.Fatalf("CompleteRequest failed: %v", err)
+	}
+	if text != `{"ok":true}` {
+		t.Errorf("unexpected text %q", text)
+	}
+	if gotPath != "/v1/chat/completions" {
+		t.Errorf("unexpected path %q", gotPath)
+	}
+	if gotAuth != "" {
+		t.Errorf("expected no auth header without a key, got %q", gotAuth)
+	}
+	if gotReq.Model != "qwen2.5:7b" || gotReq.ResponseFormat == nil || gotReq.ResponseFormat.Type != "json_object" {
+		t.Errorf("unexpected request %+v", gotReq)
+	}
+}
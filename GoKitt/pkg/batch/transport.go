@@ -0,0 +1,36 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+)
+
+// Transport posts a JSON request body and returns the raw response body.
+// WASM builds use the browser's fetch API; native builds use net/http.
+// Non-2xx responses are reported as *HTTPError.
+type Transport interface {
+	Post(ctx context.Context, url string, headers map[string]string, body string) (string, error)
+}
+
+// HTTPError is returned by a Transport when the server answers with a non-2xx status.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// OpenRouterHeaders returns the auth and attribution headers OpenRouter expects.
+// Also accepted by OpenAI-compatible servers, which ignore the extra headers.
+func OpenRouterHeaders(apiKey string) map[string]string {
+	headers := map[string]string{
+		"Authorization": "Bearer " + apiKey,
+		"X-Title":       "KittClouds",
+	}
+	if origin := defaultReferer(); origin != "" {
+		headers["HTTP-Referer"] = origin
+	}
+	return headers
+}
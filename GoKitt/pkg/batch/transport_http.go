@@ -0,0 +1,63 @@
+//go:build !js || !wasm
+
+package batch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HTTPTransport sends requests with net/http.
+// Lets extraction, memory and agent calls run from a CLI, a server or tests.
+type HTTPTransport struct {
+	Client *http.Client // nil uses http.DefaultClient
+}
+
+// NewHTTPTransport creates a transport using the given client (nil for the default).
+func NewHTTPTransport(client *http.Client) *HTTPTransport {
+	return &HTTPTransport{Client: client}
+}
+
+// Post sends a JSON POST request and returns the response body.
+func (t *HTTPTransport) Post(ctx context.Context, url string, headers map[string]string, body string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", &HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	return string(data), nil
+}
+
+// DefaultTransport returns the platform transport (net/http on native builds).
+func DefaultTransport() Transport {
+	return &HTTPTransport{}
+}
+
+// defaultReferer has no page origin to report outside the browser.
+func defaultReferer() string {
+	return ""
+}
@@ -0,0 +1,105 @@
+//go:build js && wasm
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"syscall/js"
+)
+
+// FetchTransport sends requests with the browser's fetch API,
+// avoiding CORS issues in the WASM environment.
+type FetchTransport struct{}
+
+// Post sends a JSON POST request via fetch and returns the response body.
+func (FetchTransport) Post(ctx context.Context, url string, headers map[string]string, body string) (string, error) {
+	fetch := js.Global().Get("fetch")
+	if fetch.IsUndefined() {
+		return "", fmt.Errorf("batch: fetch not available")
+	}
+
+	// Create headers object
+	jsHeaders := js.Global().Get("Object").New()
+	jsHeaders.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		jsHeaders.Set(key, value)
+	}
+
+	// Create options object
+	options := js.Global().Get("Object").New()
+	options.Set("method", "POST")
+	options.Set("headers", jsHeaders)
+	options.Set("body", body)
+
+	// Abort the request if the context is cancelled
+	if abortController := js.Global().Get("AbortController"); !abortController.IsUndefined() {
+		controller := abortController.New()
+		options.Set("signal", controller.Get("signal"))
+		stop := context.AfterFunc(ctx, func() { controller.Call("abort") })
+		defer stop()
+	}
+
+	// Call fetch
+	promise := fetch.Invoke(url, options)
+
+	// Wait for response using a channel
+	type fetchResult struct {
+		response string
+		err      error
+	}
+	resultCh := make(chan fetchResult, 1)
+
+	var textThen js.Func
+	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		response := args[0]
+		status := response.Get("status").Int()
+		ok := response.Get("ok").Bool()
+
+		textThen = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			text := args[0].String()
+			if !ok {
+				resultCh <- fetchResult{err: &HTTPError{StatusCode: status, Body: text}}
+			} else {
+				resultCh <- fetchResult{response: text}
+			}
+			return nil
+		})
+		response.Call("text").Call("then", textThen)
+		return nil
+	})
+	defer then.Release()
+
+	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		errMsg := args[0].Get("message").String()
+		resultCh <- fetchResult{err: fmt.Errorf("%s", errMsg)}
+		return nil
+	})
+	defer catch.Release()
+
+	promise.Call("then", then).Call("catch", catch)
+
+	// Wait for result (an aborted fetch lands in catch)
+	result := <-resultCh
+	if textThen.Truthy() {
+		textThen.Release()
+	}
+	if result.err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return result.response, result.err
+}
+
+// DefaultTransport returns the platform transport (browser fetch in WASM).
+func DefaultTransport() Transport {
+	return FetchTransport{}
+}
+
+// defaultReferer reports the page origin for OpenRouter attribution.
+func defaultReferer() string {
+	location := js.Global().Get("location")
+	if location.IsUndefined() {
+		return ""
+	}
+	return location.Get("origin").String()
+}
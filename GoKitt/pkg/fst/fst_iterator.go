@@ -170,6 +170,10 @@ func (i *FSTIterator) pointTo(key []byte) error {
 		return i.next(maxQ)
 	}
 
+	if fa, ok := i.aut.(FuzzyAutomaton); ok {
+		i.editDistance = fa.EditDistance(i.autStatesStack[len(i.autStatesStack)-1])
+	}
+
 	return nil
 }
 
@@ -0,0 +1,453 @@
+package vellum
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MaxLevenshteinDistance is the largest edit distance a
+// LevenshteinAutomatonBuilder can be configured with. The DFA grows quickly
+// with the distance, and anything beyond this is better served by a scan.
+const MaxLevenshteinDistance = 4
+
+// ErrLevenshteinDistance is returned when a requested edit distance exceeds
+// the builder's configured maximum.
+var ErrLevenshteinDistance = errors.New("levenshtein distance exceeds builder maximum")
+
+// LevenshteinAutomatonBuilder builds DFAs that accept every key within a
+// bounded edit distance of a query. Distances are measured in runes, while
+// the resulting automaton consumes UTF-8 bytes so it can drive FST.Search.
+type LevenshteinAutomatonBuilder struct {
+	maxDistance    uint8
+	transpositions bool
+}
+
+// NewLevenshteinAutomatonBuilder returns a builder for DFAs up to maxDistance
+// edits. With transpositions enabled, swapping two adjacent runes counts as a
+// single edit (optimal string alignment distance).
+func NewLevenshteinAutomatonBuilder(maxDistance uint8, transpositions bool) (*LevenshteinAutomatonBuilder, error) {
+	if maxDistance > MaxLevenshteinDistance {
+		return nil, ErrLevenshteinDistance
+	}
+	return &LevenshteinAutomatonBuilder{
+		maxDistance:    maxDistance,
+		transpositions: transpositions,
+	}, nil
+}
+
+// BuildDfa returns a DFA matching keys within distance edits of query.
+func (b *LevenshteinAutomatonBuilder) BuildDfa(query string, distance uint8) (*LevenshteinDFA, error) {
+	return b.build(query, distance, false)
+}
+
+// BuildPrefixDfa returns a DFA matching keys that start with some prefix
+// within distance edits of query, e.g. "arive" matches "arrives" at distance 1
+// once "arrive" has been consumed.
+func (b *LevenshteinAutomatonBuilder) BuildPrefixDfa(query string, distance uint8) (*LevenshteinDFA, error) {
+	return b.build(query, distance, true)
+}
+
+func (b *LevenshteinAutomatonBuilder) build(query string, distance uint8, prefix bool) (*LevenshteinDFA, error) {
+	if distance > b.maxDistance {
+		return nil, ErrLevenshteinDistance
+	}
+	rc := newLevRuneCompiler([]rune(query), distance, b.transpositions, prefix)
+	return rc.compile(), nil
+}
+
+// LevenshteinDFA is a byte-level automaton produced by a
+// LevenshteinAutomatonBuilder. It implements FuzzyAutomaton.
+//
+// State 0 is the dead state and state 1 is reserved (it collides with the
+// FST's noneAddr, which AutomatonContains treats as a dead end), so live
+// states start at 2.
+type LevenshteinDFA struct {
+	trans    []int32 // 256 transitions per state
+	distance []uint8 // edit distance reported for each state
+	match    []bool
+	always   []bool
+	maxDist  uint8
+}
+
+const (
+	levDeadState  = 0
+	levStartState = 2
+)
+
+// Start returns the start state
+func (d *LevenshteinDFA) Start() int {
+	return levStartState
+}
+
+// IsMatch returns true if the input consumed so far is within the distance
+func (d *LevenshteinDFA) IsMatch(s int) bool {
+	return d.match[s]
+}
+
+// CanMatch returns true if a match is still reachable from the state
+func (d *LevenshteinDFA) CanMatch(s int) bool {
+	return s > 1
+}
+
+// WillAlwaysMatch returns true once a prefix DFA has matched
+func (d *LevenshteinDFA) WillAlwaysMatch(s int) bool {
+	return d.always[s]
+}
+
+// Accept returns the next state for byte b
+func (d *LevenshteinDFA) Accept(s int, b byte) int {
+	return int(d.trans[s<<8|int(b)])
+}
+
+// EditDistance returns the edit distance of the input consumed so far. For
+// states that cannot match it returns one more than the DFA's distance.
+func (d *LevenshteinDFA) EditDistance(s int) uint8 {
+	return d.distance[s]
+}
+
+// MatchAndDistance runs input through the DFA and reports whether it matched
+// and at what distance.
+func (d *LevenshteinDFA) MatchAndDistance(input string) (bool, uint8) {
+	s := d.Start()
+	for i := 0; i < len(input) && s > 1; i++ {
+		s = d.Accept(s, input[i])
+	}
+	if s <= 1 {
+		return false, d.maxDist + 1
+	}
+	return d.match[s], d.distance[s]
+}
+
+// --- Rune-level construction ---
+
+// levRuneState is one state of the rune-level DFA: the capped DP row of
+// distances from every query prefix, plus what transpositions need.
+type levRuneState struct {
+	row  []uint8
+	prev []uint8 // previous row, only kept with transpositions
+	last int     // alphabet class of the last rune, -1 if none or foreign
+	best uint8   // prefix mode: best distance of any consumed prefix
+}
+
+func (s *levRuneState) key() string {
+	buf := make([]byte, 0, len(s.row)+len(s.prev)+3)
+	buf = append(buf, s.row...)
+	buf = append(buf, 0xFF)
+	buf = append(buf, s.prev...)
+	buf = append(buf, byte(s.last+1), s.best)
+	return string(buf)
+}
+
+type levRuneCompiler struct {
+	query          []int // query runes as alphabet classes
+	alphabet       []rune
+	classes        map[rune]int
+	distance       uint8
+	transpositions bool
+	prefix         bool
+
+	states []*levRuneState
+	index  map[string]int
+
+	dfa        *LevenshteinDFA
+	byteStates map[int]int32    // rune state -> byte state
+	trieStates map[string]int32 // (rune state, partial UTF-8) -> byte state
+	skipStates map[[2]int32]int32
+	pending    []int // rune states awaiting byte expansion
+}
+
+func newLevRuneCompiler(query []rune, distance uint8, transpositions, prefix bool) *levRuneCompiler {
+	alphabet := make([]rune, 0, len(query))
+	classes := make(map[rune]int, len(query))
+	for _, r := range query {
+		if _, ok := classes[r]; !ok {
+			classes[r] = 0
+			alphabet = append(alphabet, r)
+		}
+	}
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	for i, r := range alphabet {
+		classes[r] = i
+	}
+	q := make([]int, len(query))
+	for i, r := range query {
+		q[i] = classes[r]
+	}
+	return &levRuneCompiler{
+		query:          q,
+		alphabet:       alphabet,
+		classes:        classes,
+		distance:       distance,
+		transpositions: transpositions,
+		prefix:         prefix,
+		index:          make(map[string]int),
+		byteStates:     make(map[int]int32),
+		trieStates:     make(map[string]int32),
+		skipStates:     make(map[[2]int32]int32),
+	}
+}
+
+func (c *levRuneCompiler) limit() uint8 {
+	return c.distance + 1
+}
+
+// intern returns the index of s, or -1 if s can never match again.
+func (c *levRuneCompiler) intern(s *levRuneState) int {
+	lowest := c.limit()
+	for _, v := range s.row {
+		if v < lowest {
+			lowest = v
+		}
+	}
+	// The row minimum never decreases, so once it passes the limit only a
+	// prefix match already recorded in best can still succeed.
+	if lowest > c.distance {
+		if !c.prefix || s.best > c.distance {
+			return -1
+		}
+		s = &levRuneState{last: -1, best: s.best}
+	}
+	if s.last < 0 {
+		s.prev = nil
+	}
+	k := s.key()
+	if idx, ok := c.index[k]; ok {
+		return idx
+	}
+	idx := len(c.states)
+	c.states = append(c.states, s)
+	c.index[k] = idx
+	return idx
+}
+
+func (c *levRuneCompiler) start() int {
+	n := len(c.query)
+	row := make([]uint8, n+1)
+	for i := range row {
+		row[i] = capDistance(i, c.limit())
+	}
+	s := &levRuneState{row: row, last: -1, best: c.limit()}
+	if c.prefix {
+		s.best = row[n]
+	}
+	return c.intern(s)
+}
+
+// step advances rune state idx by a rune of the given class (-1 for a rune
+// outside the query alphabet).
+func (c *levRuneCompiler) step(idx, class int) int {
+	s := c.states[idx]
+	if s.row == nil {
+		// Prefix match already decided; nothing left to compute.
+		return idx
+	}
+	n := len(c.query)
+	lim := c.limit()
+	next := make([]uint8, n+1)
+	next[0] = minDistance(s.row[0]+1, lim)
+	for i := 1; i <= n; i++ {
+		cost := uint8(1)
+		if c.query[i-1] == class {
+			cost = 0
+		}
+		v := s.row[i] + 1
+		if w := next[i-1] + 1; w < v {
+			v = w
+		}
+		if w := s.row[i-1] + cost; w < v {
+			v = w
+		}
+		if c.transpositions && i >= 2 && s.prev != nil && class >= 0 &&
+			c.query[i-1] == s.last && c.query[i-2] == class {
+			if w := s.prev[i-2] + 1; w < v {
+				v = w
+			}
+		}
+		next[i] = minDistance(v, lim)
+	}
+
+	ns := &levRuneState{row: next, last: -1, best: s.best}
+	if c.transpositions && class >= 0 {
+		ns.prev = s.row
+		ns.last = class
+	}
+	if c.prefix && next[n] < ns.best {
+		ns.best = next[n]
+	}
+	return c.intern(ns)
+}
+
+func (c *levRuneCompiler) classOf(r rune) int {
+	if cls, ok := c.classes[r]; ok {
+		return cls
+	}
+	return -1
+}
+
+// stateDistance is the distance a rune state reports.
+func (c *levRuneCompiler) stateDistance(idx int) uint8 {
+	s := c.states[idx]
+	if c.prefix {
+		return s.best
+	}
+	return s.row[len(c.query)]
+}
+
+// --- Byte-level expansion ---
+
+func (c *levRuneCompiler) compile() *LevenshteinDFA {
+	c.dfa = &LevenshteinDFA{maxDist: c.distance}
+	// dead + reserved
+	c.newByteState(c.limit(), false, false)
+	c.newByteState(c.limit(), false, false)
+
+	start := c.start()
+	if start < 0 {
+		return c.dfa
+	}
+	c.byteStateFor(start)
+	for len(c.pending) > 0 {
+		idx := c.pending[0]
+		c.pending = c.pending[1:]
+		c.expand(idx)
+	}
+	return c.dfa
+}
+
+func (c *levRuneCompiler) newByteState(dist uint8, match, always bool) int32 {
+	id := int32(len(c.dfa.distance))
+	c.dfa.trans = append(c.dfa.trans, make([]int32, 256)...)
+	c.dfa.distance = append(c.dfa.distance, dist)
+	c.dfa.match = append(c.dfa.match, match)
+	c.dfa.always = append(c.dfa.always, always)
+	return id
+}
+
+// byteStateFor returns the byte state at the boundary of rune state idx,
+// queueing it for expansion the first time it is seen.
+func (c *levRuneCompiler) byteStateFor(idx int) int32 {
+	if idx < 0 {
+		return levDeadState
+	}
+	if id, ok := c.byteStates[idx]; ok {
+		return id
+	}
+	dist := c.stateDistance(idx)
+	match := dist <= c.distance
+	id := c.newByteState(dist, match, c.prefix && match)
+	c.byteStates[idx] = id
+	c.pending = append(c.pending, idx)
+	return id
+}
+
+func (c *levRuneCompiler) expand(idx int) {
+	base := int(c.byteStates[idx]) << 8
+	other := -2 // lazily computed step on a foreign rune
+	for b := 0; b < 256; b++ {
+		// Targets may grow c.dfa.trans, so index it afresh on every write.
+		var target int32
+		switch {
+		case b < utf8.RuneSelf:
+			target = c.byteStateFor(c.step(idx, c.classOf(rune(b))))
+		case b >= 0xC2 && b <= 0xF4:
+			if other == -2 {
+				other = c.step(idx, -1)
+			}
+			target = c.trieState(idx, []byte{byte(b)}, other)
+		default:
+			target = levDeadState
+		}
+		c.dfa.trans[base|b] = target
+	}
+}
+
+// trieState returns the byte state after reading the partial UTF-8 sequence
+// p from rune state idx. Sequences that cannot lead to a query rune collapse
+// into skip states that consume the remaining continuation bytes and then
+// land on other, the step taken on a foreign rune.
+func (c *levRuneCompiler) trieState(idx int, p []byte, other int) int32 {
+	total := utf8SeqLen(p[0])
+	if !c.isAlphabetPrefix(p) {
+		return c.skipState(total-len(p), c.byteStateFor(other))
+	}
+	key := strconv.Itoa(idx) + ":" + string(p)
+	if id, ok := c.trieStates[key]; ok {
+		return id
+	}
+	dist := c.stateDistance(idx)
+	id := c.newByteState(dist, false, false)
+	c.trieStates[key] = id
+	for b := 0x80; b <= 0xBF; b++ {
+		next := append(append([]byte{}, p...), byte(b))
+		var target int32
+		if len(next) == total {
+			r, size := utf8.DecodeRune(next)
+			if r == utf8.RuneError && size <= 1 {
+				target = levDeadState
+			} else {
+				target = c.byteStateFor(c.step(idx, c.classOf(r)))
+			}
+		} else {
+			target = c.trieState(idx, next, other)
+		}
+		c.dfa.trans[int(id)<<8|b] = target
+	}
+	return id
+}
+
+// skipState returns a state that consumes n continuation bytes and then
+// moves to target.
+func (c *levRuneCompiler) skipState(n int, target int32) int32 {
+	if n == 0 || target == levDeadState {
+		return target
+	}
+	key := [2]int32{int32(n), target}
+	if id, ok := c.skipStates[key]; ok {
+		return id
+	}
+	next := c.skipState(n-1, target)
+	id := c.newByteState(c.dfa.distance[target], false, false)
+	c.skipStates[key] = id
+	for b := 0x80; b <= 0xBF; b++ {
+		c.dfa.trans[int(id)<<8|b] = next
+	}
+	return id
+}
+
+func (c *levRuneCompiler) isAlphabetPrefix(p []byte) bool {
+	var buf [utf8.UTFMax]byte
+	for _, r := range c.alphabet {
+		n := utf8.EncodeRune(buf[:], r)
+		if n >= len(p) && string(buf[:len(p)]) == string(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func utf8SeqLen(lead byte) int {
+	switch {
+	case lead < 0xE0:
+		return 2
+	case lead < 0xF0:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func capDistance(v int, lim uint8) uint8 {
+	if v > int(lim) {
+		return lim
+	}
+	return uint8(v)
+}
+
+func minDistance(v, lim uint8) uint8 {
+	if v > lim {
+		return lim
+	}
+	return v
+}
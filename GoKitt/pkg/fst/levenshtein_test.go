@@ -0,0 +1,152 @@
+package vellum
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// osaDistance is the reference optimal string alignment distance over runes.
+func osaDistance(a, b string, transpositions bool) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if transpositions && i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func randomWord(rng *rand.Rand, alphabet []rune, maxLen int) string {
+	n := rng.Intn(maxLen + 1)
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+	return string(out)
+}
+
+func TestLevenshteinDFAMatchesReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	alphabet := []rune{'a', 'b', 'c', 'é', '’', '火'}
+
+	for _, transpositions := range []bool{false, true} {
+		lb, err := NewLevenshteinAutomatonBuilder(2, transpositions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for q := 0; q < 40; q++ {
+			query := randomWord(rng, alphabet, 5)
+			for dist := uint8(0); dist <= 2; dist++ {
+				dfa, err := lb.BuildDfa(query, dist)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for k := 0; k < 80; k++ {
+					key := randomWord(rng, alphabet, 7)
+					want := osaDistance(query, key, transpositions)
+					matched, got := dfa.MatchAndDistance(key)
+					if matched != (want <= int(dist)) {
+						t.Fatalf("query=%q key=%q dist=%d trans=%v: matched=%v, reference distance %d",
+							query, key, dist, transpositions, matched, want)
+					}
+					if matched && int(got) != want {
+						t.Fatalf("query=%q key=%q: distance %d, want %d", query, key, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLevenshteinPrefixDFA(t *testing.T) {
+	lb, err := NewLevenshteinAutomatonBuilder(2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dfa, err := lb.BuildPrefixDfa("arive", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]bool{
+		"arrives":  true,
+		"arrival":  false, // every prefix is two edits away
+		"ariv":     true,  // one deletion away
+		"arr":      false,
+		"departed": false,
+	}
+	for key, want := range cases {
+		if got, _ := dfa.MatchAndDistance(key); got != want {
+			t.Errorf("prefix match %q = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestLevenshteinBuilderLimits(t *testing.T) {
+	if _, err := NewLevenshteinAutomatonBuilder(MaxLevenshteinDistance+1, false); err != ErrLevenshteinDistance {
+		t.Fatalf("expected ErrLevenshteinDistance, got %v", err)
+	}
+	lb, _ := NewLevenshteinAutomatonBuilder(1, false)
+	if _, err := lb.BuildDfa("kill", 2); err != ErrLevenshteinDistance {
+		t.Fatalf("expected ErrLevenshteinDistance, got %v", err)
+	}
+}
+
+func TestSearchFuzzyOverFST(t *testing.T) {
+	data := map[string]uint64{
+		"attack": 1, "betray": 2, "fight": 3, "fought": 4,
+		"kill": 5, "killer": 6, "skill": 7, "slay": 8,
+	}
+	fstBytes, err := BuildSortedFST(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ir, err := OpenIndex(fstBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.Close()
+
+	matches, err := ir.SearchFuzzy("kil", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, m := range matches {
+		keys = append(keys, m.Key)
+		if m.Val != data[m.Key] {
+			t.Errorf("%s: value %d, want %d", m.Key, m.Val, data[m.Key])
+		}
+		if m.Distance != 1 {
+			t.Errorf("%s: distance %d, want 1", m.Key, m.Distance)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) != 1 || keys[0] != "kill" {
+		t.Errorf("SearchFuzzy(kil, 1) = %v, want [kill]", keys)
+	}
+
+	matches, err = ir.SearchFuzzy("betary", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Key != "betray" {
+		t.Errorf("transposition lookup = %+v, want betray", matches)
+	}
+}
@@ -0,0 +1,449 @@
+package vellum
+
+import (
+	"errors"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultRegexpStateLimit bounds the number of DFA states NewRegexp will
+// build before giving up.
+const DefaultRegexpStateLimit = 10000
+
+// ErrRegexpTooManyStates is returned when a pattern needs more DFA states than
+// the configured limit.
+var ErrRegexpTooManyStates = errors.New("regexp compiles to too many states")
+
+// ErrRegexpZeroWidth is returned for patterns using anchors or word boundary
+// assertions. Patterns always match whole keys, so these are not supported.
+var ErrRegexpZeroWidth = errors.New("zero width assertions not allowed")
+
+// Regexp is a byte-level DFA compiled from a Go (RE2) regular expression. It
+// implements Automaton, so it can drive FST.Search directly. A key matches
+// only if the entire key matches the pattern.
+//
+// As with LevenshteinDFA, state 0 is dead and state 1 is reserved.
+type Regexp struct {
+	orig   string
+	trans  []int32 // 256 transitions per state
+	match  []bool
+	live   []bool
+	always []bool
+}
+
+const (
+	reDeadState  = 0
+	reStartState = 2
+)
+
+// NewRegexp compiles expr using DefaultRegexpStateLimit.
+func NewRegexp(expr string) (*Regexp, error) {
+	return NewRegexpWithLimit(expr, DefaultRegexpStateLimit)
+}
+
+// NewRegexpWithLimit compiles expr, failing with ErrRegexpTooManyStates if the
+// DFA would need more than limit states.
+func NewRegexpWithLimit(expr string, limit int) (*Regexp, error) {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	c := &reCompiler{}
+	matchPC := c.emit(reInst{op: reOpMatch})
+	startPC, err := c.compile(parsed.Simplify(), matchPC)
+	if err != nil {
+		return nil, err
+	}
+	rv := &Regexp{orig: expr}
+	if err := rv.determinize(c.insts, startPC, limit); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// String returns the source pattern
+func (r *Regexp) String() string {
+	return r.orig
+}
+
+// Start returns the start state
+func (r *Regexp) Start() int {
+	return reStartState
+}
+
+// IsMatch returns true if the input consumed so far matches the pattern
+func (r *Regexp) IsMatch(s int) bool {
+	return r.match[s]
+}
+
+// CanMatch returns true if a match is still reachable from the state
+func (r *Regexp) CanMatch(s int) bool {
+	return r.live[s]
+}
+
+// WillAlwaysMatch returns true if every continuation from the state matches
+func (r *Regexp) WillAlwaysMatch(s int) bool {
+	return r.always[s]
+}
+
+// Accept returns the next state for byte b
+func (r *Regexp) Accept(s int, b byte) int {
+	return int(r.trans[s<<8|int(b)])
+}
+
+// --- NFA compilation ---
+
+type reOp uint8
+
+const (
+	reOpMatch reOp = iota
+	reOpFail
+	reOpSplit // epsilon to out and out1
+	reOpRange // byte in [lo, hi] to out
+)
+
+type reInst struct {
+	op     reOp
+	lo, hi byte
+	out    int
+	out1   int
+}
+
+// reCompiler builds a byte-level Thompson NFA. Each expression is compiled
+// back to front: compile receives the state to continue with and returns the
+// entry state of the expression.
+type reCompiler struct {
+	insts []reInst
+}
+
+func (c *reCompiler) emit(i reInst) int {
+	c.insts = append(c.insts, i)
+	return len(c.insts) - 1
+}
+
+func (c *reCompiler) compile(re *syntax.Regexp, next int) (int, error) {
+	var err error
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return c.emit(reInst{op: reOpFail}), nil
+	case syntax.OpEmptyMatch:
+		return next, nil
+	case syntax.OpLiteral:
+		for i := len(re.Rune) - 1; i >= 0; i-- {
+			r := re.Rune[i]
+			ranges := []rune{r, r}
+			if re.Flags&syntax.FoldCase != 0 {
+				ranges = foldRanges(r)
+			}
+			next = c.compileClass(ranges, next)
+		}
+		return next, nil
+	case syntax.OpCharClass:
+		return c.compileClass(re.Rune, next), nil
+	case syntax.OpAnyCharNotNL:
+		return c.compileClass([]rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}, next), nil
+	case syntax.OpAnyChar:
+		return c.compileClass([]rune{0, unicode.MaxRune}, next), nil
+	case syntax.OpCapture:
+		return c.compile(re.Sub[0], next)
+	case syntax.OpConcat:
+		for i := len(re.Sub) - 1; i >= 0; i-- {
+			if next, err = c.compile(re.Sub[i], next); err != nil {
+				return 0, err
+			}
+		}
+		return next, nil
+	case syntax.OpAlternate:
+		entry, err := c.compile(re.Sub[len(re.Sub)-1], next)
+		if err != nil {
+			return 0, err
+		}
+		for i := len(re.Sub) - 2; i >= 0; i-- {
+			alt, err := c.compile(re.Sub[i], next)
+			if err != nil {
+				return 0, err
+			}
+			entry = c.emit(reInst{op: reOpSplit, out: alt, out1: entry})
+		}
+		return entry, nil
+	case syntax.OpStar:
+		loop := c.emit(reInst{op: reOpSplit})
+		body, err := c.compile(re.Sub[0], loop)
+		if err != nil {
+			return 0, err
+		}
+		c.insts[loop].out = body
+		c.insts[loop].out1 = next
+		return loop, nil
+	case syntax.OpPlus:
+		loop := c.emit(reInst{op: reOpSplit})
+		body, err := c.compile(re.Sub[0], loop)
+		if err != nil {
+			return 0, err
+		}
+		c.insts[loop].out = body
+		c.insts[loop].out1 = next
+		return body, nil
+	case syntax.OpQuest:
+		body, err := c.compile(re.Sub[0], next)
+		if err != nil {
+			return 0, err
+		}
+		return c.emit(reInst{op: reOpSplit, out: body, out1: next}), nil
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText,
+		syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return 0, ErrRegexpZeroWidth
+	}
+	return 0, errors.New("unsupported regexp op: " + re.Op.String())
+}
+
+// compileClass compiles a set of rune ranges (as pairs) into alternatives of
+// UTF-8 byte range sequences.
+func (c *reCompiler) compileClass(ranges []rune, next int) int {
+	entry := -1
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for _, seq := range utf8Sequences(ranges[i], ranges[i+1]) {
+			n := next
+			for k := len(seq) - 1; k >= 0; k-- {
+				n = c.emit(reInst{op: reOpRange, lo: seq[k][0], hi: seq[k][1], out: n})
+			}
+			if entry < 0 {
+				entry = n
+			} else {
+				entry = c.emit(reInst{op: reOpSplit, out: n, out1: entry})
+			}
+		}
+	}
+	if entry < 0 {
+		return c.emit(reInst{op: reOpFail})
+	}
+	return entry
+}
+
+// foldRanges returns the case folding orbit of r as rune range pairs.
+func foldRanges(r rune) []rune {
+	orbit := []rune{r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		orbit = append(orbit, f)
+	}
+	sort.Slice(orbit, func(i, j int) bool { return orbit[i] < orbit[j] })
+	out := make([]rune, 0, 2*len(orbit))
+	for _, f := range orbit {
+		out = append(out, f, f)
+	}
+	return out
+}
+
+// utf8Sequences splits the rune range [lo, hi] into sequences of byte ranges
+// whose concatenations cover exactly the UTF-8 encodings of the range,
+// skipping surrogates.
+func utf8Sequences(lo, hi rune) [][][2]byte {
+	var out [][][2]byte
+	var split func(lo, hi rune)
+	split = func(lo, hi rune) {
+		if lo > hi {
+			return
+		}
+		if lo < 0xD800 && hi > 0xDFFF {
+			split(lo, 0xD7FF)
+			split(0xE000, hi)
+			return
+		}
+		if lo >= 0xD800 && hi <= 0xDFFF {
+			return
+		}
+		if lo >= 0xD800 && lo <= 0xDFFF {
+			lo = 0xE000
+		}
+		if hi >= 0xD800 && hi <= 0xDFFF {
+			hi = 0xD7FF
+		}
+		if lo > hi {
+			return
+		}
+		// Split on encoded length boundaries.
+		for _, m := range []rune{0x7F, 0x7FF, 0xFFFF} {
+			if lo <= m && hi > m {
+				split(lo, m)
+				split(m+1, hi)
+				return
+			}
+		}
+		if hi < utf8.RuneSelf {
+			out = append(out, [][2]byte{{byte(lo), byte(hi)}})
+			return
+		}
+		// Split until every continuation byte covers its full range or a
+		// single value.
+		n := utf8.RuneLen(lo)
+		for i := 1; i < n; i++ {
+			m := rune(1)<<(6*uint(i)) - 1
+			if lo&^m != hi&^m {
+				if lo&m != 0 {
+					split(lo, lo|m)
+					split((lo|m)+1, hi)
+					return
+				}
+				if hi&m != m {
+					split(lo, (hi&^m)-1)
+					split(hi&^m, hi)
+					return
+				}
+			}
+		}
+		var a, b [utf8.UTFMax]byte
+		utf8.EncodeRune(a[:], lo)
+		utf8.EncodeRune(b[:], hi)
+		seq := make([][2]byte, n)
+		for i := 0; i < n; i++ {
+			seq[i] = [2]byte{a[i], b[i]}
+		}
+		out = append(out, seq)
+	}
+	split(lo, hi)
+	return out
+}
+
+// --- Subset construction ---
+
+func (r *Regexp) determinize(insts []reInst, startPC, limit int) error {
+	index := make(map[string]int)
+	var sets [][]int
+
+	addState := func(set []int) (int, error) {
+		if len(set) == 0 {
+			return reDeadState, nil
+		}
+		k := reSetKey(set)
+		if id, ok := index[k]; ok {
+			return id, nil
+		}
+		if len(sets) >= limit {
+			return 0, ErrRegexpTooManyStates
+		}
+		id := len(r.match)
+		index[k] = id
+		sets = append(sets, set)
+		matched := false
+		for _, pc := range set {
+			if insts[pc].op == reOpMatch {
+				matched = true
+			}
+		}
+		r.trans = append(r.trans, make([]int32, 256)...)
+		r.match = append(r.match, matched)
+		return id, nil
+	}
+
+	// dead + reserved
+	r.trans = make([]int32, 2*256)
+	r.match = []bool{false, false}
+	sets = [][]int{nil, nil}
+
+	if _, err := addState(reClosure(insts, []int{startPC})); err != nil {
+		return err
+	}
+	if len(r.match) == reStartState {
+		// The pattern can never match; keep a dead start state.
+		r.trans = append(r.trans, make([]int32, 256)...)
+		r.match = append(r.match, false)
+		sets = append(sets, nil)
+	}
+
+	for s := reStartState; s < len(sets); s++ {
+		set := sets[s]
+		for b := 0; b < 256; b++ {
+			var outs []int
+			for _, pc := range set {
+				in := insts[pc]
+				if in.op == reOpRange && byte(b) >= in.lo && byte(b) <= in.hi {
+					outs = append(outs, in.out)
+				}
+			}
+			if len(outs) == 0 {
+				continue
+			}
+			next, err := addState(reClosure(insts, outs))
+			if err != nil {
+				return err
+			}
+			r.trans[s<<8|b] = int32(next)
+		}
+	}
+
+	r.computeLiveness()
+	return nil
+}
+
+// computeLiveness marks states from which a match is reachable, and states
+// that match no matter what follows.
+func (r *Regexp) computeLiveness() {
+	n := len(r.match)
+	r.live = make([]bool, n)
+	r.always = make([]bool, n)
+	copy(r.live, r.match)
+	for changed := true; changed; {
+		changed = false
+		for s := reStartState; s < n; s++ {
+			if r.live[s] {
+				continue
+			}
+			for b := 0; b < 256; b++ {
+				if r.live[r.trans[s<<8|b]] {
+					r.live[s] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for s := reStartState; s < n; s++ {
+		if !r.match[s] {
+			continue
+		}
+		always := true
+		for b := 0; b < 256 && always; b++ {
+			always = int(r.trans[s<<8|b]) == s
+		}
+		r.always[s] = always
+	}
+}
+
+// reClosure follows epsilon transitions and returns the sorted set of range
+// and match instructions reachable from pcs.
+func reClosure(insts []reInst, pcs []int) []int {
+	seen := make(map[int]bool)
+	var out []int
+	stack := append([]int(nil), pcs...)
+	for len(stack) > 0 {
+		pc := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[pc] {
+			continue
+		}
+		seen[pc] = true
+		switch insts[pc].op {
+		case reOpSplit:
+			stack = append(stack, insts[pc].out, insts[pc].out1)
+		case reOpRange, reOpMatch:
+			out = append(out, pc)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func reSetKey(set []int) string {
+	var sb strings.Builder
+	for i, pc := range set {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(pc))
+	}
+	return sb.String()
+}
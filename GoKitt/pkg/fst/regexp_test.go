@@ -0,0 +1,79 @@
+package vellum
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRegexpMatchesStdlib(t *testing.T) {
+	keys := []string{
+		"", "a", "ab", "abc", "abcabc", "arrive", "arrived", "arrives",
+		"Aria", "aria", "ARIA", "Arias", "çafé", "café", "cafe", "火山",
+		"kill", "killed", "x\ny", "123", "a1b2",
+	}
+	patterns := []string{
+		"a", "ab*", "(ab|c)+", "arriv(e|ed|es)?", "(?i)aria", "caf.",
+		"[a-c]+", "[^a-z]+", "\\d+", "[a-z]\\d[a-z]\\d", ".*", "x.y",
+		"(?s)x.y", "火.", "[à-ÿ]af.", "kill(ed)?|slay",
+	}
+	for _, pattern := range patterns {
+		re, err := NewRegexp(pattern)
+		if err != nil {
+			t.Fatalf("NewRegexp(%q): %v", pattern, err)
+		}
+		std := regexp.MustCompile("^(?:" + pattern + ")$")
+		for _, key := range keys {
+			want := std.MatchString(key)
+			if got := AutomatonContains(re, []byte(key)); got != want {
+				t.Errorf("pattern %q key %q: got %v, want %v", pattern, key, got, want)
+			}
+		}
+	}
+}
+
+func TestRegexpRejectsAssertions(t *testing.T) {
+	for _, pattern := range []string{"^a", "a$", "\\bword"} {
+		if _, err := NewRegexp(pattern); err != ErrRegexpZeroWidth {
+			t.Errorf("NewRegexp(%q) err = %v, want ErrRegexpZeroWidth", pattern, err)
+		}
+	}
+}
+
+func TestRegexpStateLimit(t *testing.T) {
+	if _, err := NewRegexpWithLimit("(a|b)*a(a|b)(a|b)(a|b)(a|b)(a|b)", 8); err != ErrRegexpTooManyStates {
+		t.Fatalf("expected ErrRegexpTooManyStates, got %v", err)
+	}
+}
+
+func TestRegexpSearchOverFST(t *testing.T) {
+	data := map[string]uint64{
+		"arriv": 1, "attack": 2, "betray": 3, "kill": 4, "killer": 5, "skill": 6,
+	}
+	fstBytes, err := BuildSortedFST(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ir, err := OpenIndex(fstBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.Close()
+
+	re, err := NewRegexp(".?kill.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, vals, err := ir.Search(re)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"kill", "killer", "skill"}
+	if len(keys) != len(want) {
+		t.Fatalf("Search = %v, want %v", keys, want)
+	}
+	for i, k := range want {
+		if keys[i] != k || vals[i] != data[k] {
+			t.Errorf("result %d = %s/%d, want %s/%d", i, keys[i], vals[i], k, data[k])
+		}
+	}
+}
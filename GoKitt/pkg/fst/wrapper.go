@@ -100,6 +100,66 @@ func (ir *IndexReader) SearchPrefix(prefix []byte) ([]string, []uint64, error) {
 	return keys, vals, nil
 }
 
+// Search returns all keys accepted by the automaton, in order
+func (ir *IndexReader) Search(aut Automaton) ([]string, []uint64, error) {
+	iterator, err := ir.fst.Search(aut, nil, nil)
+
+	var keys []string
+	var vals []uint64
+
+	for err == nil {
+		key, val := iterator.Current()
+		keys = append(keys, string(key))
+		vals = append(vals, val)
+		err = iterator.Next()
+	}
+
+	if err != ErrIteratorDone {
+		return nil, nil, err
+	}
+
+	return keys, vals, nil
+}
+
+// FuzzyMatch is a key found by SearchFuzzy
+type FuzzyMatch struct {
+	Key      string
+	Val      uint64
+	Distance uint8
+}
+
+// SearchFuzzy returns all keys within distance edits of query. Adjacent
+// transpositions count as a single edit.
+func (ir *IndexReader) SearchFuzzy(query string, distance uint8) ([]FuzzyMatch, error) {
+	lb, err := NewLevenshteinAutomatonBuilder(distance, true)
+	if err != nil {
+		return nil, err
+	}
+	dfa, err := lb.BuildDfa(query, distance)
+	if err != nil {
+		return nil, err
+	}
+
+	iterator, err := ir.fst.Search(dfa, nil, nil)
+
+	var matches []FuzzyMatch
+	for err == nil {
+		key, val := iterator.Current()
+		matches = append(matches, FuzzyMatch{
+			Key:      string(key),
+			Val:      val,
+			Distance: iterator.EditDistance(),
+		})
+		err = iterator.Next()
+	}
+
+	if err != ErrIteratorDone {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
 // KeyValueTuple helper for sorting
 type KeyValueTuple struct {
 	Key []byte
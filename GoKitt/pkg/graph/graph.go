@@ -21,6 +21,12 @@ const (
 	RelWorldContains = "WORLD_CONTAINS" // World -> Entity
 )
 
+// Event qualifier values that decide whether an edge is factual
+const (
+	PolarityNegated = "negated"
+	ModalityFactual = "factual"
+)
+
 // ConceptNode represents an entity in the graph
 type ConceptNode struct {
 	ID    string `json:"id"`
@@ -45,11 +51,32 @@ type ConceptEdge struct {
 	Time      string `json:"time,omitempty"`
 	Recipient string `json:"recipient,omitempty"`
 
+	// Event qualifiers: polarity (affirmed, negated), modality (factual, possible,
+	// intended, hypothetical, reported) and tense. Empty when nobody qualified the edge.
+	Polarity string `json:"polarity,omitempty"`
+	Modality string `json:"modality,omitempty"`
+	Tense    string `json:"tense,omitempty"`
+
+	// Member entity IDs when an endpoint is a group ("Aria and Tom" ... "they")
+	SourceMembers []string `json:"sourceMembers,omitempty"`
+	TargetMembers []string `json:"targetMembers,omitempty"`
+
 	// Pointers to nodes
 	Source *ConceptNode `json:"-"`
 	Target *ConceptNode `json:"-"`
 }
 
+// IsFactual reports whether the edge states something that happens
+// (not negated, possible, intended, hypothetical or merely reported)
+func (e *ConceptEdge) IsFactual() bool {
+	return IsFactual(e.Polarity, e.Modality)
+}
+
+// IsFactual reports whether qualifiers describe a fact. Empty qualifiers count as factual.
+func IsFactual(polarity, modality string) bool {
+	return polarity != PolarityNegated && (modality == "" || modality == ModalityFactual)
+}
+
 // ConceptGraph is a directed semantic graph
 type ConceptGraph struct {
 	// Node storage: ID -> Node
@@ -68,6 +95,12 @@ type SerializableEdge struct {
 	Location  string  `json:"location,omitempty"`
 	Time      string  `json:"time,omitempty"`
 	Recipient string  `json:"recipient,omitempty"`
+	Polarity  string  `json:"polarity,omitempty"`
+	Modality  string  `json:"modality,omitempty"`
+	Tense     string  `json:"tense,omitempty"`
+
+	SourceMembers []string `json:"sourceMembers,omitempty"`
+	TargetMembers []string `json:"targetMembers,omitempty"`
 }
 
 // NewGraph creates an empty graph
@@ -92,6 +125,12 @@ func (g *ConceptGraph) ToSerializable() {
 				Location:  edge.Location,
 				Time:      edge.Time,
 				Recipient: edge.Recipient,
+				Polarity:  edge.Polarity,
+				Modality:  edge.Modality,
+				Tense:     edge.Tense,
+
+				SourceMembers: edge.SourceMembers,
+				TargetMembers: edge.TargetMembers,
 			})
 		}
 	}
@@ -3,11 +3,13 @@
 package implicitmatcher
 
 import (
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/coregx/ahocorasick"
+	vellum "github.com/kittclouds/gokitt/pkg/fst"
 )
 
 // ============================================================================
@@ -265,11 +267,18 @@ type RegisteredEntity struct {
 // ============================================================================
 
 // RuntimeDictionary uses AC for both dictionary lookup AND text scanning.
+// Entities added after Compile go into a small delta automaton scanned
+// alongside the base one (see AddEntity and Compact).
 type RuntimeDictionary struct {
-	// The AC automaton built from all surface forms
-	ac *ahocorasick.Automaton
+	// The AC automaton built from patterns[:baseCount]
+	ac        *ahocorasick.Automaton
+	baseCount int
 
-	// Pattern index -> Entity IDs (multiple entities may share pattern)
+	// Automaton over patterns[baseCount:], added since the last compaction
+	delta *ahocorasick.Automaton
+
+	// Pattern index -> Entity IDs (multiple entities may share pattern), in
+	// registration order. Empty once every entity using the pattern is removed.
 	patternToIDs [][]string
 
 	// Normalized pattern -> pattern index
@@ -278,8 +287,22 @@ type RuntimeDictionary struct {
 	// Entity ID -> EntityInfo
 	idToInfo map[string]*EntityInfo
 
+	// Entity ID -> its aliases as given, its canonical surface forms and its
+	// registration order (which breaks SelectBest and Disambiguate ties, as in Compile)
+	aliases  map[string][]string
+	surfaces map[string][]string
+	order    map[string]int
+	nextSeq  int
+
 	// All patterns in order (for AC builder)
 	patterns []string
+
+	// Most words in any pattern (bounds the fuzzy pass's windows)
+	maxWords int
+
+	// FST over the live patterns the fuzzy pass may misspell, built on the
+	// first fuzzy scan after a change (see typoIndex)
+	typos *vellum.IndexReader
 }
 
 // NewRuntimeDictionary creates an empty dictionary
@@ -288,6 +311,9 @@ func NewRuntimeDictionary() *RuntimeDictionary {
 		patternToIDs: [][]string{},
 		patternIndex: make(map[string]int),
 		idToInfo:     make(map[string]*EntityInfo),
+		aliases:      make(map[string][]string),
+		surfaces:     make(map[string][]string),
+		order:        make(map[string]int),
 		patterns:     []string{},
 		ac:           nil,
 	}
@@ -297,77 +323,80 @@ func NewRuntimeDictionary() *RuntimeDictionary {
 // Uses CanonicalizeForMatch for pattern normalization.
 func Compile(entities []RegisteredEntity) (*RuntimeDictionary, error) {
 	dict := NewRuntimeDictionary()
-
 	for _, e := range entities {
-		// Parse Kind dynamically
-		var k EntityKind
-		switch v := e.Kind.(type) {
-		case EntityKind:
-			k = v
-		case int:
-			k = EntityKind(v)
-		case string:
-			k = ParseKind(v)
-		case float64:
-			k = EntityKind(int(v))
-		case map[string]interface{}:
-			if t, ok := v["type"].(string); ok {
-				k = ParseKind(t)
-			} else {
-				k = KindOther
-			}
-		default:
-			k = KindOther
-		}
+		dict.register(e)
+	}
+	if err := dict.Compact(); err != nil {
+		return nil, err
+	}
+	return dict, nil
+}
 
-		// Store entity info
-		dict.idToInfo[e.ID] = &EntityInfo{
-			ID:          e.ID,
-			Label:       e.Label,
-			Kind:        k,
-			NarrativeID: e.NarrativeID,
-		}
+// register stores an entity and attaches it to the patterns of its label,
+// aliases and auto-aliases, creating patterns that don't exist yet
+func (d *RuntimeDictionary) register(e RegisteredEntity) {
+	k := parseEntityKind(e.Kind)
+
+	// Store entity info
+	d.idToInfo[e.ID] = &EntityInfo{
+		ID:          e.ID,
+		Label:       e.Label,
+		Kind:        k,
+		NarrativeID: e.NarrativeID,
+	}
+	d.aliases[e.ID] = append(d.aliases[e.ID], e.Aliases...)
+	if _, ok := d.order[e.ID]; !ok {
+		d.order[e.ID] = d.nextSeq
+		d.nextSeq++
+	}
 
-		// Collect all surface forms
-		surfaces := []string{e.Label}
-		surfaces = append(surfaces, e.Aliases...)
-		surfaces = append(surfaces, generateAutoAliases(e.Label, k)...)
+	// Collect all surface forms
+	surfaces := []string{e.Label}
+	surfaces = append(surfaces, e.Aliases...)
+	surfaces = append(surfaces, generateAutoAliases(e.Label, k)...)
 
-		for _, surface := range surfaces {
-			// USE THE SHARED CANONICALIZER - critical for matching consistency
-			key := CanonicalizeForMatch(surface)
-			if key == "" {
-				continue
-			}
+	keys := d.surfaces[e.ID] // A repeated ID adds to its surface forms
+	for _, surface := range surfaces {
+		// USE THE SHARED CANONICALIZER - critical for matching consistency
+		key := CanonicalizeForMatch(surface)
+		if key == "" {
+			continue
+		}
+		keys = appendUnique(keys, key)
 
-			// Check if pattern already exists
-			if idx, exists := dict.patternIndex[key]; exists {
-				// Add entity ID to existing pattern
-				dict.patternToIDs[idx] = appendUnique(dict.patternToIDs[idx], e.ID)
-			} else {
-				// New pattern
-				idx := len(dict.patterns)
-				dict.patterns = append(dict.patterns, key)
-				dict.patternIndex[key] = idx
-				dict.patternToIDs = append(dict.patternToIDs, []string{e.ID})
-			}
+		// Check if pattern already exists
+		if idx, exists := d.patternIndex[key]; exists {
+			// Add entity ID to existing pattern
+			d.patternToIDs[idx] = d.insertByOrder(d.patternToIDs[idx], e.ID)
+		} else {
+			// New pattern
+			idx := len(d.patterns)
+			d.patterns = append(d.patterns, key)
+			d.patternIndex[key] = idx
+			d.patternToIDs = append(d.patternToIDs, []string{e.ID})
+			d.maxWords = max(d.maxWords, len(strings.Fields(key)))
 		}
 	}
+	d.surfaces[e.ID] = keys
+}
 
-	// Build AC automaton
-	// Use LeftmostLongest for standard entity extraction behavior (prefer "San Francisco" over "San")
-	automaton, err := ahocorasick.NewBuilder().
-		AddStrings(dict.patterns).
-		SetMatchKind(ahocorasick.LeftmostLongest).
-		SetPrefilter(true).
-		Build()
-
-	if err != nil {
-		return nil, err
+// parseEntityKind reads a RegisteredEntity's Kind, whatever JSON gave us
+func parseEntityKind(kind interface{}) EntityKind {
+	switch v := kind.(type) {
+	case EntityKind:
+		return v
+	case int:
+		return EntityKind(v)
+	case string:
+		return ParseKind(v)
+	case float64:
+		return EntityKind(int(v))
+	case map[string]interface{}:
+		if t, ok := v["type"].(string); ok {
+			return ParseKind(t)
+		}
 	}
-	dict.ac = automaton
-
-	return dict, nil
+	return KindOther
 }
 
 // ============================================================================
@@ -382,7 +411,7 @@ func (d *RuntimeDictionary) Lookup(surface string) []*EntityInfo {
 
 	key := CanonicalizeForMatch(surface)
 	idx, exists := d.patternIndex[key]
-	if !exists {
+	if !exists || d.dead(idx) {
 		return nil
 	}
 
@@ -399,8 +428,8 @@ func (d *RuntimeDictionary) Lookup(surface string) []*EntityInfo {
 // IsKnownEntity checks if a token matches any known entity
 func (d *RuntimeDictionary) IsKnownEntity(token string) bool {
 	key := CanonicalizeForMatch(token)
-	_, exists := d.patternIndex[key]
-	return exists
+	idx, exists := d.patternIndex[key]
+	return exists && len(d.patternToIDs[idx]) > 0
 }
 
 // GetInfo retrieves entity info by ID
@@ -418,11 +447,16 @@ type Match struct {
 	End         int    // Byte offset end in ORIGINAL text
 	MatchedText string // Original text slice (preserves casing)
 	PatternIdx  int    // Index into patterns slice
+
+	// ExactConfidence for Aho-Corasick matches; lower for ScanFuzzy's "possible mentions"
+	Confidence float64
 }
 
 // Scan finds all entity mentions in text (O(n) via AC).
 // Uses CanonicalizeForMatch on input - THE SAME canonicalizer used for patterns.
 // Returns offsets mapped back to the original text for accurate highlighting.
+// Base and delta matches are merged in the automaton's order (by end, longest
+// first), so the result is the same as after a full Compile.
 func (d *RuntimeDictionary) Scan(text string) []Match {
 	if d.ac == nil {
 		return nil
@@ -439,9 +473,26 @@ func (d *RuntimeDictionary) Scan(text string) []Match {
 	// Use FindAllOverlapping to find ALL entity mentions
 	// For entity extraction we want every match; overlap handling is done at higher level
 	matches := d.ac.FindAllOverlapping(haystack)
+	if d.delta != nil {
+		for _, m := range d.delta.FindAllOverlapping(haystack) {
+			m.PatternID += d.baseCount
+			matches = append(matches, m)
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			if matches[i].End != matches[j].End {
+				return matches[i].End < matches[j].End
+			}
+			return matches[i].Start > matches[j].Start
+		})
+	}
 	result := make([]Match, 0, len(matches))
 
 	for _, m := range matches {
+		// Patterns of removed entities stay in the automaton until compaction
+		if d.dead(m.PatternID) {
+			continue
+		}
+
 		// Map canonicalized offsets back to original text
 		origStart := mapOffset(m.Start, canonToOrig, len(text))
 		origEnd := mapOffset(m.End, canonToOrig, len(text))
@@ -456,6 +507,7 @@ func (d *RuntimeDictionary) Scan(text string) []Match {
 			End:         origEnd,
 			MatchedText: text[origStart:origEnd],
 			PatternIdx:  m.PatternID,
+			Confidence:  ExactConfidence,
 		})
 	}
 
@@ -520,16 +572,26 @@ func mapOffset(canonOffset int, mapping []int, originalLen int) int {
 	return mapping[canonOffset]
 }
 
-// ScanWithInfo returns matches with resolved entity info
-func (d *RuntimeDictionary) ScanWithInfo(text string) []struct {
+// ScanHit is a match with its resolved entity info
+type ScanHit struct {
 	Match
 	Entities []*EntityInfo
-} {
-	matches := d.Scan(text)
-	result := make([]struct {
-		Match
-		Entities []*EntityInfo
-	}, 0, len(matches))
+}
+
+// ScanWithInfo returns matches with resolved entity info
+func (d *RuntimeDictionary) ScanWithInfo(text string) []ScanHit {
+	return d.withInfo(d.Scan(text))
+}
+
+// ScanWithInfoFuzzy is ScanWithInfo followed by the fuzzy pass (see ScanFuzzy).
+// Exact matches come first, then the possible mentions in text order.
+func (d *RuntimeDictionary) ScanWithInfoFuzzy(text string) []ScanHit {
+	exact := d.Scan(text)
+	return d.withInfo(append(exact, d.ScanFuzzy(text, exact)...))
+}
+
+func (d *RuntimeDictionary) withInfo(matches []Match) []ScanHit {
+	result := make([]ScanHit, 0, len(matches))
 
 	for _, m := range matches {
 		ids := d.patternToIDs[m.PatternIdx]
@@ -540,10 +602,7 @@ func (d *RuntimeDictionary) ScanWithInfo(text string) []struct {
 			}
 		}
 
-		result = append(result, struct {
-			Match
-			Entities []*EntityInfo
-		}{m, entities})
+		result = append(result, ScanHit{m, entities})
 	}
 
 	return result
@@ -0,0 +1,138 @@
+package implicitmatcher
+
+import "sort"
+
+// ============================================================================
+// Disambiguation - Narrative scope + evidence for same-surface entities
+// ============================================================================
+
+// Evidence weights; recency adds up to recencyWeight, less for older mentions
+const (
+	scopeWeight     = 4.0 // Belongs to the active narrative rather than being global
+	mentionedWeight = 3.0 // Named unambiguously elsewhere in the paragraph ("Ash Ketchum ... Ash")
+	worldWeight     = 2.0 // Shares a narrative with an entity named in the paragraph
+	recencyWeight   = 2.0 // Mentioned recently, per the resolver
+)
+
+// Scope restricts matching to one narrative (world). Entities without a
+// NarrativeID are global and always in scope; the zero Scope admits everything.
+type Scope struct {
+	NarrativeID string
+}
+
+// Admits reports whether an entity may be matched in this scope
+func (s Scope) Admits(info *EntityInfo) bool {
+	return s.NarrativeID == "" || info.NarrativeID == "" || info.NarrativeID == s.NarrativeID
+}
+
+// Filter drops the out-of-scope entities from each hit, and the hits left with none
+func (s Scope) Filter(hits []ScanHit) []ScanHit {
+	out := hits[:0:0]
+	for _, hit := range hits {
+		var entities []*EntityInfo
+		for _, info := range hit.Entities {
+			if s.Admits(info) {
+				entities = append(entities, info)
+			}
+		}
+		if len(entities) > 0 {
+			hit.Entities = entities
+			out = append(out, hit)
+		}
+	}
+	return out
+}
+
+// ScanScoped is ScanWithInfo restricted to the entities in scope
+func (d *RuntimeDictionary) ScanScoped(text string, scope Scope) []ScanHit {
+	return scope.Filter(d.ScanWithInfo(text))
+}
+
+// Evidence is what Disambiguate weighs besides the candidates themselves
+type Evidence struct {
+	Scope Scope
+
+	// IDs of entities matched unambiguously in the same paragraph (see CoOccurring)
+	CoOccurring []string
+
+	// Recency ranks a candidate by how recently it was mentioned: 0 for the
+	// latest mention, -1 if never. Nil when no mention history is available.
+	Recency func(info *EntityInfo) int
+}
+
+// Resolution is the outcome of Disambiguate
+type Resolution struct {
+	Best       *EntityInfo   // nil if no candidate is in scope
+	Candidates []*EntityInfo // In-scope candidates, best first
+	Ambiguous  bool          // The evidence could not separate Best from the runner-up
+}
+
+// Disambiguate picks among the entities sharing a surface form. Candidates
+// are scored by scope, co-occurring entities and recency; kind priority and
+// then registration order only break ties, and such a tie is reported as
+// Ambiguous rather than settled silently.
+func (d *RuntimeDictionary) Disambiguate(candidates []*EntityInfo, ev Evidence) Resolution {
+	var in []*EntityInfo
+	for _, info := range candidates {
+		if info != nil && ev.Scope.Admits(info) {
+			in = append(in, info)
+		}
+	}
+	if len(in) == 0 {
+		return Resolution{}
+	}
+
+	mentioned := make(map[string]bool, len(ev.CoOccurring))
+	worlds := make(map[string]bool)
+	for _, id := range ev.CoOccurring {
+		mentioned[id] = true
+		if info := d.idToInfo[id]; info != nil && info.NarrativeID != "" {
+			worlds[info.NarrativeID] = true
+		}
+	}
+
+	scores := make(map[*EntityInfo]float64, len(in))
+	for _, info := range in {
+		score := 0.0
+		if ev.Scope.NarrativeID != "" && info.NarrativeID == ev.Scope.NarrativeID {
+			score += scopeWeight
+		}
+		if mentioned[info.ID] {
+			score += mentionedWeight
+		}
+		if info.NarrativeID != "" && worlds[info.NarrativeID] {
+			score += worldWeight
+		}
+		if ev.Recency != nil {
+			if rank := ev.Recency(info); rank >= 0 {
+				score += recencyWeight / float64(1+rank)
+			}
+		}
+		scores[info] = score
+	}
+
+	sort.SliceStable(in, func(i, j int) bool {
+		if scores[in[i]] != scores[in[j]] {
+			return scores[in[i]] > scores[in[j]]
+		}
+		return in[i].Kind.Priority() > in[j].Kind.Priority()
+	})
+	return Resolution{
+		Best:       in[0],
+		Candidates: in,
+		Ambiguous:  len(in) > 1 && scores[in[0]] == scores[in[1]],
+	}
+}
+
+// CoOccurring returns the IDs of the entities matched with a single
+// candidate between from and to, the paragraph evidence for Disambiguate.
+// Hits should already be filtered to the scope.
+func CoOccurring(hits []ScanHit, from, to int) []string {
+	var ids []string
+	for _, hit := range hits {
+		if hit.Start >= from && hit.End <= to && len(hit.Entities) == 1 {
+			ids = appendUnique(ids, hit.Entities[0].ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,82 @@
+package implicitmatcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func twoWorlds(t *testing.T) *RuntimeDictionary {
+	t.Helper()
+	dict, err := Compile([]RegisteredEntity{
+		{ID: "ash-kanto", Label: "Ash Ketchum", Aliases: []string{"Ash"}, Kind: KindCharacter, NarrativeID: "kanto"},
+		{ID: "misty", Label: "Misty", Kind: KindCharacter, NarrativeID: "kanto"},
+		{ID: "ash-ember", Label: "Ash", Kind: KindCharacter, NarrativeID: "ember"},
+		{ID: "cinder", Label: "Cinder", Kind: KindCharacter, NarrativeID: "ember"},
+		{ID: "ash-wood", Label: "Ash", Kind: KindItem},
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	return dict
+}
+
+// firstAsh disambiguates the first bare "Ash" in text, with the whole text as the paragraph
+func firstAsh(dict *RuntimeDictionary, text string, ev Evidence) Resolution {
+	hits := ev.Scope.Filter(dict.ScanWithInfo(text))
+	ev.CoOccurring = CoOccurring(hits, 0, len(text))
+	for _, hit := range hits {
+		if hit.MatchedText == "Ash" {
+			return dict.Disambiguate(hit.Entities, ev)
+		}
+	}
+	return Resolution{}
+}
+
+func TestScanScoped(t *testing.T) {
+	dict := twoWorlds(t)
+
+	for _, hit := range dict.ScanScoped("Ash met Cinder.", Scope{NarrativeID: "kanto"}) {
+		for _, e := range hit.Entities {
+			if e.NarrativeID == "ember" {
+				t.Errorf("%q matched %s outside the kanto scope", hit.MatchedText, e.ID)
+			}
+		}
+		if hit.MatchedText == "Ash" && !reflect.DeepEqual(getIDs(hit.Entities), []string{"ash-kanto", "ash-wood"}) {
+			t.Errorf("Ash in kanto = %v, want kanto's Ash and the global one", getIDs(hit.Entities))
+		}
+	}
+	if hits := dict.ScanScoped("Ash met Cinder.", Scope{}); len(hits) == 0 || len(hits[0].Entities) != 3 {
+		t.Errorf("unscoped scan should keep every Ash: %+v", hits)
+	}
+}
+
+func TestDisambiguate(t *testing.T) {
+	dict := twoWorlds(t)
+
+	tests := []struct {
+		name      string
+		text      string
+		ev        Evidence
+		want      string
+		ambiguous bool
+	}{
+		{"scope beats a global entity", "Ash waited.", Evidence{Scope: Scope{NarrativeID: "ember"}}, "ash-ember", false},
+		{"co-occurring world", "Ash met Cinder.", Evidence{}, "ash-ember", false},
+		{"named in full nearby", "Ash Ketchum slept. Later Ash woke.", Evidence{}, "ash-kanto", false},
+		{"recent mention", "Ash waited.", Evidence{Recency: func(e *EntityInfo) int {
+			if e.ID == "ash-kanto" {
+				return 2
+			}
+			return -1
+		}}, "ash-kanto", false},
+		{"no evidence is reported", "Ash waited.", Evidence{}, "ash-kanto", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := firstAsh(dict, tt.text, tt.ev)
+			if r.Best == nil || r.Best.ID != tt.want || r.Ambiguous != tt.ambiguous {
+				t.Fatalf("got %+v (ambiguous %v), want %s (ambiguous %v)", r.Best, r.Ambiguous, tt.want, tt.ambiguous)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package implicitmatcher
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	vellum "github.com/kittclouds/gokitt/pkg/fst"
+)
+
+// ============================================================================
+// Fuzzy Matching - Possible mentions the exact automaton misses
+// ============================================================================
+
+// Match confidence by how the surface form was found
+const (
+	ExactConfidence     = 1.0
+	InflectedConfidence = 0.8 // Possessive or plural/demonym form: "Arias's", "Elves", "Elvish"
+	TypoConfidence      = 0.6 // One edit from a pattern: "Aira"; each further edit costs 0.2
+)
+
+// inflections rewrite a word's ending to the forms a name may take in prose,
+// longest suffix first: "elves" -> "elf", "elvish" -> "elf", "dwarves" -> "dwarf"
+var inflections = []struct{ suffix, base string }{
+	{"vish", "f"}, {"ves", "f"}, {"ves", "fe"}, {"ies", "y"},
+	{"ish", ""}, {"ian", ""}, {"ese", ""}, {"es", ""}, {"s", ""},
+}
+
+// ScanFuzzy is the optional second pass over text: capitalized words not
+// covered by the exact matches are checked against the dictionary after
+// dropping a possessive and undoing plural or demonym endings, then with a
+// bounded edit distance (one edit for words of 4-7 letters, two from 8).
+// Capitalized words that open a sentence are only normalized, never
+// edited, so "When" does not become "Wren". Runs of capitalized words are
+// tried longest first. Matches carry InflectedConfidence or less.
+func (d *RuntimeDictionary) ScanFuzzy(text string, exact []Match) []Match {
+	if d.ac == nil {
+		return nil
+	}
+
+	toks := fuzzyTokens(text)
+	var out []Match
+	for i := 0; i < len(toks); {
+		// Consecutive capitalized words that no exact match covers
+		n := 0
+		for i+n < len(toks) && n < d.maxWords && candidateToken(text, toks[i+n], exact) {
+			n++
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+
+		matched := false
+		for w := n; w >= 1 && !matched; w-- {
+			words := make([]string, w)
+			for j := range words {
+				words[j] = toks[i+j].Text
+			}
+			idx, confidence, ok := d.fuzzyLookup(words, !sentenceStart(text, toks[i].Start))
+			if !ok {
+				continue
+			}
+			start, end := toks[i].Start, toks[i+w-1].End
+			out = append(out, Match{
+				Start:       start,
+				End:         end,
+				MatchedText: text[start:end],
+				PatternIdx:  idx,
+				Confidence:  confidence,
+			})
+			i += w
+			matched = true
+		}
+		if !matched {
+			i++
+		}
+	}
+	return out
+}
+
+// fuzzyLookup finds the pattern closest to a run of canonical words: first
+// by normalizing the last word's ending, then (if allowed) by edit distance
+func (d *RuntimeDictionary) fuzzyLookup(words []string, typos bool) (int, float64, bool) {
+	last := len(words) - 1
+	base := trimPossessiveSuffix(words[last])
+	prefix := strings.Join(words[:last], " ")
+	if prefix != "" {
+		prefix += " "
+	}
+
+	// 1. Possessive, plural and demonym forms
+	for _, form := range append([]string{base}, inflectedBases(base)...) {
+		if idx, ok := d.patternIndex[prefix+form]; ok && !d.dead(idx) {
+			return idx, InflectedConfidence, true
+		}
+	}
+	if !typos {
+		return 0, 0, false
+	}
+
+	// 2. Misspellings
+	surface := prefix + base
+	limit := maxEdits(utf8.RuneCountInString(surface))
+	if limit == 0 {
+		return 0, 0, false
+	}
+	index, err := d.typoIndex()
+	if err != nil {
+		return 0, 0, false
+	}
+	found, err := index.SearchFuzzy(surface, uint8(limit))
+	if err != nil {
+		return 0, 0, false
+	}
+	best, bestDist := -1, limit+1
+	for _, m := range found {
+		idx, dist := int(m.Val), int(m.Distance)
+		if dist < bestDist || (dist == bestDist && idx < best) {
+			best, bestDist = idx, dist
+		}
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+	return best, TypoConfidence - 0.2*float64(bestDist-1), true
+}
+
+// typoIndex returns the FST of live patterns long enough to misspell
+// (4+ runes), mapping each to its pattern index. A Levenshtein automaton
+// walks it, so typo lookups don't visit every pattern.
+func (d *RuntimeDictionary) typoIndex() (*vellum.IndexReader, error) {
+	if d.typos != nil {
+		return d.typos, nil
+	}
+	keys := make(map[string]uint64, len(d.patterns))
+	for idx, pattern := range d.patterns {
+		if !d.dead(idx) && utf8.RuneCountInString(pattern) >= 4 {
+			keys[pattern] = uint64(idx)
+		}
+	}
+	data, err := vellum.BuildSortedFST(keys)
+	if err != nil {
+		return nil, err
+	}
+	index, err := vellum.OpenIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	d.typos = index
+	return index, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// fuzzyTokens tokenizes text without the trailing joiners ("Aira." -> "aira")
+func fuzzyTokens(text string) []Tok {
+	toks := TokenizeWithOffsets(text)
+	out := toks[:0]
+	for _, t := range toks {
+		trimmed := strings.TrimRight(text[t.Start:t.End], ".-_/#&\u00B7\u2013\u2014")
+		if trimmed == "" {
+			continue
+		}
+		t.End = t.Start + len(trimmed)
+		t.Text = CanonicalizeForMatch(trimmed)
+		out = append(out, t)
+	}
+	return out
+}
+
+// candidateToken reports a capitalized non-stopword that no whole-word exact
+// match covers. Exact matches are substrings ("Aria" in "Arias's"), so the
+// ones inside a longer word don't count.
+func candidateToken(text string, tok Tok, exact []Match) bool {
+	r, _ := utf8.DecodeRuneInString(text[tok.Start:])
+	if !unicode.IsUpper(r) || StopWords[tok.Text] {
+		return false
+	}
+	// "Aria’s" is covered by an exact "Aria"
+	end := tok.End
+	for _, s := range []string{"'s", "\u2019s", "'", "\u2019"} {
+		if strings.HasSuffix(text[tok.Start:end], s) {
+			end -= len(s)
+			break
+		}
+	}
+	for _, m := range exact {
+		if m.Start <= tok.Start && end <= m.End && wholeWords(text, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// wholeWords reports a match that neither starts nor ends inside a word
+func wholeWords(text string, m Match) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:m.Start])
+	after, _ := utf8.DecodeRuneInString(text[m.End:])
+	return !isWordRune(before) && !isWordRune(after)
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// sentenceStart reports whether only spaces, quotes or openers separate the
+// offset from the previous sentence end (or the start of the text)
+func sentenceStart(text string, offset int) bool {
+	for i := offset; i > 0; {
+		r, w := utf8.DecodeLastRuneInString(text[:i])
+		switch {
+		case r == '.' || r == '!' || r == '?' || r == '\n':
+			return true
+		case unicode.IsSpace(r) || strings.ContainsRune("\"'“‘([*#>-", r):
+			i -= w
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func trimPossessiveSuffix(word string) string {
+	word = strings.TrimSuffix(word, "'")
+	if len(word) > 2 && strings.HasSuffix(word, "'s") {
+		return word[:len(word)-2]
+	}
+	return word
+}
+
+// inflectedBases returns the bases a word may be a plural or demonym of
+func inflectedBases(word string) []string {
+	var out []string
+	for _, in := range inflections {
+		if len(word) > len(in.suffix)+1 && strings.HasSuffix(word, in.suffix) {
+			out = append(out, word[:len(word)-len(in.suffix)]+in.base)
+		}
+	}
+	return out
+}
+
+// maxEdits is the edit budget for a word of n letters
+func maxEdits(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,101 @@
+package implicitmatcher
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScanFuzzy(t *testing.T) {
+	dict, err := Compile([]RegisteredEntity{
+		{ID: "aria", Label: "Aria", Kind: KindCharacter},
+		{ID: "elf", Label: "Elf", Kind: KindConcept},
+		{ID: "wren", Label: "Wren", Kind: KindCharacter},
+		{ID: "hand", Label: "Silver Hand", Kind: KindFaction},
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	text := "Arias's blade was sharp. Aria’s was blunt. The Elves sang Elvish songs with Aira. " +
+		"When they met the Silver Hnad, Wren waited."
+	exact := dict.Scan(text)
+	fuzzy := dict.ScanFuzzy(text, exact)
+
+	want := map[string]struct {
+		id         string
+		confidence float64
+	}{
+		"Arias's":     {"aria", InflectedConfidence},
+		"Elves":       {"elf", InflectedConfidence},
+		"Elvish":      {"elf", InflectedConfidence},
+		"Aira":        {"aria", TypoConfidence},
+		"Silver Hnad": {"hand", TypoConfidence},
+	}
+	got := make(map[string]Match)
+	for _, m := range fuzzy {
+		got[m.MatchedText] = m
+	}
+	for surface, w := range want {
+		m, ok := got[surface]
+		if !ok {
+			t.Errorf("%q: no fuzzy match in %+v", surface, fuzzy)
+			continue
+		}
+		if ids := dict.patternToIDs[m.PatternIdx]; len(ids) != 1 || ids[0] != w.id {
+			t.Errorf("%q matched %v, want %s", surface, ids, w.id)
+		}
+		if m.Confidence != w.confidence {
+			t.Errorf("%q confidence = %v, want %v", surface, m.Confidence, w.confidence)
+		}
+	}
+	if len(fuzzy) != len(want) {
+		t.Errorf("got %d fuzzy matches, want %d: %+v", len(fuzzy), len(want), fuzzy)
+	}
+
+	// Exact matches keep full confidence, and the combined scan reports both
+	hits := dict.ScanWithInfoFuzzy(text)
+	for _, h := range hits {
+		if h.MatchedText == "Wren" && h.Confidence != ExactConfidence {
+			t.Errorf("exact match confidence = %v", h.Confidence)
+		}
+	}
+	if len(hits) != len(exact)+len(fuzzy) {
+		t.Errorf("ScanWithInfoFuzzy got %d hits, want %d", len(hits), len(exact)+len(fuzzy))
+	}
+}
+
+func TestScanFuzzyFollowsUpdates(t *testing.T) {
+	entities := make([]RegisteredEntity, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		entities = append(entities, RegisteredEntity{ID: fmt.Sprintf("e%d", i), Label: fmt.Sprintf("Zq%04d", i), Kind: KindConcept})
+	}
+	dict, err := Compile(entities)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	typo := func(text string) []string {
+		var ids []string
+		for _, m := range dict.ScanFuzzy(text, dict.Scan(text)) {
+			ids = append(ids, dict.patternToIDs[m.PatternIdx]...)
+		}
+		return ids
+	}
+	if ids := typo("They met Galdriel."); len(ids) != 0 {
+		t.Fatalf("unexpected match %v", ids)
+	}
+
+	// The typo index is rebuilt after incremental changes
+	if err := dict.AddEntity(RegisteredEntity{ID: "galadriel", Label: "Galadriel", Kind: KindCharacter}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := typo("They met Galdriel."); len(ids) != 1 || ids[0] != "galadriel" {
+		t.Errorf("after AddEntity got %v, want galadriel", ids)
+	}
+	if err := dict.RemoveEntity("galadriel"); err != nil {
+		t.Fatal(err)
+	}
+	if ids := typo("They met Galdriel."); len(ids) != 0 {
+		t.Errorf("after RemoveEntity got %v", ids)
+	}
+}
@@ -0,0 +1,199 @@
+package implicitmatcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coregx/ahocorasick"
+)
+
+// ============================================================================
+// Incremental Updates - Delta automaton + compaction
+// ============================================================================
+
+// minCompactPatterns is the delta size below which compaction never triggers;
+// above it, the delta may grow to a quarter of the base before a rebuild.
+const minCompactPatterns = 64
+
+// AddEntity adds an entity, or replaces the one with the same ID, without
+// recompiling the base automaton. New surface forms go into the delta
+// automaton; the dictionary compacts itself once the delta grows too large.
+// A replaced entity keeps its place in SelectBest ties.
+func (d *RuntimeDictionary) AddEntity(e RegisteredEntity) error {
+	if _, exists := d.idToInfo[e.ID]; exists {
+		d.detach(e.ID)
+	}
+	d.register(e)
+	return d.refresh()
+}
+
+// RemoveEntity removes an entity. Its patterns stop matching at once and
+// leave the automata at the next compaction. Unknown IDs are ignored.
+func (d *RuntimeDictionary) RemoveEntity(id string) error {
+	if _, exists := d.idToInfo[id]; !exists {
+		return nil
+	}
+	d.detach(id)
+	delete(d.idToInfo, id)
+	delete(d.order, id)
+	return d.refresh()
+}
+
+// UpdateAliases replaces an entity's aliases, keeping its label and kind
+func (d *RuntimeDictionary) UpdateAliases(id string, aliases []string) error {
+	info, exists := d.idToInfo[id]
+	if !exists {
+		return fmt.Errorf("unknown entity %q", id)
+	}
+	return d.AddEntity(RegisteredEntity{
+		ID:          id,
+		Label:       info.Label,
+		Aliases:     aliases,
+		Kind:        info.Kind,
+		NarrativeID: info.NarrativeID,
+	})
+}
+
+// Entities returns the registered entities with the aliases they were given,
+// in registration order: what Compile needs to rebuild this dictionary
+func (d *RuntimeDictionary) Entities() []RegisteredEntity {
+	out := make([]RegisteredEntity, len(d.order))
+	i := 0
+	for id := range d.order {
+		info := d.idToInfo[id]
+		out[i] = RegisteredEntity{
+			ID:          id,
+			Label:       info.Label,
+			Aliases:     append([]string(nil), d.aliases[id]...),
+			Kind:        info.Kind,
+			NarrativeID: info.NarrativeID,
+		}
+		i++
+	}
+	sort.Slice(out, func(i, j int) bool { return d.order[out[i].ID] < d.order[out[j].ID] })
+	return out
+}
+
+// Compact rebuilds the base automaton from the live patterns, dropping those
+// of removed entities and folding in the delta. Pattern indices change.
+func (d *RuntimeDictionary) Compact() error {
+	patterns := make([]string, 0, len(d.patterns))
+	patternToIDs := make([][]string, 0, len(d.patternToIDs))
+	patternIndex := make(map[string]int, len(d.patternIndex))
+	maxWords := 0
+	for idx, key := range d.patterns {
+		if d.dead(idx) {
+			continue
+		}
+		patternIndex[key] = len(patterns)
+		patterns = append(patterns, key)
+		patternToIDs = append(patternToIDs, d.patternToIDs[idx])
+		maxWords = max(maxWords, len(strings.Fields(key)))
+	}
+
+	automaton, err := buildAutomaton(patterns)
+	if err != nil {
+		return err
+	}
+	d.ac = automaton
+	d.delta = nil
+	d.typos = nil
+	d.baseCount = len(patterns)
+	d.patterns, d.patternToIDs, d.patternIndex, d.maxWords = patterns, patternToIDs, patternIndex, maxWords
+	return nil
+}
+
+// DeltaSize returns how many patterns wait in the delta automaton
+func (d *RuntimeDictionary) DeltaSize() int {
+	return len(d.patterns) - d.baseCount
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// refresh compacts when the delta outgrows the base or too many patterns are
+// dead, and otherwise rebuilds the (small) delta automaton
+func (d *RuntimeDictionary) refresh() error {
+	d.typos = nil
+	if d.ac == nil || d.needsCompaction() {
+		return d.Compact()
+	}
+	if d.DeltaSize() == 0 {
+		d.delta = nil
+		return nil
+	}
+	delta, err := buildAutomaton(d.patterns[d.baseCount:])
+	if err != nil {
+		return err
+	}
+	d.delta = delta
+	return nil
+}
+
+func (d *RuntimeDictionary) needsCompaction() bool {
+	limit := max(minCompactPatterns, d.baseCount/4)
+	if d.DeltaSize() > limit {
+		return true
+	}
+	dead := 0
+	for _, ids := range d.patternToIDs {
+		if len(ids) == 0 {
+			dead++
+		}
+	}
+	return dead > limit
+}
+
+// dead reports a pattern whose entities were all removed; it stays in the
+// automata until the next compaction but must never match
+func (d *RuntimeDictionary) dead(idx int) bool {
+	return len(d.patternToIDs[idx]) == 0
+}
+
+// detach removes an entity from the patterns of its surface forms
+func (d *RuntimeDictionary) detach(id string) {
+	for _, key := range d.surfaces[id] {
+		idx := d.patternIndex[key]
+		var ids []string
+		for _, other := range d.patternToIDs[idx] {
+			if other != id {
+				ids = append(ids, other)
+			}
+		}
+		d.patternToIDs[idx] = ids
+	}
+	delete(d.surfaces, id)
+	delete(d.aliases, id)
+}
+
+// insertByOrder adds id to a pattern's entity list, keeping registration order
+func (d *RuntimeDictionary) insertByOrder(ids []string, id string) []string {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	pos := len(ids)
+	for i, existing := range ids {
+		if d.order[existing] > d.order[id] {
+			pos = i
+			break
+		}
+	}
+	ids = append(ids, "")
+	copy(ids[pos+1:], ids[pos:])
+	ids[pos] = id
+	return ids
+}
+
+// buildAutomaton uses LeftmostLongest for standard entity extraction behavior
+// (prefer "San Francisco" over "San")
+func buildAutomaton(patterns []string) (*ahocorasick.Automaton, error) {
+	return ahocorasick.NewBuilder().
+		AddStrings(patterns).
+		SetMatchKind(ahocorasick.LeftmostLongest).
+		SetPrefilter(true).
+		Build()
+}
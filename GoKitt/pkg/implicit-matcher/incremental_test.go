@@ -0,0 +1,145 @@
+package implicitmatcher
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// scanSummary reduces a scan to what callers see: spans and their entities
+func scanSummary(d *RuntimeDictionary, text string) []string {
+	var out []string
+	for _, hit := range d.ScanWithInfo(text) {
+		out = append(out, fmt.Sprintf("%d-%d %v", hit.Start, hit.End, getIDs(hit.Entities)))
+	}
+	return out
+}
+
+func getIDs(entities []*EntityInfo) []string {
+	ids := make([]string, len(entities))
+	for i, e := range entities {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestIncrementalMatchesCompile(t *testing.T) {
+	gandalf := RegisteredEntity{ID: "gandalf", Label: "Gandalf", Kind: KindCharacter, Aliases: []string{"Mithrandir"}}
+	frodo := RegisteredEntity{ID: "frodo", Label: "Frodo Baggins", Kind: KindCharacter}
+	shire := RegisteredEntity{ID: "shire", Label: "The Shire", Kind: KindPlace}
+	bilbo := RegisteredEntity{ID: "bilbo", Label: "Bilbo Baggins", Kind: KindCharacter}
+	fellowship := RegisteredEntity{ID: "fellowship", Label: "Fellowship of the Ring", Kind: KindFaction}
+
+	text := "Mithrandir met Frodo Baggins and Bilbo in the Shire. Baggins! The Grey Pilgrim and the Fellowship of the Ring."
+
+	dict, err := Compile([]RegisteredEntity{gandalf, frodo, shire})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if err := dict.AddEntity(bilbo); err != nil {
+		t.Fatal(err)
+	}
+	if err := dict.AddEntity(fellowship); err != nil {
+		t.Fatal(err)
+	}
+	if dict.DeltaSize() == 0 {
+		t.Fatal("new patterns should wait in the delta automaton")
+	}
+	if err := dict.UpdateAliases("gandalf", []string{"The Grey Pilgrim"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dict.RemoveEntity("shire"); err != nil {
+		t.Fatal(err)
+	}
+
+	gandalf.Aliases = []string{"The Grey Pilgrim"}
+	want, err := Compile([]RegisteredEntity{gandalf, frodo, bilbo, fellowship})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if got, exp := scanSummary(dict, text), scanSummary(want, text); !reflect.DeepEqual(got, exp) {
+		t.Errorf("incremental scan differs from Compile:\n got %v\nwant %v", got, exp)
+	}
+	if dict.IsKnownEntity("Mithrandir") || dict.IsKnownEntity("The Shire") {
+		t.Error("removed surface forms should be unknown")
+	}
+	if got := dict.Lookup("Baggins"); len(got) != 2 || got[0].ID != "frodo" || got[1].ID != "bilbo" {
+		t.Errorf("shared pattern should list entities in registration order, got %v", getIDs(got))
+	}
+
+	// Compaction keeps results and folds the delta into the base
+	if err := dict.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if dict.DeltaSize() != 0 {
+		t.Errorf("DeltaSize after Compact = %d", dict.DeltaSize())
+	}
+	if got, exp := scanSummary(dict, text), scanSummary(want, text); !reflect.DeepEqual(got, exp) {
+		t.Errorf("compacted scan differs from Compile:\n got %v\nwant %v", got, exp)
+	}
+	var ids []string
+	for _, e := range dict.Entities() {
+		ids = append(ids, e.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"gandalf", "frodo", "bilbo", "fellowship"}) {
+		t.Errorf("Entities = %v", ids)
+	}
+}
+
+func TestIncrementalCompactsLargeDelta(t *testing.T) {
+	dict, err := Compile([]RegisteredEntity{{ID: "seed", Label: "Seed", Kind: KindCharacter}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3*minCompactPatterns; i++ {
+		e := RegisteredEntity{ID: fmt.Sprintf("e%d", i), Label: fmt.Sprintf("Name%03d", i), Kind: KindConcept}
+		if err := dict.AddEntity(e); err != nil {
+			t.Fatal(err)
+		}
+		if dict.DeltaSize() > minCompactPatterns {
+			t.Fatalf("delta grew to %d patterns", dict.DeltaSize())
+		}
+	}
+	if got := dict.Lookup("Name150"); len(got) != 1 || got[0].ID != "e150" {
+		t.Errorf("Lookup after compaction = %v", getIDs(got))
+	}
+	if hits := dict.Scan("Name007 and Name170"); len(hits) != 2 {
+		t.Errorf("Scan after compaction got %d hits", len(hits))
+	}
+}
+
+func TestIncrementalFuzzySkipsRemovedEntities(t *testing.T) {
+	aria := RegisteredEntity{ID: "aria", Label: "Aria", Kind: KindCharacter}
+	arin := RegisteredEntity{ID: "arin", Label: "Arin", Kind: KindCharacter}
+	text := "Arias's blade. Then Aira left with Arni."
+
+	fuzzySummary := func(d *RuntimeDictionary) []string {
+		var out []string
+		for _, hit := range d.ScanWithInfoFuzzy(text) {
+			out = append(out, fmt.Sprintf("%d-%d %v %.1f", hit.Start, hit.End, getIDs(hit.Entities), hit.Confidence))
+		}
+		return out
+	}
+
+	dict, err := Compile([]RegisteredEntity{aria, arin})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if err := dict.RemoveEntity("aria"); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := Compile([]RegisteredEntity{arin})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	got, want := fuzzySummary(dict), fuzzySummary(fresh)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fuzzy scan after removal = %v, fresh compile = %v", got, want)
+	}
+	for _, hit := range dict.ScanWithInfoFuzzy(text) {
+		if len(hit.Entities) == 0 {
+			t.Errorf("%q matched a removed entity's pattern", hit.MatchedText)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/kittclouds/gokitt/internal/store"
+	"github.com/kittclouds/gokitt/pkg/batch"
 )
 
 // Extractor coordinates memory extraction from conversations.
@@ -22,21 +23,32 @@ type ExtractorConfig struct {
 	Store         store.Storer
 	OpenRouterKey string
 	Model         string // From TypeScript UI (e.g., free-tier model)
+
+	BaseURL   string          // Optional OpenRouter-compatible endpoint override
+	Transport batch.Transport // Optional, defaults to batch.DefaultTransport()
+
+	// Backend overrides the OpenRouter settings entirely (local model, scripted fake)
+	Backend batch.Backend
 }
 
 // NewExtractor creates a new memory extractor.
-// Both OpenRouterKey and Model MUST be provided from TypeScript settings UI.
+// Both OpenRouterKey and Model MUST be provided from TypeScript settings UI
+// unless a Backend is supplied.
 // No hardcoded defaults - user selects from free tier models in UI.
 func NewExtractor(config ExtractorConfig) *Extractor {
 	extractor := &Extractor{
 		store:   config.Store,
-		enabled: config.OpenRouterKey != "" && config.Model != "",
+		enabled: config.Backend != nil || (config.OpenRouterKey != "" && config.Model != ""),
 	}
 
-	if config.OpenRouterKey != "" && config.Model != "" {
+	if config.Backend != nil {
+		extractor.llm = NewClientWithBackend(config.Backend)
+	} else if config.OpenRouterKey != "" && config.Model != "" {
 		extractor.llm = NewOpenRouterClient(OpenRouterConfig{
-			APIKey: config.OpenRouterKey,
-			Model:  config.Model, // Must come from TypeScript UI
+			APIKey:    config.OpenRouterKey,
+			Model:     config.Model, // Must come from TypeScript UI
+			BaseURL:   config.BaseURL,
+			Transport: config.Transport,
 		})
 	}
 
@@ -1,33 +1,39 @@
-//go:build js && wasm
-
-// Package memory provides observational memory extraction and management.
-// Uses browser fetch API (via syscall/js) for LLM-based memory extraction.
 package memory
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"syscall/js"
+
+	"github.com/kittclouds/gokitt/pkg/batch"
 )
 
-// OpenRouterClient wraps browser-native fetch for memory extraction.
+// OpenRouterClient runs memory extraction against an LLM backend.
+// Defaults to OpenRouter; any batch.Backend (a local model, a scripted fake) works.
 type OpenRouterClient struct {
-	apiKey string
-	model  string
+	backend batch.Backend
 }
 
 // OpenRouterConfig holds configuration for the OpenRouter client.
 type OpenRouterConfig struct {
-	APIKey string
-	Model  string // e.g., "nvidia/nemotron-3-nano-30b-a3b:free"
+	APIKey    string
+	Model     string          // e.g., "nvidia/nemotron-3-nano-30b-a3b:free"
+	BaseURL   string          // Optional, defaults to batch.DefaultOpenRouterBaseURL
+	Transport batch.Transport // Optional, defaults to batch.DefaultTransport()
 }
 
 // NewOpenRouterClient creates a new OpenRouter client for memory extraction.
 func NewOpenRouterClient(config OpenRouterConfig) *OpenRouterClient {
-	return &OpenRouterClient{
-		apiKey: config.APIKey,
-		model:  config.Model,
+	transport := config.Transport
+	if transport == nil {
+		transport = batch.DefaultTransport()
 	}
+	return NewClientWithBackend(batch.NewOpenRouterBackend(config.BaseURL, config.APIKey, config.Model, transport))
+}
+
+// NewClientWithBackend creates a memory extraction client around any batch backend.
+func NewClientWithBackend(backend batch.Backend) *OpenRouterClient {
+	return &OpenRouterClient{backend: backend}
 }
 
 // ExtractionResult represents the LLM's extracted memories.
@@ -48,87 +54,24 @@ type MessageInput struct {
 	Content string `json:"content"`
 }
 
-// openRouterRequest represents the request body for OpenRouter API.
-type openRouterRequest struct {
-	Model          string          `json:"model"`
-	Messages       []openRouterMsg `json:"messages"`
-	Temperature    float64         `json:"temperature"`
-	MaxTokens      int             `json:"max_tokens"`
-	Stream         bool            `json:"stream"`
-	ResponseFormat *responseFormat `json:"response_format,omitempty"`
-}
-
-type openRouterMsg struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type responseFormat struct {
-	Type string `json:"type"`
-}
-
-// openRouterResponse represents the response from OpenRouter API.
-type openRouterResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-	Error *struct {
-		Message string `json:"message"`
-		Code    int    `json:"code"`
-	} `json:"error,omitempty"`
-}
-
 // ExtractMemories uses the LLM to extract factual observations from conversation messages.
-// Uses browser fetch API via syscall/js — no Go net/http (which has no transport in WASM).
 func (c *OpenRouterClient) ExtractMemories(messages []MessageInput) (*ExtractionResult, error) {
-	prompt := buildExtractionPrompt(messages)
-
-	// Build request body
-	req := openRouterRequest{
-		Model: c.model,
-		Messages: []openRouterMsg{
-			{Role: "system", Content: extractionSystemPrompt},
-			{Role: "user", Content: prompt},
-		},
-		Temperature:    0.3, // Lower temperature for consistent extraction
-		MaxTokens:      4096,
-		Stream:         false,
-		ResponseFormat: &responseFormat{Type: "json_object"},
-	}
+	return c.ExtractMemoriesContext(context.Background(), messages)
+}
 
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("memory: failed to marshal request: %w", err)
-	}
+// ExtractMemoriesContext is ExtractMemories with a caller-supplied context.
+func (c *OpenRouterClient) ExtractMemoriesContext(ctx context.Context, messages []MessageInput) (*ExtractionResult, error) {
+	prompt := buildExtractionPrompt(messages)
 
-	// Use browser fetch via syscall/js
-	raw, err := c.jsFetchWithAuth(
-		"https://openrouter.ai/api/v1/chat/completions",
-		string(reqBody),
-	)
+	content, err := c.backend.Complete(ctx, batch.Request{
+		SystemPrompt: extractionSystemPrompt,
+		UserPrompt:   prompt,
+		Temperature:  0.3, // Lower temperature for consistent extraction
+		MaxTokens:    4096,
+		JSONMode:     true,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("memory: OpenRouter API request failed: %w", err)
-	}
-
-	// Parse response
-	var resp openRouterResponse
-	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
-		return nil, fmt.Errorf("memory: failed to parse response: %w", err)
-	}
-
-	if resp.Error != nil {
-		return nil, fmt.Errorf("memory: OpenRouter API error %d: %s", resp.Error.Code, resp.Error.Message)
-	}
-
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("memory: empty response from OpenRouter")
-	}
-
-	content := resp.Choices[0].Message.Content
-	if content == "" {
-		return nil, fmt.Errorf("memory: empty content in response")
+		return nil, fmt.Errorf("memory: LLM request failed: %w", err)
 	}
 
 	// Parse the JSON extraction result
@@ -150,88 +93,6 @@ func (c *OpenRouterClient) ExtractMemories(messages []MessageInput) (*Extraction
 	return &result, nil
 }
 
-// jsFetchWithAuth performs a fetch request with OpenRouter auth headers.
-// Mirrors the pattern in pkg/batch/openrouter.go.
-func (c *OpenRouterClient) jsFetchWithAuth(url, body string) (string, error) {
-	fetch := js.Global().Get("fetch")
-	if fetch.IsUndefined() {
-		return "", fmt.Errorf("memory: fetch not available")
-	}
-
-	origin := js.Global().Get("window").Get("location").Get("origin").String()
-
-	// Create headers
-	headers := js.Global().Get("Object").New()
-	headers.Set("Content-Type", "application/json")
-	headers.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
-	headers.Set("HTTP-Referer", origin)
-	headers.Set("X-Title", "KittClouds")
-
-	// Create options
-	options := js.Global().Get("Object").New()
-	options.Set("method", "POST")
-	options.Set("headers", headers)
-	options.Set("body", body)
-
-	// Call fetch
-	promise := fetch.Invoke(url, options)
-
-	// Wait for response using a channel
-	resultCh := make(chan struct {
-		response string
-		err      error
-	})
-
-	then := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		response := args[0]
-
-		status := response.Get("status").Int()
-		if !response.Get("ok").Bool() {
-			textPromise := response.Call("text")
-			textThen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-				errText := args[0].String()
-				resultCh <- struct {
-					response string
-					err      error
-				}{response: "", err: fmt.Errorf("HTTP %d: %s", status, errText)}
-				return nil
-			})
-			defer textThen.Release()
-			textPromise.Call("then", textThen)
-			return nil
-		}
-
-		textPromise := response.Call("text")
-		textThen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-			text := args[0].String()
-			resultCh <- struct {
-				response string
-				err      error
-			}{response: text, err: nil}
-			return nil
-		})
-		defer textThen.Release()
-		textPromise.Call("then", textThen)
-		return nil
-	})
-	defer then.Release()
-
-	catch := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		errMsg := args[0].Get("message").String()
-		resultCh <- struct {
-			response string
-			err      error
-		}{response: "", err: fmt.Errorf("%s", errMsg)}
-		return nil
-	})
-	defer catch.Release()
-
-	promise.Call("then", then).Call("catch", catch)
-
-	result := <-resultCh
-	return result.response, result.err
-}
-
 // extractionSystemPrompt is the system prompt for memory extraction.
 const extractionSystemPrompt = `You are a memory extraction system. Your task is to extract factual observations from conversations.
 
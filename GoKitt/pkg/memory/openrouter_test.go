@@ -0,0 +1,73 @@
+//go:build !js || !wasm
+
+package memory
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/batch"
+)
+
+func TestExtractMemoriesOverHTTP(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"memories\":[{\"content\":\"Kitt likes tea\",\"memory_type\":\"preference\",\"confidence\":0.9},{\"content\":\"Odd\",\"memory_type\":\"bogus\",\"confidence\":7}]}"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{APIKey: "sk-test", Model: "test/model", BaseURL: server.URL})
+	result, err := client.ExtractMemories([]MessageInput{{Role: "user", Content: "I like tea"}})
+	if err != nil {
+		t.Fatalf("ExtractMemories failed: %v", err)
+	}
+
+	if len(result.Memories) != 2 {
+		t.Fatalf("expected 2 memories, got %d", len(result.Memories))
+	}
+	if result.Memories[0].MemoryType != "preference" || result.Memories[0].Content != "Kitt likes tea" {
+		t.Errorf("unexpected first memory: %+v", result.Memories[0])
+	}
+	// Invalid type and confidence are normalized
+	if result.Memories[1].MemoryType != "fact" || result.Memories[1].Confidence != 0.5 {
+		t.Errorf("expected normalized memory, got %+v", result.Memories[1])
+	}
+	if !strings.Contains(gotBody, `"response_format":{"type":"json_object"}`) {
+		t.Errorf("expected JSON response format in request, got %s", gotBody)
+	}
+}
+
+func TestExtractMemoriesHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewOpenRouterClient(OpenRouterConfig{APIKey: "bad", Model: "m", BaseURL: server.URL})
+	if _, err := client.ExtractMemories(nil); err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("expected HTTP 401 error, got %v", err)
+	}
+}
+
+func TestExtractMemoriesWithScriptedBackend(t *testing.T) {
+	fake := batch.NewScriptedBackend(`{"memories":[{"content":"The tower is in Vale","memory_type":"fact","confidence":0.8}]}`)
+	client := NewClientWithBackend(fake)
+
+	result, err := client.ExtractMemories([]MessageInput{{Role: "user", Content: "The tower is in Vale"}})
+	if err != nil {
+		t.Fatalf("ExtractMemories failed: %v", err)
+	}
+	if len(result.Memories) != 1 || result.Memories[0].Content != "The tower is in Vale" {
+		t.Errorf("unexpected memories %+v", result.Memories)
+	}
+
+	reqs := fake.Requests()
+	if len(reqs) != 1 || !reqs[0].JSONMode || !strings.Contains(reqs[0].UserPrompt, "[user]: The tower is in Vale") {
+		t.Errorf("unexpected request %+v", reqs)
+	}
+}
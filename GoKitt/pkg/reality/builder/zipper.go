@@ -2,13 +2,15 @@ package builder
 
 import (
 	"sort"
-	"strings"
 	"unicode"
 
 	"github.com/kittclouds/gokitt/pkg/reality/cst"
 	rsyntax "github.com/kittclouds/gokitt/pkg/reality/syntax"
 	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/markdown"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
 )
 
 // span represents a potential node in the tree
@@ -20,8 +22,9 @@ type span struct {
 }
 
 const (
-	prioPara  = 90
+	prioBlock = 190 // Outermost Markdown block; nested blocks get one less per level
 	prioSent  = 80
+	prioQuote = 60 // Quoted speech, inside its sentence
 	prioChunk = 50
 	prioSpan  = 40 // Entity, Link
 	prioToken = 10
@@ -114,19 +117,9 @@ func Zip(text string, scan conductor.ScanResult) *cst.Node {
 func collectSpans(text string, scan conductor.ScanResult) []span {
 	var spans []span
 
-	// 1. Structure (Heuristic)
-	// Paragraphs
-	paras := splitRanges(text, "\n\n")
-	for _, r := range paras {
-		spans = append(spans, span{rsyntax.KindParagraph, r.Start, r.End, prioPara})
-
-		// Sentences within Paragraph
-		paraText := text[r.Start:r.End]
-		sents := splitSentences(paraText)
-		for _, s := range sents {
-			spans = append(spans, span{rsyntax.KindSentence, r.Start + s.Start, r.Start + s.End, prioSent})
-		}
-	}
+	// 1. Structure (Markdown blocks, headings nested into sections)
+	doc := markdown.Parse(text)
+	spans = appendBlockSpans(spans, text, doc.Blocks, 0)
 
 	// 2. Chunks
 	for _, c := range scan.Chunks {
@@ -134,12 +127,17 @@ func collectSpans(text string, scan conductor.ScanResult) []span {
 		spans = append(spans, span{kind, c.Range.Start, c.Range.End, prioChunk})
 	}
 
-	// 3. Syntax Semantic Spans (Entities/Links)
+	// 3. Quoted speech (chunks never cross a quote boundary)
+	for _, u := range scan.Dialogue {
+		spans = append(spans, span{rsyntax.KindQuoteSpan, u.Range.Start, u.Range.End, prioQuote})
+	}
+
+	// 4. Syntax Semantic Spans (Entities/Links, registered inline patterns)
 	for _, m := range scan.Syntax {
-		spans = append(spans, span{rsyntax.KindEntitySpan, m.Start, m.End, prioSpan})
+		spans = append(spans, span{mapSyntaxKind(m.Kind), m.Start, m.End, prioSpan})
 	}
 
-	// 4. Tokens (Leaves) - Reuse from Scanner
+	// 5. Tokens (Leaves) - Reuse from Scanner
 	for _, t := range scan.Tokens {
 		kind := rsyntax.KindWord
 		switch t.POS {
@@ -156,27 +154,36 @@ func collectSpans(text string, scan conductor.ScanResult) []span {
 	return spans
 }
 
-func splitRanges(text, sep string) []chunker.TextRange {
-	var ranges []chunker.TextRange
-	parts := strings.Split(text, sep)
-	offset := 0
-	sepLen := len(sep)
-	for _, p := range parts {
-		end := offset + len(p)
-		if len(p) > 0 {
-			ranges = append(ranges, chunker.TextRange{Start: offset, End: end})
+// appendBlockSpans adds a span per Markdown block. Deeper blocks get lower
+// priority so containers open before children starting at the same offset.
+// Only prose blocks get sentences, so code and frontmatter never reach projection.
+func appendBlockSpans(spans []span, text string, blocks []*markdown.Block, depth int) []span {
+	prio := prioBlock - depth
+	if prio <= prioSent {
+		prio = prioSent + 1
+	}
+	for _, b := range blocks {
+		spans = append(spans, span{mapBlockKind(b.Kind), b.Range.Start, b.Range.End, prio})
+
+		if b.IsProse() {
+			sents := splitSentences(text[b.Content.Start:b.Content.End])
+			for _, s := range sents {
+				spans = append(spans, span{rsyntax.KindSentence, b.Content.Start + s.Start, b.Content.Start + s.End, prioSent})
+			}
 		}
-		offset = end + sepLen
+
+		spans = appendBlockSpans(spans, text, b.Children, depth+1)
 	}
-	return ranges
+	return spans
 }
 
 func splitSentences(text string) []chunker.TextRange {
-	// Simple heuristic: split by .!?
+	// Simple heuristic: split by .!? outside quotes, so a quote stays in one sentence
+	quotes := dialogue.FindQuotes(text)
 	var ranges []chunker.TextRange
 	start := 0
 	for i, r := range text {
-		if r == '.' || r == '!' || r == '?' {
+		if (r == '.' || r == '!' || r == '?') && !dialogue.Within(quotes, i, i+1) {
 			ranges = append(ranges, chunker.TextRange{Start: start, End: i + 1})
 			start = i + 1
 			// Skip whitespace after
@@ -191,6 +198,14 @@ func splitSentences(text string) []chunker.TextRange {
 	return ranges
 }
 
+// mapSyntaxKind gives each registered inline pattern its own CST kind
+func mapSyntaxKind(k syntax.SyntaxKind) rsyntax.SyntaxKind {
+	if k >= syntax.KindCustom {
+		return rsyntax.KindCustomSpan + rsyntax.SyntaxKind(k-syntax.KindCustom)
+	}
+	return rsyntax.KindEntitySpan
+}
+
 func mapChunkKind(k chunker.ChunkKind) rsyntax.SyntaxKind {
 	switch k {
 	case chunker.NounPhrase:
@@ -207,3 +222,30 @@ func mapChunkKind(k chunker.ChunkKind) rsyntax.SyntaxKind {
 		return rsyntax.KindText
 	}
 }
+
+func mapBlockKind(k markdown.BlockKind) rsyntax.SyntaxKind {
+	switch k {
+	case markdown.BlockSection:
+		return rsyntax.KindSection
+	case markdown.BlockHeading:
+		return rsyntax.KindHeading
+	case markdown.BlockList:
+		return rsyntax.KindList
+	case markdown.BlockListItem:
+		return rsyntax.KindListItem
+	case markdown.BlockQuote:
+		return rsyntax.KindBlockQuote
+	case markdown.BlockCode:
+		return rsyntax.KindCodeBlock
+	case markdown.BlockTable:
+		return rsyntax.KindTable
+	case markdown.BlockTableRow:
+		return rsyntax.KindTableRow
+	case markdown.BlockTableCell:
+		return rsyntax.KindTableCell
+	case markdown.BlockFrontmatter:
+		return rsyntax.KindFrontmatter
+	default:
+		return rsyntax.KindParagraph
+	}
+}
@@ -3,9 +3,11 @@ package builder
 import (
 	"testing"
 
+	"github.com/kittclouds/gokitt/pkg/reality/cst"
 	"github.com/kittclouds/gokitt/pkg/reality/syntax"
 	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	ssyntax "github.com/kittclouds/gokitt/pkg/scanner/syntax"
 )
 
 func TestZipperHierarchical(t *testing.T) {
@@ -86,3 +88,78 @@ func TestZipperHierarchical(t *testing.T) {
 		t.Error("Did not find NP 'The wizard' in first sentence")
 	}
 }
+
+func TestZipperMarkdownStructure(t *testing.T) {
+	text := "# Quest\n\nFrodo left.\n\n## Road\n\n- Sam followed.\n\n```\nSam.Defeat(Frodo)\n```"
+
+	root := Zip(text, conductor.ScanResult{Text: text})
+	t.Logf("Tree:\n%s", root.String(text))
+
+	if len(root.Children) != 1 || root.Children[0].Kind != syntax.KindSection {
+		t.Fatalf("Expected one top-level Section, got %d children", len(root.Children))
+	}
+	quest := root.Children[0]
+
+	var kinds []syntax.SyntaxKind
+	for _, c := range quest.Children {
+		if c.Kind != syntax.KindText {
+			kinds = append(kinds, c.Kind)
+		}
+	}
+	want := []syntax.SyntaxKind{syntax.KindHeading, syntax.KindParagraph, syntax.KindSection}
+	if len(kinds) != len(want) {
+		t.Fatalf("Section children = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("child %d = %s, want %s", i, kinds[i], want[i])
+		}
+	}
+
+	// Sentences appear in prose blocks only, never in code
+	var sentences []string
+	var walk func(n *cst.Node)
+	walk = func(n *cst.Node) {
+		if n.Kind == syntax.KindSentence {
+			if n.Ancestor(syntax.KindCodeBlock) != nil {
+				t.Errorf("Sentence %q inside code block", n.Text(text))
+			}
+			sentences = append(sentences, n.Text(text))
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	if len(sentences) != 4 || sentences[3] != "Sam followed." {
+		t.Errorf("Sentences = %q", sentences)
+	}
+}
+
+func TestZipperCustomSpans(t *testing.T) {
+	text := "On {{date:1042}} [CHARACTER:Aria] left."
+	scan := conductor.ScanResult{
+		Text: text,
+		Syntax: []ssyntax.SyntaxMatch{
+			{Start: 3, End: 16, Kind: ssyntax.KindCustom + 1},
+			{Start: 17, End: 33, Kind: ssyntax.KindEntity},
+		},
+	}
+
+	var kinds []syntax.SyntaxKind
+	var walk func(n *cst.Node)
+	walk = func(n *cst.Node) {
+		if n.Kind == syntax.KindEntitySpan || n.Kind >= syntax.KindCustomSpan {
+			kinds = append(kinds, n.Kind)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(Zip(text, scan))
+
+	want := []syntax.SyntaxKind{syntax.KindCustomSpan + 1, syntax.KindEntitySpan}
+	if len(kinds) != 2 || kinds[0] != want[0] || kinds[1] != want[1] {
+		t.Errorf("span kinds = %v, want %v", kinds, want)
+	}
+}
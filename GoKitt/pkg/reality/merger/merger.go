@@ -26,6 +26,23 @@ type MergedEdge struct {
 	Provenances []Provenance   `json:"provenances"` // Can have multiple sources
 	Attributes  map[string]any `json:"attributes,omitempty"`
 	SourceNotes []string       `json:"sourceNotes,omitempty"` // Which notes this edge came from
+
+	// Event qualifiers (see graph.ConceptEdge). Negated and non-factual edges never
+	// merge with the factual edge between the same nodes.
+	Polarity string `json:"polarity,omitempty"`
+	Modality string `json:"modality,omitempty"`
+	Tense    string `json:"tense,omitempty"`
+
+	// Every addition in order, so a note's share can be withdrawn and the rest replayed
+	contributions []contribution
+}
+
+// contribution is one source's addition to an edge
+type contribution struct {
+	provenance Provenance
+	noteID     string
+	confidence float64
+	tense      string
 }
 
 // MergedGraph is the combined graph from all sources
@@ -46,6 +63,9 @@ type MergeResult struct {
 // Merger combines edges from multiple sources
 type Merger struct {
 	merged *MergedGraph
+
+	// Scanner nodes by contributing note ("" for graphs added without a note)
+	nodeNotes map[string]map[string]bool
 }
 
 // New creates a new Merger
@@ -55,6 +75,7 @@ func New() *Merger {
 			Nodes: make(map[string]*graph.ConceptNode),
 			Edges: make(map[string]*MergedEdge),
 		},
+		nodeNotes: make(map[string]map[string]bool),
 	}
 }
 
@@ -65,8 +86,41 @@ func edgeKey(sourceID, targetID, relType string) string {
 	return fmt.Sprintf("%s-%s-%s", sourceID, strings.ToUpper(relType), targetID)
 }
 
-// AddScannerGraph adds edges from the Go CST scanner/projection
+// qualifiedEdgeKey keeps non-factual edges apart from the fact they qualify.
+// Factual edges keep the plain edgeKey.
+func qualifiedEdgeKey(sourceID, targetID, relType, polarity, modality string) string {
+	key := edgeKey(sourceID, targetID, relType)
+	if graph.IsFactual(polarity, modality) {
+		return key
+	}
+	return key + "~" + polarity + "/" + modality
+}
+
+// IsFactual reports whether the edge states something that happens
+func (e *MergedEdge) IsFactual() bool {
+	return graph.IsFactual(e.Polarity, e.Modality)
+}
+
+// Factual returns the merged graph without negated, possible, intended,
+// hypothetical or reported edges
+func (g *MergedGraph) Factual() *MergedGraph {
+	out := &MergedGraph{Nodes: g.Nodes, Edges: make(map[string]*MergedEdge, len(g.Edges))}
+	for key, e := range g.Edges {
+		if e.IsFactual() {
+			out.Edges[key] = e
+		}
+	}
+	return out
+}
+
+// AddScannerGraph adds edges from the Go CST scanner/projection.
+// A note's graph replaces whatever the scanner previously contributed for that
+// note, so rescanning a changed note doesn't pile up stale edges or boost
+// confidence with itself. Graphs without a note ID are always additive.
 func (m *Merger) AddScannerGraph(g *graph.ConceptGraph, sourceNoteID string) int {
+	if sourceNoteID != "" {
+		m.RemoveScannerNote(sourceNoteID)
+	}
 	added := 0
 
 	// Add nodes
@@ -74,11 +128,16 @@ func (m *Merger) AddScannerGraph(g *graph.ConceptGraph, sourceNoteID string) int
 		if _, exists := m.merged.Nodes[node.ID]; !exists {
 			m.merged.Nodes[node.ID] = node
 		}
+		if m.nodeNotes[node.ID] == nil {
+			m.nodeNotes[node.ID] = make(map[string]bool)
+		}
+		m.nodeNotes[node.ID][sourceNoteID] = true
 	}
 
 	// Add edges
 	for _, edge := range g.AllEdges() {
-		key := edgeKey(edge.Source.ID, edge.Target.ID, string(edge.Edge.Relation))
+		e := edge.Edge
+		key := qualifiedEdgeKey(edge.Source.ID, edge.Target.ID, e.Relation, e.Polarity, e.Modality)
 
 		if existing, exists := m.merged.Edges[key]; exists {
 			// Merge: add provenance, update confidence
@@ -88,6 +147,10 @@ func (m *Merger) AddScannerGraph(g *graph.ConceptGraph, sourceNoteID string) int
 			}
 			// Boost confidence when multiple sources agree
 			existing.Confidence = boostConfidence(existing.Confidence, edge.Edge.Weight)
+			existing.contributions = append(existing.contributions, contribution{ProvenanceScanner, sourceNoteID, edge.Edge.Weight, e.Tense})
+			if existing.Tense == "" {
+				existing.Tense = e.Tense
+			}
 		} else {
 			// New edge
 			notes := []string{}
@@ -101,6 +164,11 @@ func (m *Merger) AddScannerGraph(g *graph.ConceptGraph, sourceNoteID string) int
 				Confidence:  edge.Edge.Weight,
 				Provenances: []Provenance{ProvenanceScanner},
 				SourceNotes: notes,
+				Polarity:    e.Polarity,
+				Modality:    e.Modality,
+				Tense:       e.Tense,
+
+				contributions: []contribution{{ProvenanceScanner, sourceNoteID, edge.Edge.Weight, e.Tense}},
 			}
 			added++
 		}
@@ -117,6 +185,9 @@ type LLMEdgeInput struct {
 	Confidence   float64        `json:"confidence"`
 	Attributes   map[string]any `json:"attributes,omitempty"`
 	SourceNoteID string         `json:"sourceNoteId"`
+	Polarity     string         `json:"polarity,omitempty"`
+	Modality     string         `json:"modality,omitempty"`
+	Tense        string         `json:"tense,omitempty"`
 }
 
 // AddLLMEdges adds edges from LLM extraction
@@ -124,7 +195,7 @@ func (m *Merger) AddLLMEdges(edges []LLMEdgeInput) int {
 	added := 0
 
 	for _, e := range edges {
-		key := edgeKey(e.SourceID, e.TargetID, e.RelType)
+		key := qualifiedEdgeKey(e.SourceID, e.TargetID, e.RelType, e.Polarity, e.Modality)
 
 		if existing, exists := m.merged.Edges[key]; exists {
 			// Merge
@@ -133,6 +204,10 @@ func (m *Merger) AddLLMEdges(edges []LLMEdgeInput) int {
 				existing.SourceNotes = appendUniqueStr(existing.SourceNotes, e.SourceNoteID)
 			}
 			existing.Confidence = boostConfidence(existing.Confidence, e.Confidence)
+			existing.contributions = append(existing.contributions, contribution{ProvenanceLLM, e.SourceNoteID, e.Confidence, e.Tense})
+			if existing.Tense == "" {
+				existing.Tense = e.Tense
+			}
 			// Merge attributes
 			if existing.Attributes == nil {
 				existing.Attributes = make(map[string]any)
@@ -155,6 +230,11 @@ func (m *Merger) AddLLMEdges(edges []LLMEdgeInput) int {
 				Provenances: []Provenance{ProvenanceLLM},
 				Attributes:  e.Attributes,
 				SourceNotes: notes,
+				Polarity:    e.Polarity,
+				Modality:    e.Modality,
+				Tense:       e.Tense,
+
+				contributions: []contribution{{ProvenanceLLM, e.SourceNoteID, e.Confidence, e.Tense}},
 			}
 			added++
 		}
@@ -182,6 +262,7 @@ func (m *Merger) AddManualEdges(edges []ManualEdgeInput) int {
 			// Manual always wins for confidence
 			existing.Provenances = appendUnique(existing.Provenances, ProvenanceManual)
 			existing.Confidence = 1.0 // Manual = certain
+			existing.contributions = append(existing.contributions, contribution{ProvenanceManual, "", 1.0, ""})
 			if existing.Attributes == nil {
 				existing.Attributes = make(map[string]any)
 			}
@@ -196,6 +277,8 @@ func (m *Merger) AddManualEdges(edges []ManualEdgeInput) int {
 				Confidence:  1.0, // Manual = certain
 				Provenances: []Provenance{ProvenanceManual},
 				Attributes:  e.Attributes,
+
+				contributions: []contribution{{ProvenanceManual, "", 1.0, ""}},
 			}
 			added++
 		}
@@ -204,6 +287,79 @@ func (m *Merger) AddManualEdges(edges []ManualEdgeInput) int {
 	return added
 }
 
+// RemoveScannerNote withdraws everything the scanner contributed for a note.
+// Edges that other sources (or other notes) also produced stay, with their
+// confidence, provenances and source notes recomputed from what remains, and
+// so do the nodes they connect.
+// Returns the number of edges removed outright.
+func (m *Merger) RemoveScannerNote(noteID string) int {
+	removed := 0
+	for key, edge := range m.merged.Edges {
+		kept := edge.contributions[:0:0]
+		for _, c := range edge.contributions {
+			if c.provenance == ProvenanceScanner && c.noteID == noteID {
+				continue
+			}
+			kept = append(kept, c)
+		}
+		if len(kept) == len(edge.contributions) {
+			continue
+		}
+		if len(kept) == 0 {
+			delete(m.merged.Edges, key)
+			removed++
+			continue
+		}
+		edge.replay(kept)
+	}
+
+	// A node no note contributes any more stays while an edge from another
+	// source (LLM, manual) still points at it
+	referenced := make(map[string]bool)
+	for _, edge := range m.merged.Edges {
+		referenced[edge.SourceID] = true
+		referenced[edge.TargetID] = true
+	}
+	for id, notes := range m.nodeNotes {
+		if !notes[noteID] {
+			continue
+		}
+		delete(notes, noteID)
+		if len(notes) == 0 {
+			delete(m.nodeNotes, id)
+			if !referenced[id] {
+				delete(m.merged.Nodes, id)
+			}
+		}
+	}
+	return removed
+}
+
+// replay rebuilds the derived fields of an edge from its contributions
+func (e *MergedEdge) replay(contributions []contribution) {
+	e.contributions = contributions
+	e.Provenances = nil
+	e.SourceNotes = []string{}
+	e.Tense = ""
+	for i, c := range contributions {
+		if e.Tense == "" {
+			e.Tense = c.tense
+		}
+		e.Provenances = appendUnique(e.Provenances, c.provenance)
+		if c.noteID != "" {
+			e.SourceNotes = appendUniqueStr(e.SourceNotes, c.noteID)
+		}
+		switch {
+		case c.provenance == ProvenanceManual:
+			e.Confidence = 1.0
+		case i == 0:
+			e.Confidence = c.confidence
+		default:
+			e.Confidence = boostConfidence(e.Confidence, c.confidence)
+		}
+	}
+}
+
 // GetMergedGraph returns the combined graph
 func (m *Merger) GetMergedGraph() *MergedGraph {
 	return m.merged
@@ -267,6 +423,9 @@ func (m *Merger) ToConceptGraph() *graph.ConceptGraph {
 		g.AddEdge(source, target, &graph.ConceptEdge{
 			Relation: edge.RelType,
 			Weight:   weight,
+			Polarity: edge.Polarity,
+			Modality: edge.Modality,
+			Tense:    edge.Tense,
 		})
 	}
 
@@ -0,0 +1,113 @@
+package merger
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+)
+
+func scannerGraph(edges ...[3]string) *graph.ConceptGraph {
+	g := graph.NewGraph()
+	for _, e := range edges {
+		g.EnsureNode(e[0], e[0], graph.KindConcept)
+		g.EnsureNode(e[2], e[2], graph.KindConcept)
+		g.AddLabeledEdge(e[0], e[2], e[1], 0.5)
+	}
+	return g
+}
+
+func TestAddScannerGraphReplacesNoteContribution(t *testing.T) {
+	m := New()
+	m.AddScannerGraph(scannerGraph([3]string{"Frodo", "KNOWS", "Sam"}), "note-1")
+
+	// Rescanning the same note must not boost its own edge
+	m.AddScannerGraph(scannerGraph([3]string{"Frodo", "KNOWS", "Sam"}), "note-1")
+	edge := m.GetMergedGraph().Edges[edgeKey("Frodo", "Sam", "KNOWS")]
+	if edge == nil || edge.Confidence != 0.5 {
+		t.Fatalf("expected single contribution at 0.5, got %+v", edge)
+	}
+
+	// The note changes: the old edge and its orphaned node disappear
+	m.AddScannerGraph(scannerGraph([3]string{"Frodo", "KNOWS", "Gollum"}), "note-1")
+	merged := m.GetMergedGraph()
+	if _, ok := merged.Edges[edgeKey("Frodo", "Sam", "KNOWS")]; ok {
+		t.Error("stale edge survived a rescan")
+	}
+	if _, ok := merged.Nodes["Sam"]; ok {
+		t.Error("orphaned node survived a rescan")
+	}
+	if _, ok := merged.Edges[edgeKey("Frodo", "Gollum", "KNOWS")]; !ok {
+		t.Error("new edge missing")
+	}
+}
+
+func TestRemoveScannerNoteKeepsOtherSources(t *testing.T) {
+	m := New()
+	m.AddScannerGraph(scannerGraph([3]string{"Frodo", "KNOWS", "Sam"}), "note-1")
+	m.AddScannerGraph(scannerGraph([3]string{"Frodo", "KNOWS", "Sam"}), "note-2")
+	m.AddLLMEdges([]LLMEdgeInput{{SourceID: "Frodo", TargetID: "Sam", RelType: "KNOWS", Confidence: 0.8, SourceNoteID: "note-3"}})
+
+	key := edgeKey("Frodo", "Sam", "KNOWS")
+	edge := m.GetMergedGraph().Edges[key]
+	before := edge.Confidence
+
+	if removed := m.RemoveScannerNote("note-1"); removed != 0 {
+		t.Errorf("removed %d edges, want 0", removed)
+	}
+	edge = m.GetMergedGraph().Edges[key]
+	want := boostConfidence(0.5, 0.8)
+	if math.Abs(edge.Confidence-want) > 1e-9 || edge.Confidence >= before {
+		t.Errorf("confidence = %f, want %f", edge.Confidence, want)
+	}
+	if len(edge.SourceNotes) != 2 || edge.SourceNotes[0] != "note-2" || edge.SourceNotes[1] != "note-3" {
+		t.Errorf("source notes = %v", edge.SourceNotes)
+	}
+
+	m.RemoveScannerNote("note-2")
+	edge = m.GetMergedGraph().Edges[key]
+	if len(edge.Provenances) != 1 || edge.Provenances[0] != ProvenanceLLM || edge.Confidence != 0.8 {
+		t.Errorf("LLM-only edge = %+v", edge)
+	}
+	// The LLM edge still needs its endpoints
+	if node := m.GetMergedGraph().Nodes["Frodo"]; node == nil || node.Label != "Frodo" {
+		t.Errorf("node of a remaining LLM edge was dropped: %+v", node)
+	}
+
+	m.AddScannerGraph(scannerGraph([3]string{"Frodo", "KNOWS", "Gollum"}), "note-4")
+	m.RemoveScannerNote("note-4")
+	if _, ok := m.GetMergedGraph().Nodes["Gollum"]; ok {
+		t.Error("scanner node kept after its last note was removed")
+	}
+}
+
+func TestQualifiedEdgesStayApart(t *testing.T) {
+	g := graph.NewGraph()
+	aria := g.EnsureNode("Aria", "Aria", graph.KindConcept)
+	king := g.EnsureNode("King", "King", graph.KindConcept)
+	g.AddEdge(aria, king, &graph.ConceptEdge{Relation: "KILLS", Weight: 0.5, Polarity: "affirmed", Modality: "factual", Tense: "past"})
+	g.AddEdge(aria, king, &graph.ConceptEdge{Relation: "KILLS", Weight: 0.5, Polarity: "negated", Modality: "factual", Tense: "past"})
+	g.AddEdge(aria, king, &graph.ConceptEdge{Relation: "KILLS", Weight: 0.5, Polarity: "affirmed", Modality: "hypothetical", Tense: "present"})
+
+	m := New()
+	m.AddScannerGraph(g, "note-1")
+	merged := m.GetMergedGraph()
+	if len(merged.Edges) != 3 {
+		t.Fatalf("edges = %d, want the fact and two qualified variants", len(merged.Edges))
+	}
+	fact := merged.Edges[edgeKey("Aria", "King", "KILLS")]
+	if fact == nil || !fact.IsFactual() || fact.Tense != "past" || fact.Confidence != 0.5 {
+		t.Errorf("factual edge = %+v", fact)
+	}
+
+	factual := merged.Factual()
+	if len(factual.Edges) != 1 || factual.Edges[edgeKey("Aria", "King", "KILLS")] == nil {
+		t.Errorf("Factual() kept %d edges", len(factual.Edges))
+	}
+
+	// LLM edges without qualifiers merge with the fact
+	m.AddLLMEdges([]LLMEdgeInput{{SourceID: "Aria", TargetID: "King", RelType: "KILLS", Confidence: 0.8}})
+	if len(m.GetMergedGraph().Edges) != 3 || len(fact.Provenances) != 2 {
+		t.Errorf("unqualified LLM edge should merge with the fact: %+v", fact)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/kittclouds/gokitt/pkg/hierarchy"
 	"github.com/kittclouds/gokitt/pkg/reality/cst"
 	rsyntax "github.com/kittclouds/gokitt/pkg/reality/syntax"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
 	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
 )
 
 // EntityResolver maps a text offset (start) to an Entity ID
@@ -44,12 +47,78 @@ func Project(root *cst.Node, matcher *narrative.NarrativeMatcher, entities Entit
 	return g
 }
 
+// ProjectDialogue adds a SPEAKS_TO edge per attributed utterance whose listener
+// is known, spanning the quote and qualified by its tag. Lines without a
+// listener only count towards the speaker's dialogue (see dialogue.LineCounts).
+// Speakers and listeners take their kind from the scan's entities.
+func ProjectDialogue(g *graph.ConceptGraph, utterances []dialogue.Utterance, entities map[string]conductor.EntityName, prov *hierarchy.ProvenanceContext) {
+	var worldNode *graph.ConceptNode
+	if prov != nil && prov.WorldID != "" {
+		worldNode = g.Nodes["world:"+prov.WorldID]
+	}
+
+	for _, u := range utterances {
+		if u.Speaker == dialogue.Unknown || u.Listener == "" {
+			continue
+		}
+		speaker := g.EnsureNode(u.Speaker, nodeLabel(u.Speaker), entityKind(entities, u.Speaker))
+		listener := g.EnsureNode(u.Listener, nodeLabel(u.Listener), entityKind(entities, u.Listener))
+		g.AddEdge(speaker, listener, &graph.ConceptEdge{
+			Relation:   narrative.RelSpeaksTo.String(),
+			Weight:     1.0,
+			SourceSpan: [2]int{u.Range.Start, u.Range.End},
+			Recipient:  u.Listener,
+			Polarity:   u.Qualifier.Polarity.String(),
+			Modality:   u.Qualifier.Modality.String(),
+			Tense:      u.Qualifier.Tense.String(),
+
+			SourceMembers: resolver.GroupMembers(u.Speaker),
+			TargetMembers: resolver.GroupMembers(u.Listener),
+		})
+
+		if worldNode != nil {
+			ensureWorldLink(g, worldNode, speaker)
+			ensureWorldLink(g, worldNode, listener)
+		}
+	}
+}
+
+// ProjectPossessions adds an OWNS edge per possessive ("Mira's sword"),
+// spanning the owner and the owned
+func ProjectPossessions(g *graph.ConceptGraph, possessions []conductor.Possession, prov *hierarchy.ProvenanceContext) {
+	var worldNode *graph.ConceptNode
+	if prov != nil && prov.WorldID != "" {
+		worldNode = g.Nodes["world:"+prov.WorldID]
+	}
+
+	for _, p := range possessions {
+		owner := g.EnsureNode(p.Owner, nodeLabel(p.Owner), "Concept")
+		owned := g.EnsureNode(p.Owned, nodeLabel(p.Owned), "Concept")
+		g.AddEdge(owner, owned, &graph.ConceptEdge{
+			Relation:      narrative.RelOwns.String(),
+			Weight:        1.0,
+			SourceSpan:    [2]int{p.Range.Start, p.Range.End},
+			SourceMembers: resolver.GroupMembers(p.Owner),
+		})
+
+		if worldNode != nil {
+			ensureWorldLink(g, worldNode, owner)
+			ensureWorldLink(g, worldNode, owned)
+		}
+	}
+}
+
 func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.NarrativeMatcher, entities EntityMap, source string, worldNode *graph.ConceptNode) {
 	// 1. Flatten children into sequential list
+	// Quoted speech isn't narration; its sentence's speech verbs belong to ProjectDialogue
 	var nodes []*cst.Node
+	hasQuote := false
 	var gather func(n *cst.Node)
 	gather = func(n *cst.Node) {
 		switch n.Kind {
+		case rsyntax.KindQuoteSpan:
+			hasQuote = true
+			return
 		case rsyntax.KindNounPhrase, rsyntax.KindVerbPhrase, rsyntax.KindEntitySpan, rsyntax.KindPrepPhrase, rsyntax.KindAdjPhrase, rsyntax.KindWord:
 			nodes = append(nodes, n)
 			return
@@ -63,8 +132,7 @@ func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.N
 	// 2. Iterate VPs to find relations
 	for i, n := range nodes {
 		if n.Kind == rsyntax.KindVerbPhrase {
-			verbText := n.Text(source)
-			match := matcher.Lookup(verbText)
+			match, phraseEnd := lookupVerb(n, matcher, source)
 
 			if match != nil {
 				// Find Subject (Left)
@@ -76,8 +144,15 @@ func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.N
 
 				relType := match.RelationType.String()
 				isCommunication := relType == "SPEAKS_TO" || relType == "MENTIONS" || relType == "REVEALS"
+				if hasQuote && relType == "SPEAKS_TO" {
+					continue
+				}
 
 				searchOffset := 1 // Start searching for object at verb + 1
+				// Skip particles a phrasal verb consumed ("set out for [Mordor]")
+				for phraseEnd > n.Range.End && i+searchOffset < len(nodes) && nodes[i+searchOffset].Range.End <= phraseEnd {
+					searchOffset++
+				}
 
 				if isCommunication {
 					// 1. Check for "that" (Attribution)
@@ -142,19 +217,28 @@ func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.N
 						}
 					}
 
-					// Add QuadPlus (with recipient)
-					g.AddQuadPlus(
-						subjID, subjID, "Concept",
-						targetID, targetID, "Concept",
-						relType,
-						1.0,
-						manner, location, time, recipientID,
-					)
+					// Add QuadPlus (with recipient), qualified by the verb phrase and its clause
+					q := narrative.Qualify(n.Text(source), source[sent.Range.Start:n.Range.Start])
+					// A group subject or object ("they" for "Aria and Tom") carries its members
+					g.AddEdge(g.EnsureNode(subjID, nodeLabel(subjID), "Concept"), g.EnsureNode(targetID, nodeLabel(targetID), "Concept"), &graph.ConceptEdge{
+						Relation:  relType,
+						Weight:    1.0,
+						Manner:    manner,
+						Location:  location,
+						Time:      time,
+						Recipient: recipientID,
+						Polarity:  q.Polarity.String(),
+						Modality:  q.Modality.String(),
+						Tense:     q.Tense.String(),
+
+						SourceMembers: resolver.GroupMembers(subjID),
+						TargetMembers: resolver.GroupMembers(targetID),
+					})
 
 					// Link to World (if exists and hasn't been linked yet)
 					if worldNode != nil {
 						// Link Subject
-						subjNode, _ := g.Nodes[subjID] // Should exist after AddQuadPlus
+						subjNode, _ := g.Nodes[subjID] // Should exist after AddEdge
 						if subjNode != nil {
 							ensureWorldLink(g, worldNode, subjNode)
 						}
@@ -170,6 +254,23 @@ func processSentence(sent *cst.Node, g *graph.ConceptGraph, matcher *narrative.N
 	}
 }
 
+// lookupVerb matches the longest verb or phrasal verb starting at one of the
+// phrase's words, from the last one, so auxiliaries and negators ("did not
+// kill") don't hide the main verb. Returns the match and where it ends in source.
+func lookupVerb(vp *cst.Node, matcher *narrative.NarrativeMatcher, source string) (*narrative.VerbMatch, int) {
+	if match := matcher.Lookup(vp.Text(source)); match != nil {
+		return match, vp.Range.End
+	}
+	for j := len(vp.Children) - 1; j >= 0; j-- {
+		if w := vp.Children[j]; w.Kind == rsyntax.KindWord {
+			if match, n := matcher.LookupPhrase(source[w.Range.Start:]); match != nil {
+				return match, w.Range.Start + n
+			}
+		}
+	}
+	return nil, vp.Range.End
+}
+
 // findRecipient looks for "to [CapitalizedWord]" pattern after a verb
 // Returns the name if found, empty string otherwise
 func findRecipient(nodes []*cst.Node, verbIdx int, source string) string {
@@ -272,6 +373,48 @@ func findToken(nodes []*cst.Node, startIdx int, tokenText string, source string,
 	return -1
 }
 
+// NameEntities labels the nodes of matched entities by name, since
+// dictionary IDs are not names; a group is labeled by its members' names.
+// Generic Concept nodes of matched entities also take the entity's kind.
+func NameEntities(g *graph.ConceptGraph, entities map[string]conductor.EntityName) {
+	name := func(id string) string {
+		if e, ok := entities[id]; ok && e.Name != "" {
+			return e.Name
+		}
+		return id
+	}
+	for id, node := range g.Nodes {
+		if members := resolver.GroupMembers(id); members != nil {
+			names := make([]string, len(members))
+			for i, m := range members {
+				names[i] = name(m)
+			}
+			node.Label = strings.Join(names, " and ")
+		} else if _, ok := entities[id]; ok {
+			node.Label = name(id)
+			if node.Kind == graph.KindConcept {
+				node.Kind = entityKind(entities, id)
+			}
+		}
+	}
+}
+
+// entityKind returns the kind of a matched entity, or Concept
+func entityKind(entities map[string]conductor.EntityName, id string) string {
+	if e, ok := entities[id]; ok && e.Kind != "" {
+		return e.Kind
+	}
+	return graph.KindConcept
+}
+
+// nodeLabel labels a group node by its members ("Aria and Tom"), other nodes by ID
+func nodeLabel(id string) string {
+	if members := resolver.GroupMembers(id); members != nil {
+		return strings.Join(members, " and ")
+	}
+	return id
+}
+
 // ensureWorldLink creates a WORLD_CONTAINS edge if it doesn't represent
 func ensureWorldLink(g *graph.ConceptGraph, world *graph.ConceptNode, entity *graph.ConceptNode) {
 	// Optimization: Check existing outbound edges first to avoid duplicates
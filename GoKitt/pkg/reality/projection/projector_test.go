@@ -0,0 +1,58 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+)
+
+func TestProjectDialogueQualifiesAndKindsItsEdges(t *testing.T) {
+	g := graph.NewGraph()
+	entities := map[string]conductor.EntityName{
+		"e-frodo": {Name: "Frodo", Kind: "CHARACTER"},
+		"e-sam":   {Name: "Sam", Kind: "CHARACTER"},
+	}
+	// "Run," Frodo did not say to Sam.
+	ProjectDialogue(g, []dialogue.Utterance{{
+		Speaker:   "e-frodo",
+		Listener:  "e-sam",
+		Range:     chunker.NewRange(0, 6),
+		Qualifier: narrative.Qualifier{Polarity: narrative.Negated, Tense: narrative.Past},
+	}}, entities, nil)
+
+	frodo, sam := g.Nodes["e-frodo"], g.Nodes["e-sam"]
+	if frodo == nil || sam == nil {
+		t.Fatalf("speaker and listener nodes missing: %v", g.Nodes)
+	}
+	if frodo.Kind != "CHARACTER" || sam.Kind != "CHARACTER" {
+		t.Errorf("kinds = %s, %s, want CHARACTER", frodo.Kind, sam.Kind)
+	}
+	if len(frodo.Outbound) != 1 {
+		t.Fatalf("edges = %d, want 1", len(frodo.Outbound))
+	}
+	edge := frodo.Outbound[0]
+	if edge.Polarity != graph.PolarityNegated || edge.Tense != narrative.Past.String() {
+		t.Errorf("edge qualifiers = %s/%s/%s, want negated past", edge.Polarity, edge.Modality, edge.Tense)
+	}
+	if edge.IsFactual() {
+		t.Error("a denied line should not be a factual SPEAKS_TO")
+	}
+}
+
+func TestNameEntitiesSetsConceptKinds(t *testing.T) {
+	g := graph.NewGraph()
+	g.EnsureNode("e-frodo", "e-frodo", graph.KindConcept)
+	g.EnsureNode("Mordor", "Mordor", graph.KindConcept)
+
+	NameEntities(g, map[string]conductor.EntityName{"e-frodo": {Name: "Frodo", Kind: "CHARACTER"}})
+	if n := g.Nodes["e-frodo"]; n.Label != "Frodo" || n.Kind != "CHARACTER" {
+		t.Errorf("e-frodo = %s (%s), want Frodo (CHARACTER)", n.Label, n.Kind)
+	}
+	if n := g.Nodes["Mordor"]; n.Kind != graph.KindConcept {
+		t.Errorf("unmatched node kind = %s, want Concept", n.Kind)
+	}
+}
@@ -0,0 +1,380 @@
+// Package scancache memoizes the Scan → Zip → Project pipeline per note.
+//
+// Notes are cached by ID and version (falling back to a content hash). When a
+// note changes, its text is split into top-level Markdown blocks (so fenced
+// code and frontmatter are never cut in half), and only paragraphs whose text
+// changed are rescanned and reprojected; unchanged paragraphs reuse their
+// results even if they moved.
+//
+// A note's paragraphs are scanned in order with one resolver context, chosen
+// per call (see Options), so a pronoun can refer back to an earlier paragraph.
+// A paragraph is only reused if it would start from the same context, so a
+// partial rescan gives the same result as scanning the whole note.
+package scancache
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/hierarchy"
+	"github.com/kittclouds/gokitt/pkg/reality/builder"
+	"github.com/kittclouds/gokitt/pkg/reality/projection"
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+	"github.com/kittclouds/gokitt/pkg/scanner/markdown"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+)
+
+// Scanner is the part of the conductor the cache drives.
+type Scanner interface {
+	Session(narrativeID string) *conductor.ScanSession
+	GetMatcher() *narrative.NarrativeMatcher
+}
+
+// Options select how a note is scanned. A note scanned with other options
+// is rescanned in full.
+type Options struct {
+	NarrativeID string                // Scopes matching to the note's narrative; "" for the default session
+	Context     conductor.ContextMode // How the session's context flows into the note
+}
+
+// Result is the pipeline output for one note version.
+// Scan offsets are in note coordinates. Graph is shared with the cache: read only.
+type Result struct {
+	NoteID     string
+	Version    int64
+	Scan       conductor.ScanResult
+	Graph      *graph.ConceptGraph
+	Paragraphs int  // Paragraphs in the note
+	Rescanned  int  // Paragraphs scanned by this call
+	Cached     bool // Served without running the pipeline at all
+}
+
+// paragraph is one cached paragraph, in paragraph-local coordinates.
+type paragraph struct {
+	rng   chunker.TextRange
+	hash  uint64
+	scan  conductor.ScanResult
+	graph *graph.ConceptGraph
+
+	// Resolver context before and after the paragraph
+	startKey uint64
+	endKey   uint64
+	end      *resolver.NarrativeContext
+}
+
+type entry struct {
+	version    int64
+	hash       uint64
+	key        string // Provenance and options the note was scanned with
+	paragraphs []*paragraph
+	result     *Result
+}
+
+// Cache holds per-note scan results.
+// Thread-safe for concurrent WASM callbacks.
+type Cache struct {
+	mu      sync.Mutex
+	scanner Scanner
+	notes   map[string]*entry
+}
+
+// New creates an empty cache around a scanner.
+func New(scanner Scanner) *Cache {
+	return &Cache{
+		scanner: scanner,
+		notes:   make(map[string]*entry),
+	}
+}
+
+// Scan returns the pipeline result for a note, reusing whatever it can.
+// A non-zero version equal to the cached one is a hit without looking at the
+// text; otherwise the content hash decides. Either way the note must start
+// from the context it was scanned in. Changing prov invalidates the note.
+// The note is scanned in the default session without pronoun history, so
+// results don't depend on which notes were scanned before.
+func (c *Cache) Scan(noteID string, version int64, text string, prov *hierarchy.ProvenanceContext) *Result {
+	return c.ScanWith(noteID, version, text, prov, Options{Context: conductor.ContextReset})
+}
+
+// ScanWith is Scan with scan options
+func (c *Cache) ScanWith(noteID string, version int64, text string, prov *hierarchy.ProvenanceContext, opts Options) *Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := provKey(prov) + "\x00" + opts.NarrativeID + "\x00" + opts.Context.String()
+	old := c.notes[noteID]
+	note := c.scanner.Session(opts.NarrativeID).BeginNote(opts.Context)
+	ctxKey := note.ContextKey()
+	if old != nil && old.key == key && old.startsFrom(ctxKey) {
+		hit := version != 0 && old.version == version
+		if !hit && hashString(text) == old.hash {
+			old.version = version
+			old.result.Version = version
+			hit = true
+		}
+		if hit {
+			if n := len(old.paragraphs); n > 0 && opts.Context == conductor.ContextCarry {
+				note.Resume(old.paragraphs[n-1].end) // As if the note had been scanned
+			}
+			return cachedCopy(old.result)
+		}
+	}
+
+	// Reusable paragraphs by content hash (only when provenance and options are unchanged)
+	reusable := make(map[uint64][]*paragraph)
+	if old != nil && old.key == key {
+		for _, p := range old.paragraphs {
+			reusable[p.hash] = append(reusable[p.hash], p)
+		}
+	}
+
+	e := &entry{version: version, hash: hashString(text), key: key}
+	rescanned := 0
+	var skipped *paragraph // Last reused paragraph, if the note hasn't caught up with it
+	for _, rng := range markdown.SplitBlocks(text) {
+		paraText := text[rng.Start:rng.End]
+		h := hashString(paraText)
+
+		if p := takeReusable(reusable, h, ctxKey); p != nil {
+			e.paragraphs = append(e.paragraphs, &paragraph{
+				rng: rng, hash: h, scan: p.scan, graph: p.graph,
+				startKey: p.startKey, endKey: p.endKey, end: p.end,
+			})
+			ctxKey = p.endKey
+			skipped = p
+			continue
+		}
+
+		if skipped != nil {
+			note.Resume(skipped.end)
+			skipped = nil
+		}
+		p := c.scanParagraph(note, paraText, rng, h, prov)
+		p.startKey = ctxKey
+		p.end = note.Snapshot()
+		p.endKey = p.end.Key()
+		ctxKey = p.endKey
+		e.paragraphs = append(e.paragraphs, p)
+		rescanned++
+	}
+	if skipped != nil && opts.Context == conductor.ContextCarry {
+		note.Resume(skipped.end) // The session carries on from the end of the note
+	}
+
+	e.result = &Result{
+		NoteID:     noteID,
+		Version:    version,
+		Scan:       assembleScan(text, e.paragraphs),
+		Graph:      assembleGraph(e.paragraphs),
+		Paragraphs: len(e.paragraphs),
+		Rescanned:  rescanned,
+	}
+	c.notes[noteID] = e
+
+	res := *e.result
+	return &res
+}
+
+// startsFrom reports whether the note was scanned from the context ctxKey
+func (e *entry) startsFrom(ctxKey uint64) bool {
+	return len(e.paragraphs) == 0 || e.paragraphs[0].startKey == ctxKey
+}
+
+// Invalidate drops a note's cached results.
+func (c *Cache) Invalidate(noteID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.notes, noteID)
+}
+
+// Clear drops everything, e.g. after the entity dictionary changes.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.notes = make(map[string]*entry)
+}
+
+// Len returns the number of cached notes.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.notes)
+}
+
+// takeReusable removes and returns a cached paragraph with this text hash
+// that started from the context ctxKey, or nil
+func takeReusable(reusable map[uint64][]*paragraph, h, ctxKey uint64) *paragraph {
+	candidates := reusable[h]
+	for i, p := range candidates {
+		if p.startKey == ctxKey {
+			reusable[h] = append(candidates[:i:i], candidates[i+1:]...)
+			return p
+		}
+	}
+	return nil
+}
+
+// scanParagraph runs the full pipeline over one paragraph.
+func (c *Cache) scanParagraph(note *conductor.NoteScan, text string, rng chunker.TextRange, h uint64, prov *hierarchy.ProvenanceContext) *paragraph {
+	scan := note.Scan(text)
+	root := builder.Zip(text, scan)
+
+	entityMap := make(projection.EntityMap)
+	for _, ref := range scan.ResolvedRefs {
+		entityMap[ref.Range.Start] = ref.EntityID
+	}
+
+	g := projection.Project(root, c.scanner.GetMatcher(), entityMap, text, prov)
+	projection.ProjectDialogue(g, scan.Dialogue, scan.Entities, prov)
+	projection.ProjectPossessions(g, scan.Possessions, prov)
+	projection.NameEntities(g, scan.Entities)
+
+	return &paragraph{
+		rng:   rng,
+		hash:  h,
+		scan:  scan,
+		graph: g,
+	}
+}
+
+// assembleScan stitches paragraph results into one result in note coordinates.
+func assembleScan(text string, paragraphs []*paragraph) conductor.ScanResult {
+	out := conductor.ScanResult{Text: text, CleanText: markdown.Parse(text).Mask(text)}
+	for _, p := range paragraphs {
+		if p.rng.Start == 0 && p.scan.Frontmatter != nil {
+			out.Frontmatter = p.scan.Frontmatter
+		}
+		off := p.rng.Start
+		for _, m := range p.scan.Syntax {
+			m.Start += off
+			m.End += off
+			out.Syntax = append(out.Syntax, m)
+		}
+		for _, t := range p.scan.Tokens {
+			t.Range = shift(t.Range, off)
+			out.Tokens = append(out.Tokens, t)
+		}
+		for _, ch := range p.scan.Chunks {
+			ch.Range = shift(ch.Range, off)
+			ch.Head = shift(ch.Head, off)
+			if len(ch.Modifiers) > 0 {
+				mods := make([]chunker.TextRange, len(ch.Modifiers))
+				for i, m := range ch.Modifiers {
+					mods[i] = shift(m, off)
+				}
+				ch.Modifiers = mods
+			}
+			out.Chunks = append(out.Chunks, ch)
+		}
+		for _, ev := range p.scan.Narrative {
+			ev.Range = shift(ev.Range, off)
+			out.Narrative = append(out.Narrative, ev)
+		}
+		for _, ref := range p.scan.ResolvedRefs {
+			ref.Range = shift(ref.Range, off)
+			out.ResolvedRefs = append(out.ResolvedRefs, ref)
+		}
+		for _, pos := range p.scan.Possessions {
+			pos.Range = shift(pos.Range, off)
+			out.Possessions = append(out.Possessions, pos)
+		}
+		for _, amb := range p.scan.Ambiguities {
+			amb.Range = shift(amb.Range, off)
+			out.Ambiguities = append(out.Ambiguities, amb)
+		}
+		for _, u := range p.scan.Dialogue {
+			u.Range = shift(u.Range, off)
+			u.Content = shift(u.Content, off)
+			out.Dialogue = append(out.Dialogue, u)
+		}
+		for id, name := range p.scan.Entities {
+			if out.Entities == nil {
+				out.Entities = make(map[string]conductor.EntityName)
+			}
+			out.Entities[id] = name
+		}
+	}
+	return out
+}
+
+// assembleGraph unions the paragraph graphs. World links are deduplicated the
+// same way the projector does within a single note.
+func assembleGraph(paragraphs []*paragraph) *graph.ConceptGraph {
+	g := graph.NewGraph()
+	for _, p := range paragraphs {
+		for _, node := range p.graph.Nodes {
+			g.EnsureNode(node.ID, node.Label, node.Kind)
+		}
+	}
+	for _, p := range paragraphs {
+		for _, node := range p.graph.Nodes {
+			source := g.Nodes[node.ID]
+			for _, edge := range node.Outbound {
+				target := g.Nodes[edge.Target.ID]
+				if edge.Relation == graph.RelWorldContains && hasEdge(source, target, edge.Relation) {
+					continue
+				}
+				span := edge.SourceSpan
+				if span != [2]int{} {
+					span = [2]int{span[0] + p.rng.Start, span[1] + p.rng.Start}
+				}
+				g.AddEdge(source, target, &graph.ConceptEdge{
+					Relation:   edge.Relation,
+					Weight:     edge.Weight,
+					SourceDoc:  edge.SourceDoc,
+					SourceSpan: span,
+					Manner:     edge.Manner,
+					Location:   edge.Location,
+					Time:       edge.Time,
+					Recipient:  edge.Recipient,
+					Polarity:   edge.Polarity,
+					Modality:   edge.Modality,
+					Tense:      edge.Tense,
+
+					SourceMembers: edge.SourceMembers,
+					TargetMembers: edge.TargetMembers,
+				})
+			}
+		}
+	}
+	return g
+}
+
+func hasEdge(source, target *graph.ConceptNode, relation string) bool {
+	for _, e := range source.Outbound {
+		if e.Target == target && e.Relation == relation {
+			return true
+		}
+	}
+	return false
+}
+
+func shift(r chunker.TextRange, off int) chunker.TextRange {
+	return chunker.TextRange{Start: r.Start + off, End: r.End + off}
+}
+
+func cachedCopy(r *Result) *Result {
+	res := *r
+	res.Rescanned = 0
+	res.Cached = true
+	return &res
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func provKey(prov *hierarchy.ProvenanceContext) string {
+	if prov == nil {
+		return ""
+	}
+	return prov.VaultID + "\x00" + prov.WorldID + "\x00" + prov.ParentPath + "\x00" + prov.FolderType
+}
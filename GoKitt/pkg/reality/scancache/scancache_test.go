@@ -0,0 +1,332 @@
+package scancache
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/graph"
+	"github.com/kittclouds/gokitt/pkg/hierarchy"
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/reality/builder"
+	"github.com/kittclouds/gokitt/pkg/reality/projection"
+	"github.com/kittclouds/gokitt/pkg/scanner/conductor"
+)
+
+func newScanner(t *testing.T) *conductor.Conductor {
+	c, err := conductor.New()
+	if err != nil {
+		t.Fatalf("conductor: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+const (
+	paraA = "[CHARACTER:Gandalf] traveled to [LOCATION:Mountain]."
+	paraB = "[CHARACTER:Frodo] defeated the [MONSTER:Balrog]."
+	paraC = "The road went ever on."
+)
+
+func TestCacheHitByVersionAndHash(t *testing.T) {
+	scanner := newScanner(t)
+	cache := New(scanner)
+	text := paraA + "\n\n" + paraB
+
+	first := cache.Scan("n1", 1, text, nil)
+	if first.Cached || first.Rescanned != 2 || first.Paragraphs != 2 {
+		t.Fatalf("first scan: cached=%v rescanned=%d paragraphs=%d", first.Cached, first.Rescanned, first.Paragraphs)
+	}
+
+	// Same version: served from cache
+	again := cache.Scan("n1", 1, text, nil)
+	if !again.Cached || again.Graph != first.Graph {
+		t.Error("same version should be a cache hit")
+	}
+
+	// New version, same text: content hash hit
+	bumped := cache.Scan("n1", 2, text, nil)
+	if !bumped.Cached || bumped.Version != 2 || bumped.Rescanned != 0 {
+		t.Errorf("unchanged text should hit by hash, got cached=%v version=%d rescanned=%d", bumped.Cached, bumped.Version, bumped.Rescanned)
+	}
+}
+
+func TestCacheRescansOnlyChangedParagraphs(t *testing.T) {
+	scanner := newScanner(t)
+	cache := New(scanner)
+
+	cache.Scan("n1", 1, paraA+"\n\n"+paraB, nil)
+
+	// Insert a paragraph before B: A and B are reused, B moves
+	text := paraA + "\n\n" + paraC + "\n\n" + paraB
+	res := cache.Scan("n1", 2, text, nil)
+
+	if res.Rescanned != 1 || res.Paragraphs != 3 {
+		t.Fatalf("only the new paragraph should be scanned: rescanned=%d paragraphs=%d", res.Rescanned, res.Paragraphs)
+	}
+
+	// Offsets of reused paragraphs are shifted into note coordinates
+	for _, m := range res.Scan.Syntax {
+		if got := text[m.Start:m.End]; !strings.HasPrefix(got, "[") || !strings.HasSuffix(got, "]") {
+			t.Errorf("syntax match %q has wrong offsets %d-%d (text %q)", m.Label, m.Start, m.End, got)
+		}
+	}
+	for _, tok := range res.Scan.Tokens {
+		if got := text[tok.Range.Start:tok.Range.End]; got != tok.Text {
+			t.Errorf("token %q has offsets pointing at %q", tok.Text, got)
+		}
+	}
+
+	// Same graph as running the pipeline over the whole note
+	direct := newScanner(t)
+	scan := direct.Scan(text)
+	entityMap := make(projection.EntityMap)
+	for _, ref := range scan.ResolvedRefs {
+		entityMap[ref.Range.Start] = ref.EntityID
+	}
+	want := edgeSet(projection.Project(builder.Zip(text, scan), direct.GetMatcher(), entityMap, text, nil))
+	if got := edgeSet(res.Graph); !reflect.DeepEqual(got, want) {
+		t.Errorf("graph edges = %v, want %v", got, want)
+	}
+}
+
+func edgeSet(g *graph.ConceptGraph) map[string]int {
+	set := make(map[string]int)
+	for _, e := range g.AllEdges() {
+		set[e.Source.ID+" "+e.Edge.Relation+" "+e.Target.ID]++
+	}
+	return set
+}
+
+func TestCacheProvenanceAndInvalidate(t *testing.T) {
+	scanner := newScanner(t)
+	cache := New(scanner)
+	prov := &hierarchy.ProvenanceContext{WorldID: "n1", ParentPath: "Notes/n1"}
+
+	res := cache.Scan("n1", 1, paraA, prov)
+	if res.Graph.GetNode("world:n1") == nil {
+		t.Error("world node missing")
+	}
+
+	// Different provenance reprojects everything
+	res = cache.Scan("n1", 1, paraA, nil)
+	if res.Cached || res.Rescanned != 1 {
+		t.Errorf("provenance change should rescan, cached=%v rescanned=%d", res.Cached, res.Rescanned)
+	}
+
+	cache.Invalidate("n1")
+	if cache.Len() != 0 {
+		t.Errorf("Len = %d after Invalidate", cache.Len())
+	}
+	if res := cache.Scan("n1", 1, paraA, nil); res.Cached {
+		t.Error("invalidated note served from cache")
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 {
+		t.Errorf("Len = %d after Clear", cache.Len())
+	}
+}
+
+func TestDialogueEdgesSpanQuotes(t *testing.T) {
+	cache := New(newScanner(t))
+	text := paraC + "\n\n" + `"Run," [CHARACTER:Frodo] said to [CHARACTER:Sam].`
+
+	res := cache.Scan("n1", 1, text, nil)
+	var speaks []*graph.ConceptEdge
+	for _, node := range res.Graph.Nodes {
+		for _, e := range node.Outbound {
+			if e.Relation == "SPEAKS_TO" {
+				speaks = append(speaks, e)
+			}
+		}
+	}
+	if len(speaks) != 1 {
+		t.Fatalf("SPEAKS_TO edges = %d, want 1", len(speaks))
+	}
+	e := speaks[0]
+	if e.Source.ID != "Frodo" || e.Target.ID != "Sam" {
+		t.Errorf("edge = %s -> %s", e.Source.ID, e.Target.ID)
+	}
+	if got := text[e.SourceSpan[0]:e.SourceSpan[1]]; got != `"Run,"` {
+		t.Errorf("edge span = %q, want the quote in note coordinates", got)
+	}
+	if len(res.Scan.Dialogue) != 1 || res.Scan.Dialogue[0].Content.Slice(text) != "Run," {
+		t.Errorf("assembled dialogue = %+v", res.Scan.Dialogue)
+	}
+}
+
+func TestScanWithNarrativeScopesMatches(t *testing.T) {
+	scanner := newScanner(t)
+	dict, err := implicitmatcher.Compile([]implicitmatcher.RegisteredEntity{
+		{ID: "ash-kanto", Label: "Ash", Kind: "CHARACTER", NarrativeID: "kanto"},
+		{ID: "ash-ember", Label: "Ash", Kind: "CHARACTER", NarrativeID: "ember"},
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	scanner.SetDictionary(dict)
+	cache := New(scanner)
+	text := "Ash defeated the [MONSTER:Balrog]."
+
+	for _, narrative := range []string{"kanto", "ember"} {
+		res := cache.ScanWith("n1", 1, text, nil, Options{NarrativeID: narrative})
+		if res.Cached {
+			t.Errorf("%s: a scan in another narrative was served from cache", narrative)
+		}
+		node := res.Graph.GetNode("ash-" + narrative)
+		if node == nil || node.Label != "Ash" {
+			t.Errorf("%s: Ash node = %+v, edges %v", narrative, node, edgeSet(res.Graph))
+		}
+	}
+}
+
+func TestNotesDoNotShareContext(t *testing.T) {
+	const heLine = "He defeated the [MONSTER:Balrog]."
+	resolvesHe := func(res *Result) bool {
+		for _, ref := range res.Scan.ResolvedRefs {
+			if ref.Text == "He" && ref.EntityID == "Gandalf" {
+				return true
+			}
+		}
+		return false
+	}
+
+	cache := New(newScanner(t))
+	if !resolvesHe(cache.Scan("n1", 1, paraA+"\n\n"+heLine, nil)) {
+		t.Error("a pronoun should refer back to an earlier paragraph of its note")
+	}
+	alone := New(newScanner(t)).Scan("n2", 1, heLine, nil)
+	after := cache.Scan("n2", 1, heLine, nil)
+	if resolvesHe(after) {
+		t.Error("pronoun history leaked from the previous note")
+	}
+	if !reflect.DeepEqual(alone.Scan.ResolvedRefs, after.Scan.ResolvedRefs) {
+		t.Errorf("results depend on scan order: %+v vs %+v", alone.Scan.ResolvedRefs, after.Scan.ResolvedRefs)
+	}
+}
+
+func TestPartialRescanMatchesFullScan(t *testing.T) {
+	const heLine = "He defeated the [MONSTER:Balrog]."
+	cache := New(newScanner(t))
+	cache.Scan("n1", 1, paraA+"\n\n"+paraC, nil)
+
+	steps := []struct {
+		text      string
+		rescanned int
+	}{
+		// Gandalf's paragraph is reused; "He" still needs its mention
+		{paraA + "\n\n" + heLine, 1},
+		// Frodo now comes between them, so "He" is rescanned too
+		{paraA + "\n\n[CHARACTER:Frodo] left the Shire.\n\n" + heLine, 2},
+	}
+	for i, step := range steps {
+		res := cache.Scan("n1", int64(i+2), step.text, nil)
+		full := New(newScanner(t)).Scan("n1", 1, step.text, nil)
+		if res.Rescanned != step.rescanned {
+			t.Errorf("step %d: rescanned %d paragraphs, want %d", i, res.Rescanned, step.rescanned)
+		}
+		if !reflect.DeepEqual(res.Scan.ResolvedRefs, full.Scan.ResolvedRefs) {
+			t.Errorf("step %d: references %+v, full scan %+v", i, res.Scan.ResolvedRefs, full.Scan.ResolvedRefs)
+		}
+		if !reflect.DeepEqual(edgeSet(res.Graph), edgeSet(full.Graph)) {
+			t.Errorf("step %d: edges %v, full scan %v", i, edgeSet(res.Graph), edgeSet(full.Graph))
+		}
+	}
+}
+
+// resolvesHe returns the entity "He" resolves to in a result, or ""
+func resolvesHe(r *Result) string {
+	for _, ref := range r.Scan.ResolvedRefs {
+		if ref.Text == "He" {
+			return ref.EntityID
+		}
+	}
+	return ""
+}
+
+func TestCarriedChaptersFollowEdits(t *testing.T) {
+	scanner := newScanner(t)
+	cache := New(scanner)
+	carry := Options{NarrativeID: "lotr", Context: conductor.ContextCarry}
+	chapter2 := "He defeated the balrog."
+
+	cache.ScanWith("ch1", 1, "[CHARACTER:Gandalf] traveled to the Mountain.", nil, carry)
+	if got := resolvesHe(cache.ScanWith("ch2", 1, chapter2, nil, carry)); got != "Gandalf" {
+		t.Fatalf("chapter 2 He = %q, want Gandalf", got)
+	}
+
+	// Chapter 2 is unchanged, but the chapter before it now ends elsewhere
+	scanner.Session("lotr").Reset()
+	cache.ScanWith("ch1", 2, "[CHARACTER:Frodo] traveled to the Mountain.", nil, carry)
+	second := cache.ScanWith("ch2", 1, chapter2, nil, carry)
+	if second.Cached {
+		t.Error("chapter 2 was served from a different starting context")
+	}
+	if got := resolvesHe(second); got != "Frodo" {
+		t.Errorf("chapter 2 He after the edit = %q, want Frodo", got)
+	}
+}
+
+func TestCarriedHitAdvancesTheSession(t *testing.T) {
+	scanner := newScanner(t)
+	cache := New(scanner)
+	carry := Options{NarrativeID: "lotr", Context: conductor.ContextCarry}
+	chapter1 := "[CHARACTER:Gandalf] traveled to the Mountain."
+
+	// The second scan starts from a context that already knows Gandalf,
+	// and so does the third: it is a hit
+	session := scanner.Session("lotr")
+	cache.ScanWith("ch1", 1, chapter1, nil, carry)
+	session.Reset()
+	cache.ScanWith("ch1", 1, chapter1, nil, carry)
+	session.Reset()
+	if hit := cache.ScanWith("ch1", 1, chapter1, nil, carry); !hit.Cached {
+		t.Fatal("same version from the same context should be a hit")
+	}
+
+	// The hit still leaves the session at the end of chapter 1
+	if got := resolvesHe(cache.ScanWith("ch2", 1, "He defeated the balrog.", nil, carry)); got != "Gandalf" {
+		t.Errorf("chapter 2 He after a cached chapter 1 = %q, want Gandalf", got)
+	}
+}
+
+func TestCarriedPartialRescanMatchesFullScan(t *testing.T) {
+	const heLine = "He defeated the [MONSTER:Balrog]."
+	carry := Options{NarrativeID: "lotr", Context: conductor.ContextCarry}
+
+	// Every entity is known up front, so each scan after a Reset starts
+	// from the same context
+	warm := func() *conductor.Conductor {
+		scanner := newScanner(t)
+		session := scanner.Session("lotr")
+		session.Scan("[CHARACTER:Gandalf] slept. [CHARACTER:Frodo] slept. The [MONSTER:Balrog] slept at [LOCATION:Mountain].")
+		session.Reset()
+		return scanner
+	}
+
+	scanner := warm()
+	cache := New(scanner)
+	cache.ScanWith("n1", 1, paraA+"\n\n"+heLine, nil, carry)
+
+	// The new paragraph leaves the context as it was, so "He" is reused
+	text := paraA + "\n\n" + paraC + "\n\n" + heLine
+	scanner.Session("lotr").Reset()
+	res := cache.ScanWith("n1", 2, text, nil, carry)
+	if res.Rescanned != 1 {
+		t.Errorf("rescanned %d paragraphs, want 1", res.Rescanned)
+	}
+
+	fresh := warm()
+	full := New(fresh).ScanWith("n1", 1, text, nil, carry)
+	if !reflect.DeepEqual(res.Scan.ResolvedRefs, full.Scan.ResolvedRefs) {
+		t.Errorf("references %+v, full scan %+v", res.Scan.ResolvedRefs, full.Scan.ResolvedRefs)
+	}
+
+	// Both sessions carry on from the end of the note
+	next := "It returned." // The Balrog, named last
+	a, b := scanner.Session("lotr").Scan(next), fresh.Session("lotr").Scan(next)
+	if !reflect.DeepEqual(a.ResolvedRefs, b.ResolvedRefs) || len(a.ResolvedRefs) == 0 {
+		t.Errorf("session after partial rescan %+v, after full scan %+v", a.ResolvedRefs, b.ResolvedRefs)
+	}
+}
@@ -28,6 +28,7 @@ const (
 	KindEntitySpan   SyntaxKind = 30
 	KindConceptSpan  SyntaxKind = 31
 	KindRelationSpan SyntaxKind = 32
+	KindQuoteSpan    SyntaxKind = 33 // Quoted speech
 
 	// Links
 	KindWikilink SyntaxKind = 40
@@ -37,6 +38,21 @@ const (
 	// Clauses
 	KindMainClause SyntaxKind = 50
 	KindSubClause  SyntaxKind = 51
+
+	// Markdown Blocks
+	KindHeading     SyntaxKind = 60
+	KindList        SyntaxKind = 61
+	KindListItem    SyntaxKind = 62
+	KindBlockQuote  SyntaxKind = 63
+	KindCodeBlock   SyntaxKind = 64
+	KindTable       SyntaxKind = 65
+	KindTableRow    SyntaxKind = 66
+	KindTableCell   SyntaxKind = 67
+	KindFrontmatter SyntaxKind = 68
+
+	// User-defined inline spans: KindCustomSpan + n for the scanner's
+	// KindCustom + n (see syntax.InlinePattern in the scanner)
+	KindCustomSpan SyntaxKind = 1000
 )
 
 func (k SyntaxKind) String() string {
@@ -69,7 +85,30 @@ func (k SyntaxKind) String() string {
 		return "PrepPhrase"
 	case KindEntitySpan:
 		return "EntitySpan"
+	case KindQuoteSpan:
+		return "QuoteSpan"
+	case KindHeading:
+		return "Heading"
+	case KindList:
+		return "List"
+	case KindListItem:
+		return "ListItem"
+	case KindBlockQuote:
+		return "BlockQuote"
+	case KindCodeBlock:
+		return "CodeBlock"
+	case KindTable:
+		return "Table"
+	case KindTableRow:
+		return "TableRow"
+	case KindTableCell:
+		return "TableCell"
+	case KindFrontmatter:
+		return "Frontmatter"
 	default:
+		if k >= KindCustomSpan {
+			return "CustomSpan"
+		}
 		return "Unknown"
 	}
 }
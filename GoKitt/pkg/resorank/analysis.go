@@ -0,0 +1,79 @@
+package resorank
+
+import "github.com/kittclouds/gokitt/pkg/stem"
+
+// Term converts a word to its index term for a field
+func (s *Scorer) Term(field, word string) string {
+	return s.analyzer(field).Term(word)
+}
+
+// QueryTerms converts query words to index terms. A word yields one term per
+// distinct analysis in use, so "Baggins" matches stemmed prose ("baggin") as
+// well as an unstemmed name field ("baggins"). Duplicates are dropped.
+func (s *Scorer) QueryTerms(words []string) []string {
+	terms := make([]string, 0, len(words))
+	for _, group := range s.queryGroups(words) {
+		terms = append(terms, group...)
+	}
+	return terms
+}
+
+// SearchWords analyzes query words like QueryTerms and searches with them,
+// scoring each word once by its best analysis in a document rather than
+// summing every analysis that happens to match
+func (s *Scorer) SearchWords(words []string, queryVector []float32, limit int, scope *SearchScope) []SearchResult {
+	return s.search(s.queryGroups(words), queryVector, limit, scope)
+}
+
+// queryGroups returns each word's distinct index terms, one group per word.
+// Terms already produced by an earlier word are dropped; empty groups too.
+func (s *Scorer) queryGroups(words []string) [][]string {
+	analyzers := []stem.Analyzer{stem.Stemmed}
+	for _, a := range s.Config.FieldAnalyzers {
+		if a != stem.Stemmed && !containsAnalyzer(analyzers, a) {
+			analyzers = append(analyzers, a)
+		}
+	}
+
+	seen := make(map[string]bool, len(words))
+	groups := make([][]string, 0, len(words))
+	for _, w := range words {
+		var group []string
+		for _, a := range analyzers {
+			term := a.Term(w)
+			if term != "" && !seen[term] {
+				seen[term] = true
+				group = append(group, term)
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// singleTerms groups already-analyzed terms one per group
+func singleTerms(terms []string) [][]string {
+	groups := make([][]string, len(terms))
+	for i, term := range terms {
+		groups[i] = []string{term}
+	}
+	return groups
+}
+
+func (s *Scorer) analyzer(field string) stem.Analyzer {
+	if a, ok := s.Config.FieldAnalyzers[field]; ok {
+		return a
+	}
+	return stem.Stemmed
+}
+
+func containsAnalyzer(list []stem.Analyzer, a stem.Analyzer) bool {
+	for _, x := range list {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
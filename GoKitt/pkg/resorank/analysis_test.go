@@ -0,0 +1,98 @@
+package resorank
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQueryTermsMatchAnalyzedFields(t *testing.T) {
+	s := NewScorer(DefaultConfig())
+
+	// "Frodo Baggins fought the orcs": the name is an entity, the rest prose
+	tokens := make(map[string]TokenMetadata)
+	add := func(field, word string) {
+		term := s.Term(field, word)
+		meta := tokens[term]
+		if meta.FieldOccurrences == nil {
+			meta.FieldOccurrences = make(map[string]FieldOccurrence)
+		}
+		occ := meta.FieldOccurrences[field]
+		occ.TF++
+		occ.FieldLength = 5
+		meta.FieldOccurrences[field] = occ
+		meta.SegmentMask = 1
+		tokens[term] = meta
+	}
+	add(EntityField, "Frodo")
+	add(EntityField, "Baggins")
+	add("content", "fought")
+	add("content", "the")
+	add("content", "orcs")
+	s.IndexDocument("doc1", DocumentMetadata{
+		TotalTokenCount: 5,
+		FieldLengths:    map[string]int{EntityField: 2, "content": 3},
+	}, tokens)
+
+	if _, ok := tokens["baggins"]; !ok {
+		t.Errorf("entity name was stemmed: %v", tokens)
+	}
+	if _, ok := tokens["fight"]; !ok {
+		t.Errorf("prose was not lemmatized: %v", tokens)
+	}
+
+	for _, query := range [][]string{{"fighting"}, {"Baggins"}, {"orc"}} {
+		terms := s.QueryTerms(query)
+		if len(s.Search(terms, nil, 10)) != 1 {
+			t.Errorf("query %v (terms %v) found nothing", query, terms)
+		}
+	}
+
+	got := s.QueryTerms([]string{"Baggins", "fights", "baggins"})
+	want := []string{"baggin", "baggins", "fight", "fights"}
+	if len(got) != len(want) {
+		t.Fatalf("QueryTerms = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("QueryTerms = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestSearchWordsScoresEachWordOnce(t *testing.T) {
+	s := NewScorer(DefaultConfig())
+
+	// doc1 has "Baggins" both as an entity and in the prose; doc2 only as an entity
+	tokens := func(fields map[string]string) map[string]TokenMetadata {
+		out := make(map[string]TokenMetadata)
+		for field, word := range fields {
+			out[s.Term(field, word)] = TokenMetadata{
+				FieldOccurrences: map[string]FieldOccurrence{field: {TF: 1, FieldLength: 1}},
+				SegmentMask:      1,
+			}
+		}
+		return out
+	}
+	meta := DocumentMetadata{
+		TotalTokenCount: 2,
+		FieldLengths:    map[string]int{EntityField: 1, "content": 1},
+	}
+	s.IndexDocument("doc1", meta, tokens(map[string]string{EntityField: "Baggins", "content": "Baggins"}))
+	s.IndexDocument("doc2", meta, tokens(map[string]string{EntityField: "Baggins", "content": "road"}))
+
+	results := s.SearchWords([]string{"Baggins"}, nil, 10, nil)
+	if len(results) != 2 || results[0].DocID != "doc1" {
+		t.Fatalf("SearchWords = %v, want doc1 then doc2", results)
+	}
+
+	// doc1 counts the word once, through its better-scoring form
+	stemmed := s.Score([]string{"baggin"}, nil, "doc1")
+	name := s.Score([]string{"baggins"}, nil, "doc1")
+	if stemmed == 0 || name == 0 {
+		t.Fatalf("doc1 should match both forms: baggin %v, baggins %v", stemmed, name)
+	}
+	if want := math.Max(stemmed, name); math.Abs(results[0].Score-want) > 1e-9 {
+		t.Errorf("doc1 scored %v, want the better form's %v", results[0].Score, want)
+	}
+}
@@ -41,6 +41,20 @@ func (c *EntropyCache) Has(term string) bool {
 	return ok
 }
 
+// Invalidate drops a term whose postings changed
+func (c *EntropyCache) Invalidate(term string) {
+	if _, ok := c.cache[term]; !ok {
+		return
+	}
+	delete(c.cache, term)
+	for i, t := range c.accessOrder {
+		if t == term {
+			c.accessOrder = append(c.accessOrder[:i], c.accessOrder[i+1:]...)
+			break
+		}
+	}
+}
+
 // Clear wipes the cache
 func (c *EntropyCache) Clear() {
 	c.cache = make(map[string]float64, c.maxSize)
@@ -14,6 +14,7 @@ import (
 type FSTIndex struct {
 	Index    *vellum.IndexReader
 	Postings []byte
+	FST      []byte // Raw FST bytes backing Index (kept for snapshots)
 }
 
 // BuildFSTIndex converts the map-based index to FSTIndex
@@ -65,6 +66,20 @@ func BuildFSTIndex(tokenIndex map[string]map[string]TokenMetadata) (*FSTIndex, e
 	return &FSTIndex{
 		Index:    idxReader,
 		Postings: postingsBuf.Bytes(),
+		FST:      fstBytes,
+	}, nil
+}
+
+// OpenFSTIndex rebuilds an FSTIndex from previously built FST and postings bytes
+func OpenFSTIndex(fstBytes, postings []byte) (*FSTIndex, error) {
+	idxReader, err := vellum.OpenIndex(fstBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &FSTIndex{
+		Index:    idxReader,
+		Postings: postings,
+		FST:      fstBytes,
 	}, nil
 }
 
@@ -86,6 +101,36 @@ func (fi *FSTIndex) Get(term string) (map[string]TokenMetadata, bool) {
 	return docs, true
 }
 
+// Each calls fn for every term in the index with its decoded postings
+func (fi *FSTIndex) Each(fn func(term string, docs map[string]TokenMetadata)) error {
+	terms, offsets, err := fi.Index.Search(&vellum.AlwaysMatch{})
+	if err != nil {
+		return err
+	}
+
+	for i, term := range terms {
+		docs, err := decodePostings(bytes.NewReader(fi.Postings[offsets[i]:]))
+		if err != nil {
+			return fmt.Errorf("failed to decode postings for term %s: %w", term, err)
+		}
+		fn(term, docs)
+	}
+	return nil
+}
+
+// FuzzyTerms returns the indexed terms within distance edits of term
+func (fi *FSTIndex) FuzzyTerms(term string, distance uint8) ([]string, error) {
+	matches, err := fi.Index.SearchFuzzy(term, distance)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]string, len(matches))
+	for i, m := range matches {
+		terms[i] = m.Key
+	}
+	return terms, nil
+}
+
 // Close releases resources
 func (fi *FSTIndex) Close() error {
 	return fi.Index.Close()
@@ -0,0 +1,74 @@
+package resorank
+
+// DefaultVectorCandidates is how many neighbours hybrid mode asks a
+// VectorSource for when Scorer.VectorCandidates is unset.
+const DefaultVectorCandidates = 100
+
+// VectorCandidate is one nearest-neighbour hit from an external vector index.
+type VectorCandidate struct {
+	DocID      string
+	Similarity float64 // Cosine similarity
+}
+
+// VectorSource supplies vector candidates for hybrid search, e.g. a sqlite-vec
+// KNN query. Implementations should honour scope.NarrativeID when they can;
+// the scorer still applies the full scope filter to whatever comes back.
+type VectorSource interface {
+	NearestDocuments(queryVector []float32, k int, scope *SearchScope) ([]VectorCandidate, error)
+}
+
+// VectorSourceFunc adapts a plain function to VectorSource.
+type VectorSourceFunc func(queryVector []float32, k int, scope *SearchScope) ([]VectorCandidate, error)
+
+// NearestDocuments calls f.
+func (f VectorSourceFunc) NearestDocuments(queryVector []float32, k int, scope *SearchScope) ([]VectorCandidate, error) {
+	return f(queryVector, k, scope)
+}
+
+// vectorCandidates adds vector candidates for a query to candidates.
+// In hybrid mode it returns the source's similarities by docID; otherwise (or
+// if the source fails) it falls back to every indexed document and returns nil,
+// meaning similarities come from DocumentMetadata.Embedding.
+func (s *Scorer) vectorCandidates(queryVector []float32, scope *SearchScope, candidates map[string]bool) map[string]float64 {
+	if s.VectorSource != nil {
+		k := s.VectorCandidates
+		if k <= 0 {
+			k = DefaultVectorCandidates
+		}
+		hits, err := s.VectorSource.NearestDocuments(queryVector, k, scope)
+		if err == nil {
+			similarities := make(map[string]float64, len(hits))
+			for _, hit := range hits {
+				if _, ok := s.DocumentIndex[hit.DocID]; !ok {
+					continue // Embedded but not indexed (or removed since)
+				}
+				similarities[hit.DocID] = hit.Similarity
+				candidates[hit.DocID] = true
+			}
+			return similarities
+		}
+	}
+
+	// Brute force
+	for docID := range s.DocumentIndex {
+		candidates[docID] = true
+	}
+	return nil
+}
+
+// vectorScore returns the clamped cosine similarity between the query and a
+// document, preferring a hybrid-mode hit over the in-memory embedding.
+func vectorScore(queryVector []float32, meta DocumentMetadata, hit float64, isHit bool) float64 {
+	score := 0.0
+	switch {
+	case len(queryVector) == 0:
+	case isHit:
+		score = hit
+	case len(meta.Embedding) > 0:
+		score = CosineSimilarity(queryVector, meta.Embedding)
+	}
+	if score < 0 {
+		return 0
+	}
+	return score
+}
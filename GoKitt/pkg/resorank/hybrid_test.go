@@ -0,0 +1,70 @@
+package resorank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHybridUsesVectorSourceCandidates(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VectorAlpha = 0.5
+	scorer := NewScorer(cfg)
+	scorer.IndexDocument("near", bodyDoc(10), bodyTokens(10, "castle"))
+	scorer.IndexDocument("far", bodyDoc(10), bodyTokens(10, "castle"))
+	scorer.IndexDocument("vectorOnly", bodyDoc(10), bodyTokens(10, "moat"))
+	scorer.IndexDocument("ignored", bodyDoc(10), bodyTokens(10, "moat"))
+
+	var gotK int
+	var gotScope *SearchScope
+	scorer.VectorCandidates = 2
+	scorer.VectorSource = VectorSourceFunc(func(q []float32, k int, scope *SearchScope) ([]VectorCandidate, error) {
+		gotK, gotScope = k, scope
+		return []VectorCandidate{
+			{DocID: "near", Similarity: 0.9},
+			{DocID: "vectorOnly", Similarity: 0.8},
+			{DocID: "removed", Similarity: 0.99},
+		}, nil
+	})
+
+	scope := &SearchScope{NarrativeID: ""}
+	results := scorer.SearchScoped([]string{"castle"}, []float32{1, 0}, 10, scope)
+
+	if gotK != 2 || gotScope != scope {
+		t.Errorf("source called with k=%d scope=%v", gotK, gotScope)
+	}
+
+	ids := make(map[string]float64)
+	for _, r := range results {
+		ids[r.DocID] = r.Score
+	}
+	if _, ok := ids["ignored"]; ok {
+		t.Error("document outside both text and vector candidates was scored")
+	}
+	if _, ok := ids["removed"]; ok {
+		t.Error("unindexed vector hit was returned")
+	}
+	if _, ok := ids["vectorOnly"]; !ok {
+		t.Error("vector-only candidate missing")
+	}
+	if ids["near"] <= ids["far"] {
+		t.Errorf("vector hit should outrank text-only match: near=%f far=%f", ids["near"], ids["far"])
+	}
+}
+
+func TestHybridFallsBackToBruteForce(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VectorAlpha = 0.5
+	scorer := NewScorer(cfg)
+	meta := bodyDoc(10)
+	meta.Embedding = []float32{1, 0}
+	scorer.IndexDocument("doc1", meta, bodyTokens(10, "moat"))
+
+	scorer.VectorSource = VectorSourceFunc(func([]float32, int, *SearchScope) ([]VectorCandidate, error) {
+		return nil, errors.New("index offline")
+	})
+
+	results := scorer.Search(nil, []float32{1, 0}, 10)
+	if len(results) != 1 || results[0].DocID != "doc1" {
+		t.Fatalf("expected brute-force hit on doc1, got %v", results)
+	}
+}
@@ -14,6 +14,16 @@ type Scorer struct {
 	TokenIndex    map[string]map[string]TokenMetadata `json:"tokenIndex"` // term -> docID -> meta (mutable overlay)
 	FrozenIndex   *FSTIndex                           `json:"-"`          // Immutable FST-backed base layer
 
+	// Bookkeeping for removal and re-indexing
+	DocFrequencies map[string]int      `json:"docFrequencies"` // term -> number of live docs containing it
+	DocTerms       map[string][]string `json:"docTerms"`       // docID -> indexed terms (forward index)
+	Tombstones     map[string]bool     `json:"tombstones"`     // docIDs whose FrozenIndex postings are dead until Compact
+
+	// Hybrid mode: vector candidates come from an external index (e.g. sqlite-vec)
+	// instead of a brute-force scan of DocumentIndex. Not persisted in snapshots.
+	VectorSource     VectorSource `json:"-"`
+	VectorCandidates int          `json:"-"` // k per query; 0 means DefaultVectorCandidates
+
 	// Caches
 	IDFCache     map[int]float64
 	EntropyCache *EntropyCache
@@ -27,22 +37,29 @@ type Scorer struct {
 // NewScorer creates a new scorer
 func NewScorer(config ResoRankConfig) *Scorer {
 	s := &Scorer{
-		Config:        config,
-		CorpusStats:   CorpusStatistics{AverageFieldLengths: make(map[string]float64)},
-		DocumentIndex: make(map[string]DocumentMetadata),
-		TokenIndex:    make(map[string]map[string]TokenMetadata),
-		IDFCache:      make(map[int]float64),
-		EntropyCache:  NewEntropyCache(1000),
+		Config:         config,
+		CorpusStats:    CorpusStatistics{AverageFieldLengths: make(map[string]float64)},
+		DocumentIndex:  make(map[string]DocumentMetadata),
+		TokenIndex:     make(map[string]map[string]TokenMetadata),
+		DocFrequencies: make(map[string]int),
+		DocTerms:       make(map[string][]string),
+		Tombstones:     make(map[string]bool),
+		IDFCache:       make(map[int]float64),
+		EntropyCache:   NewEntropyCache(1000),
 	}
 	return s
 }
 
-// IndexDocument adds a document
+// IndexDocument adds a document, replacing any previously indexed version
 func (s *Scorer) IndexDocument(docID string, meta DocumentMetadata, tokens map[string]TokenMetadata) {
+	// Re-indexing drops the old postings and stats first
+	s.RemoveDocument(docID)
+
 	// Add Doc
 	s.DocumentIndex[docID] = meta
 
 	// Add Tokens
+	terms := make([]string, 0, len(tokens))
 	for term, tMeta := range tokens {
 		if s.TokenIndex[term] == nil {
 			s.TokenIndex[term] = make(map[string]TokenMetadata)
@@ -55,10 +72,104 @@ func (s *Scorer) IndexDocument(docID string, meta DocumentMetadata, tokens map[s
 		}
 
 		s.TokenIndex[term][docID] = tMeta
+		s.DocFrequencies[term]++
+		s.EntropyCache.Invalidate(term)
+		terms = append(terms, term)
+	}
+	s.DocTerms[docID] = terms
+
+	// Update corpus stats
+	s.addDocStats(meta)
+}
+
+// RemoveDocument deletes a document and all of its postings.
+// Postings already frozen into the FrozenIndex are tombstoned and
+// physically dropped on the next Compact. Returns false if the
+// document was not indexed.
+func (s *Scorer) RemoveDocument(docID string) bool {
+	meta, ok := s.DocumentIndex[docID]
+	if !ok {
+		return false
+	}
+
+	for _, term := range s.DocTerms[docID] {
+		if docs, ok := s.TokenIndex[term]; ok {
+			delete(docs, docID)
+			if len(docs) == 0 {
+				delete(s.TokenIndex, term)
+			}
+		}
+		if s.DocFrequencies[term] <= 1 {
+			delete(s.DocFrequencies, term)
+		} else {
+			s.DocFrequencies[term]--
+		}
+		s.EntropyCache.Invalidate(term)
+	}
+
+	delete(s.DocTerms, docID)
+	delete(s.DocumentIndex, docID)
+	if s.FrozenIndex != nil {
+		s.Tombstones[docID] = true
+	}
+
+	s.removeDocStats(meta)
+	return true
+}
+
+// addDocStats folds a new document into the running corpus averages.
+// Averages are updated incrementally so manually seeded stats survive.
+func (s *Scorer) addDocStats(meta DocumentMetadata) {
+	n := float64(s.CorpusStats.TotalDocuments)
+	s.CorpusStats.AverageDocLength = (s.CorpusStats.AverageDocLength*n + float64(meta.TotalTokenCount)) / (n + 1)
+
+	for field := range meta.FieldLengths {
+		if _, ok := s.CorpusStats.AverageFieldLengths[field]; !ok {
+			s.CorpusStats.AverageFieldLengths[field] = 0
+		}
+	}
+	for field, avg := range s.CorpusStats.AverageFieldLengths {
+		s.CorpusStats.AverageFieldLengths[field] = (avg*n + float64(meta.FieldLengths[field])) / (n + 1)
 	}
 
-	// Update basic stats
 	s.CorpusStats.TotalDocuments++
+	clear(s.IDFCache)
+}
+
+// removeDocStats reverses addDocStats for a removed document
+func (s *Scorer) removeDocStats(meta DocumentMetadata) {
+	n := float64(s.CorpusStats.TotalDocuments)
+	if n <= 1 {
+		s.CorpusStats.TotalDocuments = 0
+		s.CorpusStats.AverageDocLength = 0
+		s.CorpusStats.AverageFieldLengths = make(map[string]float64)
+		clear(s.IDFCache)
+		return
+	}
+
+	s.CorpusStats.AverageDocLength = nonNegative((s.CorpusStats.AverageDocLength*n - float64(meta.TotalTokenCount)) / (n - 1))
+	for field, avg := range s.CorpusStats.AverageFieldLengths {
+		s.CorpusStats.AverageFieldLengths[field] = nonNegative((avg*n - float64(meta.FieldLengths[field])) / (n - 1))
+	}
+
+	s.CorpusStats.TotalDocuments--
+	clear(s.IDFCache)
+}
+
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// docFrequency returns the live document frequency of a term, falling back
+// to the caller-supplied CorpusDocFreq for terms the scorer hasn't counted.
+func (s *Scorer) docFrequency(term string, meta TokenMetadata) int {
+	if df, ok := s.DocFrequencies[term]; ok {
+		return df
+	}
+	return meta.CorpusDocFreq
 }
 
 // Search executes a query (Hybrid)
@@ -68,20 +179,27 @@ func (s *Scorer) Search(query []string, queryVector []float32, limit int) []Sear
 
 // SearchScoped executes a query with optional scope filtering
 func (s *Scorer) SearchScoped(query []string, queryVector []float32, limit int, scope *SearchScope) []SearchResult {
+	return s.search(singleTerms(query), queryVector, limit, scope)
+}
+
+// search scores candidates for query groups; each group is one query word's
+// alternative terms (see SearchWords)
+func (s *Scorer) search(groups [][]string, queryVector []float32, limit int, scope *SearchScope) []SearchResult {
 	candidates := make(map[string]bool)
 
 	// 1. Text-based Candidates (from both frozen and mutable indexes)
-	for _, term := range query {
-		for docID := range s.getTermPostings(term) {
-			candidates[docID] = true
+	for _, group := range groups {
+		for _, term := range group {
+			for docID := range s.getTermPostings(term) {
+				candidates[docID] = true
+			}
 		}
 	}
 
-	// 2. Vector Candidates (Brute force for now)
+	// 2. Vector Candidates (VectorSource in hybrid mode, brute force otherwise)
+	var vectorHits map[string]float64
 	if len(queryVector) > 0 {
-		for docID := range s.DocumentIndex {
-			candidates[docID] = true
-		}
+		vectorHits = s.vectorCandidates(queryVector, scope, candidates)
 	}
 
 	var results []SearchResult
@@ -91,7 +209,9 @@ func (s *Scorer) SearchScoped(query []string, queryVector []float32, limit int,
 			continue
 		}
 
-		score := s.Score(query, queryVector, docID)
+		meta := s.DocumentIndex[docID]
+		hit, isHit := vectorHits[docID]
+		score := s.scoreDocument(groups, docID, meta, vectorScore(queryVector, meta, hit, isHit))
 		if score > 0 {
 			results = append(results, SearchResult{DocID: docID, Score: score})
 		}
@@ -148,7 +268,12 @@ func (s *Scorer) Score(query []string, queryVector []float32, docID string) floa
 	if !ok {
 		return 0.0
 	}
+	return s.scoreDocument(singleTerms(query), docID, docMeta, vectorScore(queryVector, docMeta, 0, false))
+}
 
+// scoreDocument mixes the BM25/BMX text score with a precomputed vector score.
+// A query group counts once, through whichever of its terms scores highest.
+func (s *Scorer) scoreDocument(groups [][]string, docID string, docMeta DocumentMetadata, vectorScore float64) float64 {
 	// 0. Pre-calc BMX parameters
 	alpha := s.Config.K1
 	if s.Config.UseAdaptiveAlpha {
@@ -159,7 +284,18 @@ func (s *Scorer) Score(query []string, queryVector []float32, docID string) floa
 	var entropyStats QueryEntropyStats
 	hasEntropy := s.Config.EnableBMXEntropy || s.Config.EnableBMXSimilarity
 	if hasEntropy {
-		entropyStats = CalculateQueryEntropyStats(query, s.EntropyCache, s.TokenIndex)
+		// Entropy covers every alternative term of every group
+		var query []string
+		for _, group := range groups {
+			query = append(query, group...)
+		}
+
+		// Entropy needs live postings from both layers, minus tombstones
+		queryIndex := make(map[string]map[string]TokenMetadata, len(query))
+		for _, term := range query {
+			queryIndex[term] = s.getTermPostings(term)
+		}
+		entropyStats = CalculateQueryEntropyStats(query, s.EntropyCache, queryIndex)
 	}
 
 	gamma := 0.0
@@ -175,18 +311,29 @@ func (s *Scorer) Score(query []string, queryVector []float32, docID string) floa
 	var termMasks []uint32
 	var termIDFs []float64
 	docTermMasks := make(map[string]uint32)
-
-	// 2. Score Terms
-	for _, term := range query {
-		postings := s.getTermPostings(term)
-		tMeta, ok := postings[docID]
-		if !ok {
+	var matched []string
+
+	// 2. Score Terms (best alternative per group)
+	for _, group := range groups {
+		term, found := "", false
+		var tMeta TokenMetadata
+		var idf, termScore float64
+		for _, t := range group {
+			meta, ok := s.getTermPostings(t)[docID]
+			if !ok {
+				continue
+			}
+			tIDF := s.getIDF(s.docFrequency(t, meta))
+			score := s.scoreTermBMX(meta, tIDF, alpha, gamma, entropyStats.AvgEntropy)
+			if !found || score > termScore {
+				term, found = t, true
+				tMeta, idf, termScore = meta, tIDF, score
+			}
+		}
+		if !found {
 			continue
 		}
 
-		idf := s.getIDF(tMeta.CorpusDocFreq)
-		termScore := s.scoreTermBMX(tMeta, idf, alpha, gamma, entropyStats.AvgEntropy)
-
 		// Per-Term Proximity (applied immediately)
 		if s.Config.ProximityStrategy == "per-term" && termScore > 0 {
 			prox := PerTermProximityMultiplier(tMeta.SegmentMask, termMasks, s.Config.ProximityAlpha, s.Config.MaxSegments)
@@ -198,6 +345,7 @@ func (s *Scorer) Score(query []string, queryVector []float32, docID string) floa
 		termMasks = append(termMasks, tMeta.SegmentMask)
 		termIDFs = append(termIDFs, idf)
 		docTermMasks[term] = tMeta.SegmentMask
+		matched = append(matched, term)
 	}
 
 	// 3. Proximity Multipliers (Global / Pairwise / IdfWeighted)
@@ -228,37 +376,23 @@ func (s *Scorer) Score(query []string, queryVector []float32, docID string) floa
 	}
 
 	// 4. Phrase Boost
-	if s.Config.EnablePhraseBoost && len(query) > 1 && DetectPhraseMatch(query, docTermMasks) {
+	// Every group must match, in order, through its chosen term
+	if s.Config.EnablePhraseBoost && len(groups) > 1 && len(matched) == len(groups) && DetectPhraseMatch(matched, docTermMasks) {
 		totalScore *= s.Config.PhraseBoostMultiplier
 	}
 
 	// 5. Similarity Boost (BMX)
 	if s.Config.EnableBMXSimilarity && hasEntropy {
 		beta := CalculateBeta(s.CorpusStats.TotalDocuments)
-		commonCount := 0
-		for _, t := range query {
-			if _, ok := docTermMasks[t]; ok {
-				commonCount++
-			}
-		}
 		sim := 0.0
-		if len(query) > 0 {
-			sim = float64(commonCount) / float64(len(query))
+		if len(groups) > 0 {
+			sim = float64(len(matched)) / float64(len(groups))
 		}
 		boost := beta * sim * entropyStats.SumNormalizedEntropies
 		totalScore += boost
 	}
 
-	// 6. Vector Scoring
-	vectorScore := 0.0
-	if len(queryVector) > 0 && len(docMeta.Embedding) > 0 {
-		vectorScore = CosineSimilarity(queryVector, docMeta.Embedding)
-		if vectorScore < 0 {
-			vectorScore = 0
-		}
-	}
-
-	// 7. Hybrid Mix
+	// 6. Hybrid Mix
 	alphaVec := s.Config.VectorAlpha
 	finalScore := ((1.0 - alphaVec) * totalScore) + (alphaVec * vectorScore * 20.0)
 
@@ -325,10 +459,13 @@ func remapSegmentMask(mask uint32, fromSegs uint32, toSegs uint32) uint32 {
 func (s *Scorer) getTermPostings(term string) map[string]TokenMetadata {
 	result := make(map[string]TokenMetadata)
 
-	// First, load from frozen index if available
+	// First, load from frozen index if available, skipping tombstoned docs
 	if s.FrozenIndex != nil {
 		if frozen, ok := s.FrozenIndex.Get(term); ok {
 			for docID, meta := range frozen {
+				if s.Tombstones[docID] {
+					continue
+				}
 				result[docID] = meta
 			}
 		}
@@ -344,15 +481,15 @@ func (s *Scorer) getTermPostings(term string) map[string]TokenMetadata {
 	return result
 }
 
-// Compact freezes the current mutable TokenIndex into the FrozenIndex
+// Compact freezes the live postings (frozen minus tombstones, plus the
+// mutable TokenIndex) into a new FrozenIndex.
 // This significantly reduces memory usage for large indexes
 func (s *Scorer) Compact() error {
-	if len(s.TokenIndex) == 0 {
+	if len(s.TokenIndex) == 0 && len(s.Tombstones) == 0 {
 		return nil // Nothing to compact
 	}
 
-	// Build new FST index from mutable data
-	newFrozen, err := BuildFSTIndex(s.TokenIndex)
+	newFrozen, err := s.buildLiveIndex()
 	if err != nil {
 		return err
 	}
@@ -365,6 +502,54 @@ func (s *Scorer) Compact() error {
 	// Swap
 	s.FrozenIndex = newFrozen
 	s.TokenIndex = make(map[string]map[string]TokenMetadata) // Clear mutable
+	s.Tombstones = make(map[string]bool)
 
 	return nil
 }
+
+// buildLiveIndex merges both layers into a fresh FSTIndex without touching
+// the scorer. Returns nil if there are no live postings.
+func (s *Scorer) buildLiveIndex() (*FSTIndex, error) {
+	merged := make(map[string]map[string]TokenMetadata)
+	add := func(term, docID string, meta TokenMetadata) {
+		if merged[term] == nil {
+			merged[term] = make(map[string]TokenMetadata)
+		}
+		merged[term][docID] = meta
+	}
+
+	// Carry over live frozen postings
+	if s.FrozenIndex != nil {
+		err := s.FrozenIndex.Each(func(term string, docs map[string]TokenMetadata) {
+			for docID, meta := range docs {
+				if !s.Tombstones[docID] {
+					add(term, docID, meta)
+				}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Mutable overlay wins
+	for term, docs := range s.TokenIndex {
+		for docID, meta := range docs {
+			add(term, docID, meta)
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	// Bake current doc frequencies into the frozen postings
+	for _, docs := range merged {
+		for docID, meta := range docs {
+			meta.CorpusDocFreq = len(docs)
+			docs[docID] = meta
+		}
+	}
+
+	return BuildFSTIndex(merged)
+}
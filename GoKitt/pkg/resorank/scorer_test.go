@@ -235,3 +235,127 @@ func TestScopedSearch(t *testing.T) {
 
 	t.Log("TestScopedSearch: All scope filters working correctly!")
 }
+
+func bodyDoc(length int) DocumentMetadata {
+	return DocumentMetadata{
+		TotalTokenCount: length,
+		FieldLengths:    map[string]int{"body": length},
+	}
+}
+
+func bodyTokens(length int, terms ...string) map[string]TokenMetadata {
+	tokens := make(map[string]TokenMetadata, len(terms))
+	for _, term := range terms {
+		tokens[term] = TokenMetadata{
+			SegmentMask:      1,
+			FieldOccurrences: map[string]FieldOccurrence{"body": {TF: 1, FieldLength: length}},
+		}
+	}
+	return tokens
+}
+
+func TestReindexReplacesDocument(t *testing.T) {
+	scorer := NewScorer(DefaultConfig())
+	scorer.IndexDocument("doc1", bodyDoc(10), bodyTokens(10, "dragon", "castle"))
+	scorer.IndexDocument("doc2", bodyDoc(30), bodyTokens(30, "dragon"))
+
+	// Re-index doc1 without "castle"
+	scorer.IndexDocument("doc1", bodyDoc(20), bodyTokens(20, "dragon", "river"))
+
+	if scorer.CorpusStats.TotalDocuments != 2 {
+		t.Errorf("Expected 2 documents, got %d", scorer.CorpusStats.TotalDocuments)
+	}
+	if math.Abs(scorer.CorpusStats.AverageDocLength-25) > 1e-9 {
+		t.Errorf("Expected average length 25, got %f", scorer.CorpusStats.AverageDocLength)
+	}
+	if math.Abs(scorer.CorpusStats.AverageFieldLengths["body"]-25) > 1e-9 {
+		t.Errorf("Expected average body length 25, got %f", scorer.CorpusStats.AverageFieldLengths["body"])
+	}
+	if results := scorer.Search([]string{"castle"}, nil, 10); len(results) != 0 {
+		t.Errorf("Stale term 'castle' still matches: %+v", results)
+	}
+	if df := scorer.DocFrequencies["dragon"]; df != 2 {
+		t.Errorf("Expected df(dragon)=2, got %d", df)
+	}
+	if _, ok := scorer.DocFrequencies["castle"]; ok {
+		t.Error("df(castle) should be gone")
+	}
+}
+
+func TestRemoveDocument(t *testing.T) {
+	scorer := NewScorer(DefaultConfig())
+	scorer.IndexDocument("doc1", bodyDoc(10), bodyTokens(10, "dragon"))
+	scorer.IndexDocument("doc2", bodyDoc(30), bodyTokens(30, "dragon", "knight"))
+
+	if !scorer.RemoveDocument("doc2") {
+		t.Fatal("Expected doc2 to be removed")
+	}
+	if scorer.RemoveDocument("doc2") {
+		t.Error("Removing twice should report false")
+	}
+
+	if scorer.CorpusStats.TotalDocuments != 1 || scorer.CorpusStats.AverageDocLength != 10 {
+		t.Errorf("Unexpected stats after removal: %+v", scorer.CorpusStats)
+	}
+	if _, ok := scorer.TokenIndex["knight"]; ok {
+		t.Error("Postings for 'knight' should be dropped")
+	}
+	results := scorer.Search([]string{"dragon"}, nil, 10)
+	if len(results) != 1 || results[0].DocID != "doc1" {
+		t.Errorf("Expected only doc1, got %+v", results)
+	}
+
+	scorer.RemoveDocument("doc1")
+	if scorer.CorpusStats.TotalDocuments != 0 || len(scorer.DocFrequencies) != 0 {
+		t.Errorf("Expected empty corpus, got %+v / %v", scorer.CorpusStats, scorer.DocFrequencies)
+	}
+}
+
+func TestRemoveDocumentFromFrozenIndex(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.EnableBMXEntropy = true
+	scorer := NewScorer(cfg)
+	scorer.IndexDocument("doc1", bodyDoc(10), bodyTokens(10, "dragon", "castle"))
+	scorer.IndexDocument("doc2", bodyDoc(10), bodyTokens(10, "dragon"))
+	scorer.IndexDocument("doc3", bodyDoc(10), bodyTokens(10, "dragon"))
+
+	if err := scorer.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	before := scorer.Search([]string{"dragon"}, nil, 10)
+	if len(before) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(before))
+	}
+
+	// Tombstone doc1, replace doc2 so it no longer mentions dragon
+	scorer.RemoveDocument("doc1")
+	scorer.IndexDocument("doc2", bodyDoc(10), bodyTokens(10, "castle"))
+
+	check := func(stage string) {
+		results := scorer.Search([]string{"dragon"}, nil, 10)
+		if len(results) != 1 || results[0].DocID != "doc3" {
+			t.Errorf("%s: expected only doc3 for dragon, got %+v", stage, results)
+		}
+		results = scorer.Search([]string{"castle"}, nil, 10)
+		if len(results) != 1 || results[0].DocID != "doc2" {
+			t.Errorf("%s: expected only doc2 for castle, got %+v", stage, results)
+		}
+	}
+
+	check("before compact")
+	if len(scorer.Tombstones) != 2 {
+		t.Errorf("Expected 2 tombstones, got %d", len(scorer.Tombstones))
+	}
+
+	if err := scorer.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	check("after compact")
+	if len(scorer.Tombstones) != 0 {
+		t.Error("Tombstones should be cleared by Compact")
+	}
+	if postings, _ := scorer.FrozenIndex.Get("dragon"); postings["doc3"].CorpusDocFreq != 1 {
+		t.Errorf("Expected frozen df 1 for dragon, got %+v", postings)
+	}
+}
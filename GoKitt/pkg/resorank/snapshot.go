@@ -0,0 +1,325 @@
+package resorank
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+// Snapshot layout (all integers little-endian):
+//
+//	magic    [4]byte  "RSRK"
+//	version  uint16
+//	length   uint64   payload length
+//	payload  []byte
+//	checksum uint32   CRC-32 (IEEE) of payload
+//
+// The payload holds, in order: the config as JSON, corpus stats, document
+// metadata with each document's term list, and the live postings as an FST
+// plus its postings blob. Doc frequencies are rebuilt from the term lists.
+
+// SnapshotVersion is the current snapshot format version.
+// Version 2 indexes analyzed (stemmed) terms; version 1 snapshots held raw
+// words and are rejected so callers re-index.
+const SnapshotVersion = 2
+
+var snapshotMagic = [4]byte{'R', 'S', 'R', 'K'}
+
+var (
+	// ErrSnapshotFormat is returned for data that isn't a ResoRank snapshot
+	ErrSnapshotFormat = errors.New("resorank: not a snapshot")
+	// ErrSnapshotVersion is returned for snapshots written by an unknown format version
+	ErrSnapshotVersion = errors.New("resorank: unsupported snapshot version")
+	// ErrSnapshotChecksum is returned when the payload doesn't match its checksum
+	ErrSnapshotChecksum = errors.New("resorank: snapshot checksum mismatch")
+)
+
+// Snapshot serializes the scorer into a single binary blob. Both index
+// layers are merged, so the snapshot never carries tombstones.
+func (s *Scorer) Snapshot() ([]byte, error) {
+	var payload bytes.Buffer
+	if err := s.writeSnapshotPayload(&payload); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Grow(payload.Len() + 18)
+	out.Write(snapshotMagic[:])
+	binary.Write(&out, binary.LittleEndian, uint16(SnapshotVersion))
+	binary.Write(&out, binary.LittleEndian, uint64(payload.Len()))
+	out.Write(payload.Bytes())
+	binary.Write(&out, binary.LittleEndian, crc32.ChecksumIEEE(payload.Bytes()))
+	return out.Bytes(), nil
+}
+
+// LoadScorer restores a scorer from a Snapshot. All postings land in the
+// FrozenIndex; the mutable overlay starts empty.
+func LoadScorer(data []byte) (*Scorer, error) {
+	if len(data) < 18 || !bytes.Equal(data[:4], snapshotMagic[:]) {
+		return nil, ErrSnapshotFormat
+	}
+	version := binary.LittleEndian.Uint16(data[4:6])
+	if version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, version)
+	}
+	length := binary.LittleEndian.Uint64(data[6:14])
+	if uint64(len(data)-18) != length {
+		return nil, fmt.Errorf("%w: truncated payload", ErrSnapshotFormat)
+	}
+	payload := data[14 : 14+length]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(data[14+length:]) {
+		return nil, ErrSnapshotChecksum
+	}
+
+	s, err := readSnapshotPayload(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("resorank: decode snapshot: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Scorer) writeSnapshotPayload(w *bytes.Buffer) error {
+	// Config
+	cfg, err := json.Marshal(s.Config)
+	if err != nil {
+		return err
+	}
+	writeString(w, string(cfg))
+
+	// Corpus stats
+	writeUvarint(w, uint64(s.CorpusStats.TotalDocuments))
+	writeFloat64(w, s.CorpusStats.AverageDocLength)
+	fields := sortedKeys(s.CorpusStats.AverageFieldLengths)
+	writeUvarint(w, uint64(len(fields)))
+	for _, field := range fields {
+		writeString(w, field)
+		writeFloat64(w, s.CorpusStats.AverageFieldLengths[field])
+	}
+
+	// Documents
+	docIDs := sortedKeys(s.DocumentIndex)
+	writeUvarint(w, uint64(len(docIDs)))
+	for _, docID := range docIDs {
+		meta := s.DocumentIndex[docID]
+		writeString(w, docID)
+		writeUvarint(w, uint64(meta.TotalTokenCount))
+		fieldNames := sortedKeys(meta.FieldLengths)
+		writeUvarint(w, uint64(len(fieldNames)))
+		for _, field := range fieldNames {
+			writeString(w, field)
+			writeUvarint(w, uint64(meta.FieldLengths[field]))
+		}
+		writeUvarint(w, uint64(len(meta.Embedding)))
+		for _, v := range meta.Embedding {
+			binary.Write(w, binary.LittleEndian, math.Float32bits(v))
+		}
+		writeString(w, meta.NarrativeID)
+		writeString(w, meta.FolderPath)
+
+		terms := s.DocTerms[docID]
+		writeUvarint(w, uint64(len(terms)))
+		for _, term := range terms {
+			writeString(w, term)
+		}
+	}
+
+	// Postings: reuse the frozen layer as-is when nothing sits on top of it
+	index := s.FrozenIndex
+	if len(s.TokenIndex) > 0 || len(s.Tombstones) > 0 {
+		if index, err = s.buildLiveIndex(); err != nil {
+			return err
+		}
+		if index != nil {
+			defer index.Close()
+		}
+	}
+	if index == nil {
+		writeUvarint(w, 0)
+		writeUvarint(w, 0)
+		return nil
+	}
+	writeUvarint(w, uint64(len(index.FST)))
+	w.Write(index.FST)
+	writeUvarint(w, uint64(len(index.Postings)))
+	w.Write(index.Postings)
+	return nil
+}
+
+func readSnapshotPayload(r *bytes.Reader) (*Scorer, error) {
+	cfgJSON, err := readString(r)
+	if err != nil {
+		return nil, err
+	}
+	config := DefaultConfig()
+	if err := json.Unmarshal([]byte(cfgJSON), &config); err != nil {
+		return nil, err
+	}
+	s := NewScorer(config)
+
+	// Corpus stats
+	total, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	s.CorpusStats.TotalDocuments = int(total)
+	if s.CorpusStats.AverageDocLength, err = readFloat64(r); err != nil {
+		return nil, err
+	}
+	fieldCount, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < fieldCount; i++ {
+		field, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		if s.CorpusStats.AverageFieldLengths[field], err = readFloat64(r); err != nil {
+			return nil, err
+		}
+	}
+
+	// Documents
+	docCount, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < docCount; i++ {
+		docID, meta, terms, err := readSnapshotDocument(r)
+		if err != nil {
+			return nil, err
+		}
+		s.DocumentIndex[docID] = meta
+		s.DocTerms[docID] = terms
+		for _, term := range terms {
+			s.DocFrequencies[term]++
+		}
+	}
+
+	// Postings
+	fstBytes, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	postings, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(fstBytes) > 0 {
+		if s.FrozenIndex, err = OpenFSTIndex(fstBytes, postings); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func readSnapshotDocument(r *bytes.Reader) (string, DocumentMetadata, []string, error) {
+	var meta DocumentMetadata
+	docID, err := readString(r)
+	if err != nil {
+		return "", meta, nil, err
+	}
+	tokenCount, err := readUvarint(r)
+	if err != nil {
+		return "", meta, nil, err
+	}
+	meta.TotalTokenCount = int(tokenCount)
+
+	fieldCount, err := readUvarint(r)
+	if err != nil {
+		return "", meta, nil, err
+	}
+	if fieldCount > 0 {
+		meta.FieldLengths = make(map[string]int, fieldCount)
+	}
+	for i := uint64(0); i < fieldCount; i++ {
+		field, err := readString(r)
+		if err != nil {
+			return "", meta, nil, err
+		}
+		length, err := readUvarint(r)
+		if err != nil {
+			return "", meta, nil, err
+		}
+		meta.FieldLengths[field] = int(length)
+	}
+
+	dims, err := readUvarint(r)
+	if err != nil {
+		return "", meta, nil, err
+	}
+	if dims > 0 {
+		meta.Embedding = make([]float32, dims)
+		for i := range meta.Embedding {
+			var bits uint32
+			if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+				return "", meta, nil, err
+			}
+			meta.Embedding[i] = math.Float32frombits(bits)
+		}
+	}
+
+	if meta.NarrativeID, err = readString(r); err != nil {
+		return "", meta, nil, err
+	}
+	if meta.FolderPath, err = readString(r); err != nil {
+		return "", meta, nil, err
+	}
+
+	termCount, err := readUvarint(r)
+	if err != nil {
+		return "", meta, nil, err
+	}
+	terms := make([]string, termCount)
+	for i := range terms {
+		if terms[i], err = readString(r); err != nil {
+			return "", meta, nil, err
+		}
+	}
+	return docID, meta, terms, nil
+}
+
+// --- Helpers ---
+
+func writeFloat64(w io.Writer, v float64) error {
+	return binary.Write(w, binary.LittleEndian, math.Float64bits(v))
+}
+
+func readFloat64(r io.Reader) (float64, error) {
+	var bits uint64
+	if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(bits), nil
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	length, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
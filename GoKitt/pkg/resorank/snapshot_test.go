@@ -0,0 +1,111 @@
+package resorank
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FieldWeights["body"] = 2.0
+	cfg.VectorAlpha = 0.25
+
+	scorer := NewScorer(cfg)
+	scorer.IndexDocument("doc1", DocumentMetadata{
+		TotalTokenCount: 10,
+		FieldLengths:    map[string]int{"body": 10},
+		Embedding:       []float32{0.5, -1},
+		NarrativeID:     "narrative-A",
+		FolderPath:      "Timeline/Chapter1",
+	}, bodyTokens(10, "dragon", "castle"))
+	scorer.IndexDocument("doc2", bodyDoc(20), bodyTokens(20, "dragon"))
+	if err := scorer.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	// Leave work on top of the frozen layer: a tombstone and a mutable doc
+	scorer.RemoveDocument("doc2")
+	scorer.IndexDocument("doc3", bodyDoc(30), bodyTokens(30, "dragon", "knight"))
+
+	data, err := scorer.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	loaded, err := LoadScorer(data)
+	if err != nil {
+		t.Fatalf("LoadScorer failed: %v", err)
+	}
+	defer loaded.FrozenIndex.Close()
+
+	if !reflect.DeepEqual(loaded.Config, scorer.Config) {
+		t.Errorf("Config mismatch:\n got %+v\nwant %+v", loaded.Config, scorer.Config)
+	}
+	if !reflect.DeepEqual(loaded.CorpusStats, scorer.CorpusStats) {
+		t.Errorf("Stats mismatch:\n got %+v\nwant %+v", loaded.CorpusStats, scorer.CorpusStats)
+	}
+	if !reflect.DeepEqual(loaded.DocumentIndex, scorer.DocumentIndex) {
+		t.Errorf("Documents mismatch:\n got %+v\nwant %+v", loaded.DocumentIndex, scorer.DocumentIndex)
+	}
+	if !reflect.DeepEqual(loaded.DocFrequencies, scorer.DocFrequencies) {
+		t.Errorf("Doc frequencies mismatch: got %v, want %v", loaded.DocFrequencies, scorer.DocFrequencies)
+	}
+
+	for _, query := range [][]string{{"dragon"}, {"castle"}, {"knight", "dragon"}} {
+		want := scorer.Search(query, nil, 10)
+		got := loaded.Search(query, nil, 10)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Search %v mismatch:\n got %+v\nwant %+v", query, got, want)
+		}
+	}
+
+	// The loaded scorer stays fully mutable
+	if !loaded.RemoveDocument("doc1") {
+		t.Error("Expected doc1 to be removable after load")
+	}
+	if results := loaded.Search([]string{"castle"}, nil, 10); len(results) != 0 {
+		t.Errorf("Expected no castle results, got %+v", results)
+	}
+}
+
+func TestSnapshotEmptyScorer(t *testing.T) {
+	data, err := NewScorer(DefaultConfig()).Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	loaded, err := LoadScorer(data)
+	if err != nil {
+		t.Fatalf("LoadScorer failed: %v", err)
+	}
+	if loaded.FrozenIndex != nil || len(loaded.DocumentIndex) != 0 {
+		t.Error("Expected an empty scorer")
+	}
+}
+
+func TestSnapshotRejectsCorruptData(t *testing.T) {
+	scorer := NewScorer(DefaultConfig())
+	scorer.IndexDocument("doc1", bodyDoc(10), bodyTokens(10, "dragon"))
+	data, err := scorer.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)/2] ^= 0xFF
+	if _, err := LoadScorer(corrupt); !errors.Is(err, ErrSnapshotChecksum) {
+		t.Errorf("Expected checksum error, got %v", err)
+	}
+
+	future := append([]byte(nil), data...)
+	future[4] = SnapshotVersion + 1
+	if _, err := LoadScorer(future); !errors.Is(err, ErrSnapshotVersion) {
+		t.Errorf("Expected version error, got %v", err)
+	}
+
+	if _, err := LoadScorer([]byte("not a snapshot at all")); !errors.Is(err, ErrSnapshotFormat) {
+		t.Errorf("Expected format error, got %v", err)
+	}
+	if _, err := LoadScorer(data[:len(data)-1]); !errors.Is(err, ErrSnapshotFormat) {
+		t.Errorf("Expected format error for truncated data, got %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package resorank
 
+import "github.com/kittclouds/gokitt/pkg/stem"
+
 // ResoRankConfig holds scoring parameters
 type ResoRankConfig struct {
 	K1                  float64               `json:"k1"`
@@ -13,6 +15,9 @@ type ResoRankConfig struct {
 	FieldParams         map[string]FieldParam `json:"fieldParams"`
 	VectorAlpha         float64               `json:"vectorAlpha"` // Weight for vector score (0-1)
 
+	// Analysis: how each field's words become terms. Unlisted fields are stemmed.
+	FieldAnalyzers map[string]stem.Analyzer `json:"fieldAnalyzers"`
+
 	// BMX / Entropy
 	EnableBMXEntropy    bool     `json:"enableBmxEntropy"`
 	EnableBMXSimilarity bool     `json:"enableBmxSimilarity"`
@@ -30,6 +35,9 @@ type FieldParam struct {
 	B      float64 `json:"b"` // Field-specific b
 }
 
+// EntityField holds entity names, which are matched unstemmed
+const EntityField = "entities"
+
 func DefaultConfig() ResoRankConfig {
 	return ResoRankConfig{
 		K1:                    1.2,
@@ -41,6 +49,7 @@ func DefaultConfig() ResoRankConfig {
 		FieldWeights:          make(map[string]float64),
 		FieldParams:           make(map[string]FieldParam),
 		VectorAlpha:           0.0,
+		FieldAnalyzers:        map[string]stem.Analyzer{EntityField: stem.Lowercase},
 		EnableBMXEntropy:      false,
 		EnableBMXSimilarity:   false,
 		UseAdaptiveAlpha:      false,
@@ -323,20 +323,19 @@ func (c *Chunker) tryNounPhrase(tokens []Token, start int) (Chunk, int) {
 	return Chunk{}, 0
 }
 
-// tryVerbPhrase: Aux? Adv* Verb Adv*
+// tryVerbPhrase: (Aux|Modal|Adv)* Verb Adv*
+// The auxiliary chain keeps negation and modality with the verb ("would not have killed").
 func (c *Chunker) tryVerbPhrase(tokens []Token, start int) (Chunk, int) {
 	i := start
 	var modifiers []TextRange
 	headIdx := -1
+	lastAux := -1
 
-	// Optional auxiliary/modal
-	if i < len(tokens) && (tokens[i].POS == Auxiliary || tokens[i].POS == Modal) {
-		modifiers = append(modifiers, tokens[i].Range)
-		i++
-	}
-
-	// Pre-verb adverbs
-	for i < len(tokens) && tokens[i].POS == Adverb {
+	// Auxiliaries, modals and the adverbs between them
+	for i < len(tokens) && (tokens[i].POS == Auxiliary || tokens[i].POS == Modal || tokens[i].POS == Adverb) {
+		if tokens[i].POS != Adverb {
+			lastAux = i
+		}
 		modifiers = append(modifiers, tokens[i].Range)
 		i++
 	}
@@ -345,13 +344,14 @@ func (c *Chunker) tryVerbPhrase(tokens []Token, start int) (Chunk, int) {
 	if i < len(tokens) && tokens[i].POS == Verb {
 		headIdx = i
 		i++
-	} else if headIdx == -1 && len(modifiers) > 0 {
+	} else if lastAux >= 0 {
 		// If we saw Aux/Modal but no main Verb, treat the last Aux as the head (Copula-like behavior)
-		// e.g. "is" in "is dangerous"
-		headIdx = i - 1 // The last modifier was the Aux
-		// Re-classify modifiers (remove the head from modifiers list)
-		// This is a bit hacky, but simpler than rewriting the loop.
-		// Actually, let's just accept it.
+		// e.g. "is" in "is dangerous", "was not" in "was not afraid"
+		headIdx = lastAux
+		modifiers = append(modifiers[:lastAux-start], modifiers[lastAux-start+1:]...)
+	} else if len(modifiers) > 0 {
+		// Adverbs alone: keep the last one as head
+		headIdx = i - 1
 	} else {
 		// Special Case: "is" appearing alone (Auxiliary)
 		// If we haven't consumed anything distinctively verb-like, fail.
@@ -182,3 +182,26 @@ func filterByKind(chunks []Chunk, kind ChunkKind) []Chunk {
 	}
 	return out
 }
+
+func TestVerbPhraseAuxiliaryChain(t *testing.T) {
+	c := New()
+	cases := map[string]string{
+		"Aria did not kill the king":          "did not kill",
+		"Aria might not betray him":           "might not betray",
+		"Aria would not have killed the king": "would not have killed",
+		"Aria didn't kill the king":           "didn't kill",
+	}
+	for text, want := range cases {
+		vps := filterByKind(c.Chunk(text).Chunks, VerbPhrase)
+		if len(vps) != 1 {
+			t.Errorf("%s: expected 1 VP, got %d", text, len(vps))
+			continue
+		}
+		if got := vps[0].Text(text); got != want {
+			t.Errorf("%s: VP = %q, want %q", text, got, want)
+		}
+		if head := vps[0].HeadText(text); head == "not" || head == "did" {
+			t.Errorf("%s: head should be the main verb, got %q", text, head)
+		}
+	}
+}
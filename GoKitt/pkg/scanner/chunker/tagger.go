@@ -44,8 +44,9 @@ func (t *Tagger) Tag(words []string) []POS {
 
 		// Rule 1: Determiner/Adjective force Noun
 		// "The [run]", "A fast [attack]"
-		// If current is Verb-like but preceded by Modifier/Det, it's likely a Noun
-		if (prevTag == Determiner || prevTag.IsModifier()) && currentTag.IsVerbal() {
+		// If current is Verb-like but preceded by Adjective/Det, it's likely a Noun
+		// (adverbs don't count: "did not [kill]", "quickly [ran]")
+		if (prevTag == Determiner || prevTag == Adjective) && currentTag.IsVerbal() {
 			// Special check: Don't convert "is/was" etc? No, lexicon handles those firmly.
 			// This works best for ambiguous words like "run", "attack", "play"
 			tags[i] = Noun
@@ -53,11 +54,15 @@ func (t *Tagger) Tag(words []string) []POS {
 		}
 
 		// Rule 2: Modal forces Verb
-		// "can [run]", "will [attack]"
+		// "can [run]", "will [attack]", also across adverbs: "might not [betray]"
 		if prevTag == Modal && currentTag.IsNominal() {
 			tags[i] = Verb
 			continue
 		}
+		if prevTag == Adverb && currentTag == Noun && modalBefore(tags, i) {
+			tags[i] = Verb
+			continue
+		}
 
 		// Rule 3: "To" forces Verb (Infinitive marker)
 		// "want to [run]"
@@ -148,6 +153,20 @@ func fastLower(s string) string {
 	return s
 }
 
+// modalBefore reports whether a modal precedes position i across adverbs only
+func modalBefore(tags []POS, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		switch tags[j] {
+		case Adverb:
+			continue
+		case Modal:
+			return true
+		}
+		return false
+	}
+	return false
+}
+
 func isTo(s string) bool {
 	return len(s) == 2 && (s[0] == 't' || s[0] == 'T') && (s[1] == 'o' || s[1] == 'O')
 }
@@ -174,12 +193,14 @@ func (t *Tagger) loadDefaultLexicon() {
 
 	// Auxiliaries
 	for _, w := range []string{"is", "are", "was", "were", "be", "been", "being", "am",
-		"have", "has", "had", "having", "do", "does", "did", "doing"} {
+		"have", "has", "had", "having", "do", "does", "did", "doing",
+		"isn't", "aren't", "wasn't", "weren't", "hasn't", "haven't", "hadn't", "don't", "doesn't", "didn't"} {
 		t.lexicon[w] = Auxiliary
 	}
 
 	// Modals
-	for _, w := range []string{"can", "could", "will", "would", "shall", "should", "may", "might", "must"} {
+	for _, w := range []string{"can", "could", "will", "would", "shall", "should", "may", "might", "must",
+		"cannot", "can't", "couldn't", "won't", "wouldn't", "shouldn't", "mustn't"} {
 		t.lexicon[w] = Modal
 	}
 
@@ -211,7 +232,7 @@ func (t *Tagger) loadDefaultLexicon() {
 	// Common adverbs
 	for _, w := range []string{"very", "quite", "rather", "really", "too", "just", "only",
 		"now", "then", "here", "there", "always", "never", "often", "sometimes", "slowly",
-		"quickly", "suddenly", "finally", "already", "still", "even"} {
+		"quickly", "suddenly", "finally", "already", "still", "even", "not"} {
 		t.lexicon[w] = Adverb
 	}
 
@@ -3,13 +3,15 @@
 package conductor
 
 import (
-	"strings"
-	"unicode"
+	"sort"
+	"sync"
 
 	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
 	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/conductor/helpers"
-	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/frontmatter"
+	"github.com/kittclouds/gokitt/pkg/scanner/markdown"
 	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
 	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
@@ -24,6 +26,17 @@ type ScanResult struct {
 	Chunks       []chunker.Chunk
 	Narrative    []NarrativeEvent
 	ResolvedRefs []ResolvedReference
+	Possessions  []Possession             // "Mira's sword", "his ring"
+	Ambiguities  []Ambiguity              // Implicit matches the evidence could not settle
+	Dialogue     []dialogue.Utterance     // Quoted speech in text order
+	Frontmatter  *frontmatter.Frontmatter // nil if the note has none (or it isn't valid YAML)
+	Entities     map[string]EntityName    // Names of the entity IDs matched in the text
+}
+
+// EntityName is what a scan knows about a matched entity ID
+type EntityName struct {
+	Name string
+	Kind string
 }
 
 // NarrativeEvent is a high-level derived event from the scan
@@ -33,6 +46,16 @@ type NarrativeEvent struct {
 	Subject  string // EntityID or "Unknown"
 	Object   string // EntityID or "Unknown"
 	Range    chunker.TextRange
+
+	// How the text commits to the event: "did not kill", "might kill", "if ... kills"
+	Polarity narrative.Polarity
+	Modality narrative.Modality
+	Tense    narrative.Tense
+}
+
+// IsFactual reports whether the event is affirmed without modal or hypothetical hedging
+func (e NarrativeEvent) IsFactual() bool {
+	return e.Polarity == narrative.Affirmed && e.Modality == narrative.Factual
 }
 
 // ResolvedReference maps a text span to an EntityID
@@ -42,14 +65,36 @@ type ResolvedReference struct {
 	Range    chunker.TextRange
 }
 
-// Conductor manages the scanning pipeline
+// Possession is an OWNS relation read from a possessive: "Mira's sword"
+type Possession struct {
+	Owner string // EntityID, or the name when unresolved
+	Owned string // EntityID of a tagged entity, else the lower-cased noun
+	Range chunker.TextRange
+}
+
+// Ambiguity is an implicit match shared by several in-scope entities that
+// scope, co-occurrence and recency could not tell apart. The match in Syntax
+// uses Chosen; callers may ask the user instead.
+type Ambiguity struct {
+	Text       string
+	Range      chunker.TextRange
+	Chosen     string   // EntityID used for the match
+	Candidates []string // EntityIDs, best first
+}
+
+// Conductor manages the scanning pipeline.
+// Resolver and discovery state lives in ScanSessions; Scan uses the default session.
 type Conductor struct {
 	syntaxScanner    *syntax.SyntaxScanner
 	implicitScanner  *implicitmatcher.RuntimeDictionary
 	chunker          *chunker.Chunker
 	narrativeMatcher *narrative.NarrativeMatcher
-	resolver         *resolver.Resolver
-	discoveryEngine  *discovery.DiscoveryEngine
+
+	mu             sync.Mutex
+	defaultSession *ScanSession
+	sessions       map[string]*ScanSession // By narrative ID
+	seeds          []implicitmatcher.RegisteredEntity
+	rejected       []string // Tokens every session's discovery ignores
 }
 
 // New creates a new Conductor with all sub-components initialized
@@ -59,17 +104,15 @@ func New() (*Conductor, error) {
 		return nil, err
 	}
 
-	// Initialize Discovery Engine (threshold 2 for demo)
-	discEngine := discovery.NewEngine(2, nm)
-
-	return &Conductor{
+	c := &Conductor{
 		syntaxScanner:    syntax.New(),
 		implicitScanner:  nil, // To be loaded if needed
 		chunker:          chunker.New(),
 		narrativeMatcher: nm,
-		resolver:         resolver.New(),
-		discoveryEngine:  discEngine,
-	}, nil
+		sessions:         make(map[string]*ScanSession),
+	}
+	c.defaultSession = c.newSession("")
+	return c, nil
 }
 
 // SetDictionary loads the implicit scanner dictionary
@@ -82,17 +125,60 @@ func (c *Conductor) GetDictionary() *implicitmatcher.RuntimeDictionary {
 	return c.implicitScanner
 }
 
-// Scan processes text through all pipeline stages
+// GetSyntaxScanner returns the explicit syntax scanner, where inline
+// patterns are registered
+func (c *Conductor) GetSyntaxScanner() *syntax.SyntaxScanner {
+	return c.syntaxScanner
+}
+
+// Scan processes text through all pipeline stages using the default session
 func (c *Conductor) Scan(text string) ScanResult {
+	return c.defaultSession.Scan(text)
+}
+
+// scan runs every pipeline stage against the given resolver and the session's discovery engine
+func (s *ScanSession) scan(text string, res *resolver.Resolver) ScanResult {
+	c := s.conductor
+
+	// 0. Markdown Pass (Structure)
+	// Code, frontmatter and markers are blanked out so they never reach the
+	// matchers or the chunker; offsets stay valid for the original text.
+	doc := markdown.Parse(text)
+	clean := doc.Mask(text)
+	prose := doc.Prose()
+
+	var meta *frontmatter.Frontmatter
+	if block := doc.Frontmatter(); block != nil {
+		meta, _ = frontmatter.Parse(block.Content.Slice(text))
+	}
+
 	// 1. Syntax Pass (Explicit Tags/Links)
-	synMatches := c.syntaxScanner.Scan(text)
-	c.registerExplicitEntities(synMatches)
+	// Mentions are observed in text order as each pass reaches them, so a
+	// reference never resolves to an entity named after it
+	synMatches := c.syntaxScanner.Scan(clean)
+	mentions := newTimeline(res)
+	s.registerExplicitEntities(res, mentions, synMatches)
+
+	// Hidden inline spans ("%%GM note%%") are blanked like code: the syntax
+	// match stays, its words never reach the matchers or the chunker
+	clean = c.maskHidden(clean, synMatches)
 
 	// 2. Implicit Matcher Pass (Registry Entities) - Phase 0 Fix
 	// This allows entities registered via LLM or manual tagging to be found in prose
 	// and create EntitySpan nodes in the CST for relationship extraction.
+	var ambiguities []Ambiguity
 	if c.implicitScanner != nil {
-		implicitHits := c.implicitScanner.ScanWithInfo(text)
+		// Only the session's narrative and global entities are in scope
+		scope := implicitmatcher.Scope{NarrativeID: s.narrativeID}
+		var implicitHits []implicitmatcher.ScanHit
+		for _, hit := range c.implicitScanner.ScanScoped(clean, scope) {
+			// Skip matches that run across blocks (list items, table cells)
+			if withinAny(prose, hit.Start, hit.End) {
+				implicitHits = append(implicitHits, hit)
+			}
+		}
+		explicit := synMatches
+
 		for _, hit := range implicitHits {
 			// Skip if this span already has a syntax match (explicit takes priority)
 			isOverlapping := false
@@ -107,16 +193,26 @@ func (c *Conductor) Scan(text string) ScanResult {
 				continue
 			}
 
-			// Use best entity if multiple match same pattern
-			bestEntity := c.implicitScanner.SelectBest(func() []string {
-				ids := make([]string, 0, len(hit.Entities))
-				for _, e := range hit.Entities {
-					ids = append(ids, e.ID)
-				}
-				return ids
-			}())
+			// Several entities may share the surface form ("Ash" in two worlds):
+			// weigh the paragraph's other entities and the mentions before this one
+			mentions.at(hit.Start)
+			resolution := c.implicitScanner.Disambiguate(hit.Entities, implicitmatcher.Evidence{
+				Scope:       scope,
+				CoOccurring: c.coOccurring(scope, implicitHits, explicit, blockOf(prose, hit.Start)),
+				Recency:     func(e *implicitmatcher.EntityInfo) int { return res.Recency(e.ID) },
+			})
+			bestEntity := resolution.Best
 
 			if bestEntity != nil {
+				if resolution.Ambiguous {
+					ambiguities = append(ambiguities, Ambiguity{
+						Text:       hit.MatchedText,
+						Range:      chunker.NewRange(hit.Start, hit.End),
+						Chosen:     bestEntity.ID,
+						Candidates: entityIDs(resolution.Candidates),
+					})
+				}
+
 				synMatches = append(synMatches, syntax.SyntaxMatch{
 					Start:      hit.Start,
 					End:        hit.End,
@@ -124,46 +220,70 @@ func (c *Conductor) Scan(text string) ScanResult {
 					Original:   hit.MatchedText,
 					Kind:       syntax.KindEntity,
 					EntityKind: bestEntity.Kind.String(),
+					EntityID:   bestEntity.ID,
 					Label:      bestEntity.Label,
 				})
 
-				// Also register with resolver for pronoun resolution
-				c.resolver.ObserveMention(bestEntity.Label)
+				// Also register with resolver for pronoun and description resolution.
+				// Keyed by ID, so same-named entities keep their own history.
+				if _, known := res.Entity(bestEntity.ID); !known {
+					res.RegisterEntity(resolver.EntityMetadata{
+						ID:      bestEntity.ID,
+						Name:    bestEntity.Label,
+						Kind:    bestEntity.Kind.String(),
+						Aliases: []string{},
+						Gender:  genderForKind(bestEntity.Kind.String()),
+					})
+				}
+				mentions.add(mention{pos: hit.Start, id: bestEntity.ID})
 			}
 		}
 	}
+	// "Aria and Tom" becomes a group for a later "they"
+	for _, group := range groupMentions(clean, entityMatches(synMatches)) {
+		mentions.add(group)
+	}
+
+	// 2b. Dialogue Pass (Quotes -> Speakers)
+	// Runs before chunking so quoted speech is chunked apart from narration
+	talk := s.attributeDialogue(clean, prose, synMatches, res, mentions)
 
 	// 3. Chunker Pass (Structure)
-	chunkResult := c.chunker.Chunk(text)
+	// Each prose block is chunked on its own so phrases never cross blocks,
+	// and quotes are split from the narration around them
+	chunkResult := c.chunkProse(clean, splitAtQuotes(prose, talk.quotes))
+
+	// 3b. Coreference Pass (Descriptions, Possessives)
+	// "the king" and "Mira's sword" are read against the mentions before them
+	coref := resolveCoreference(clean, chunkResult.Tokens, synMatches, res, mentions)
 
-	// 4. Harvest Candidates (All NPs)
+	// 4. Harvest Candidates (capitalized names inside NPs)
+	// Tagged and matched entities are already known, so their NPs are skipped
+	var nounPhrases []chunker.TextRange
 	for _, chunk := range chunkResult.Chunks {
-		if chunk.Kind == chunker.NounPhrase {
-			head := chunk.HeadText(text)
-			// Only observe if capitalized (heuristic for Proper Noun)
-			if len(head) > 0 {
-				first := []rune(head)[0]
-				if unicode.IsUpper(first) {
-					c.discoveryEngine.ObserveToken(head)
-				}
-			}
+		if chunk.Kind == chunker.NounPhrase && !overlapsEntity(synMatches, chunk.Range) {
+			nounPhrases = append(nounPhrases, chunk.Range)
 		}
 	}
+	s.discovery.ObservePhrases("", clean, nounPhrases)
 
 	// 5. Narrative Pass (Verbs -> Events) & Discovery "Virus"
-	var narrativeEvents []NarrativeEvent
+	// Speech and its tags become SPEAKS_TO events spanning the quote
+	narrativeEvents := talk.events()
+	entities := entityMatches(synMatches)
 
 	for i, chunk := range chunkResult.Chunks {
-		if chunk.Kind == chunker.VerbPhrase {
-			// Check verb against Narrative FST
-			headVerb := chunk.HeadText(text)
-			match := c.narrativeMatcher.Lookup(headVerb)
+		if chunk.Kind == chunker.VerbPhrase && !talk.owns(chunk.Range) {
+			// Check verb against Narrative FST, longest phrasal verb first
+			// ("ran away from" takes its particle and preposition along)
+			match, n := c.narrativeMatcher.LookupPhrase(clean[chunk.Head.Start:])
 
 			if match != nil {
 				// We found a narrative event!
-				// Attempt to find Subject (prev NP) and Object (next NP)
+				// Attempt to find Subject (prev NP) and Object (next NP after the phrase)
+				phraseEnd := chunk.Head.Start + n
 				subjChunk := helpers.FindPrevNP(chunkResult.Chunks, i)
-				objChunk := helpers.FindNextNP(chunkResult.Chunks, i)
+				objChunk := helpers.FindNextNPAfter(chunkResult.Chunks, i, phraseEnd)
 
 				subjText := "Unknown"
 				objText := "Unknown"
@@ -177,41 +297,57 @@ func (c *Conductor) Scan(text string) ScanResult {
 
 				// Run Discovery Logic (Virus)
 				if subjChunk != nil && objChunk != nil {
-					subjKind := c.resolveKind(subjText)
+					subjKind := s.resolveKind(subjText)
 					// Only propagate from known kinds for now, or assume Character if Proper
 					if subjKind != implicitmatcher.KindOther {
-						c.discoveryEngine.ObserveRelation(subjKind, match, objText)
+						s.discovery.ObserveRelation(subjKind, match, objText)
 					}
 				}
 
 				// Resolve Entity IDs for final output
-				subjID := c.resolver.Resolve(subjText, nil)
-				if subjID == "" {
-					subjID = subjText
-				}
+				// A definite description ("the old wizard") is resolved as a whole
+				subjID := resolveChunk(res, mentions, coref, entities, subjChunk, subjText)
+				objID := resolveChunk(res, mentions, coref, entities, objChunk, objText)
 
-				objID := c.resolver.Resolve(objText, nil)
-				if objID == "" {
-					objID = objText
-				}
+				// Negation, modals and the clause around the verb
+				q := narrative.Qualify(chunk.Text(clean), clean[:chunk.Range.Start])
 
 				narrativeEvents = append(narrativeEvents, NarrativeEvent{
 					Event:    match.EventClass,
 					Relation: match.RelationType,
 					Subject:  subjID,
 					Object:   objID,
-					Range:    chunk.Range,
+					Range:    chunker.NewRange(chunk.Range.Start, max(chunk.Range.End, phraseEnd)),
+					Polarity: q.Polarity,
+					Modality: q.Modality,
+					Tense:    q.Tense,
 				})
 			}
 		}
 	}
 
 	// 6. Resolver Pass (Pronouns) - Second pass for remaining tokens
+	// Entity matches already name their entity; same-named entities would
+	// be confused if their words were resolved by name
 	var resolvedRefs []ResolvedReference
+	names := make(map[string]EntityName, len(entities))
+	for _, m := range entities {
+		id := matchID(m)
+		resolvedRefs = append(resolvedRefs, ResolvedReference{
+			Text:     m.Text,
+			EntityID: id,
+			Range:    chunker.NewRange(m.Start, m.End),
+		})
+		names[id] = EntityName{Name: m.Label, Kind: m.EntityKind}
+	}
 	for _, token := range chunkResult.Tokens {
+		if insideEntity(entities, token.Range) {
+			continue
+		}
 		if token.POS == chunker.Pronoun || token.POS == chunker.ProperNoun {
 			word := token.Text
-			if id := c.resolver.Resolve(word, nil); id != "" {
+			mentions.at(token.Range.Start)
+			if id := res.Resolve(word, nil); id != "" {
 				resolvedRefs = append(resolvedRefs, ResolvedReference{
 					Text:     word,
 					EntityID: id,
@@ -221,63 +357,179 @@ func (c *Conductor) Scan(text string) ScanResult {
 		}
 	}
 
+	// The session continues from the end of the text
+	mentions.finish()
+
+	resolvedRefs = append(resolvedRefs, coref.descriptions...)
+	sort.Slice(resolvedRefs, func(i, j int) bool {
+		return resolvedRefs[i].Range.Start < resolvedRefs[j].Range.Start
+	})
+
 	return ScanResult{
 		Text:         text,
-		CleanText:    text,
+		CleanText:    clean,
 		Syntax:       synMatches,
 		Tokens:       chunkResult.Tokens,
 		Chunks:       chunkResult.Chunks,
 		Narrative:    narrativeEvents,
 		ResolvedRefs: resolvedRefs,
+		Possessions:  coref.possessions,
+		Ambiguities:  ambiguities,
+		Dialogue:     talk.utterances,
+		Frontmatter:  meta,
+		Entities:     names,
 	}
 }
 
-// Close cleans up resources
-func (c *Conductor) Close() error {
-	return c.narrativeMatcher.Close()
+// resolveChunk resolves an event's subject or object, falling back to its text
+func resolveChunk(res *resolver.Resolver, mentions *timeline, coref coreference, entities []syntax.SyntaxMatch, chunk *chunker.Chunk, head string) string {
+	if chunk != nil {
+		mentions.at(chunk.Range.Start)
+		if id, ok := coref.description(chunk.Range.Start); ok {
+			return id
+		}
+		for _, m := range entities {
+			if chunk.Head.Overlaps(chunker.NewRange(m.Start, m.End)) {
+				return matchID(m)
+			}
+		}
+	}
+	if id := res.Resolve(head, nil); id != "" {
+		return id
+	}
+	return head
+}
+
+// chunkProse chunks each prose range separately, in text coordinates
+func (c *Conductor) chunkProse(text string, prose []chunker.TextRange) chunker.ChunkResult {
+	var out chunker.ChunkResult
+	for _, r := range prose {
+		res := c.chunker.Chunk(text[r.Start:r.End])
+		for _, t := range res.Tokens {
+			t.Range = shiftRange(t.Range, r.Start)
+			out.Tokens = append(out.Tokens, t)
+		}
+		for _, ch := range res.Chunks {
+			ch.Range = shiftRange(ch.Range, r.Start)
+			ch.Head = shiftRange(ch.Head, r.Start)
+			for i, m := range ch.Modifiers {
+				ch.Modifiers[i] = shiftRange(m, r.Start)
+			}
+			out.Chunks = append(out.Chunks, ch)
+		}
+	}
+	return out
+}
+
+// splitAtQuotes cuts prose ranges at quote boundaries, keeping each quote whole
+func splitAtQuotes(prose []chunker.TextRange, quotes []dialogue.Quote) []chunker.TextRange {
+	if len(quotes) == 0 {
+		return prose
+	}
+	out := make([]chunker.TextRange, 0, len(prose)+2*len(quotes))
+	for _, r := range prose {
+		start := r.Start
+		for _, q := range quotes {
+			if q.Range.Start < r.Start || q.Range.End > r.End {
+				continue
+			}
+			if q.Range.Start > start {
+				out = append(out, chunker.TextRange{Start: start, End: q.Range.Start})
+			}
+			out = append(out, q.Range)
+			start = q.Range.End
+		}
+		if start < r.End {
+			out = append(out, chunker.TextRange{Start: start, End: r.End})
+		}
+	}
+	return out
 }
 
-// Helpers
+func shiftRange(r chunker.TextRange, off int) chunker.TextRange {
+	return chunker.TextRange{Start: r.Start + off, End: r.End + off}
+}
 
-func (c *Conductor) registerExplicitEntities(matches []syntax.SyntaxMatch) {
+func overlapsEntity(matches []syntax.SyntaxMatch, r chunker.TextRange) bool {
 	for _, m := range matches {
-		if m.Kind == syntax.KindEntity {
-			gender := resolver.GenderUnknown
-			k := strings.ToUpper(m.EntityKind)
-			if k == "LOCATION" || k == "OBJECT" || k == "ITEM" || k == "MONSTER" {
-				gender = resolver.GenderNeutral
+		if m.Kind == syntax.KindEntity && r.Overlaps(chunker.NewRange(m.Start, m.End)) {
+			return true
+		}
+	}
+	return false
+}
+
+// coOccurring returns the entities named unambiguously in a block, by
+// implicit match or by a tag whose label the dictionary knows
+func (c *Conductor) coOccurring(scope implicitmatcher.Scope, hits []implicitmatcher.ScanHit, explicit []syntax.SyntaxMatch, block chunker.TextRange) []string {
+	ids := implicitmatcher.CoOccurring(hits, block.Start, block.End)
+	for _, m := range explicit {
+		if m.Kind != syntax.KindEntity || m.Start < block.Start || m.End > block.End {
+			continue
+		}
+		var known []string
+		for _, info := range c.implicitScanner.Lookup(m.Label) {
+			if scope.Admits(info) {
+				known = append(known, info.ID)
 			}
+		}
+		if len(known) == 1 {
+			ids = append(ids, known[0])
+		}
+	}
+	return ids
+}
 
-			c.resolver.RegisterEntity(resolver.EntityMetadata{
-				ID:      m.Label,
-				Name:    m.Label,
-				Kind:    m.EntityKind,
-				Aliases: []string{},
-				Gender:  gender,
-			})
-			c.resolver.ObserveMention(m.Label)
+func entityIDs(entities []*implicitmatcher.EntityInfo) []string {
+	ids := make([]string, len(entities))
+	for i, e := range entities {
+		ids[i] = e.ID
+	}
+	return ids
+}
 
-			// Also tell Discovery about it (as PROMOTED + Known Kind)
-			c.discoveryEngine.ObserveToken(m.Label)
-			// Force set kind in registry
-			kind := implicitmatcher.ParseKind(m.EntityKind)
-			c.discoveryEngine.Registry.ProposeInference(m.Label, kind)
+// maskHidden blanks the matches of hidden inline patterns, keeping offsets
+func (c *Conductor) maskHidden(text string, matches []syntax.SyntaxMatch) string {
+	var buf []byte
+	for _, m := range matches {
+		if !c.syntaxScanner.IsHidden(m) {
+			continue
+		}
+		if buf == nil {
+			buf = []byte(text)
 		}
+		for i := m.Start; i < m.End; i++ {
+			buf[i] = ' '
+		}
+	}
+	if buf == nil {
+		return text
 	}
+	return string(buf)
 }
 
-func (c *Conductor) resolveKind(text string) implicitmatcher.EntityKind {
-	// 1. Check Resolver/Explicit
-	// (Resolver tracks EntityMetadata but not DAFSA Kind directly, needs alignment)
-	// For now, assume Character if Proper Noun and unknown
+// blockOf returns the prose block containing pos
+func blockOf(blocks []chunker.TextRange, pos int) chunker.TextRange {
+	for _, r := range blocks {
+		if pos >= r.Start && pos < r.End {
+			return r
+		}
+	}
+	return chunker.TextRange{}
+}
 
-	// 2. Check Discovery Registry
-	stats := c.discoveryEngine.Registry.GetStats(text)
-	if stats != nil && stats.InferredKind != nil {
-		return *stats.InferredKind
+func withinAny(ranges []chunker.TextRange, start, end int) bool {
+	for _, r := range ranges {
+		if start >= r.Start && end <= r.End {
+			return true
+		}
 	}
+	return false
+}
 
-	return implicitmatcher.KindCharacter // Aggressive default for demo
+// Close cleans up resources
+func (c *Conductor) Close() error {
+	return c.narrativeMatcher.Close()
 }
 
 // GetMatcher returns the narrative matcher for external use (Projection)
@@ -285,55 +537,84 @@ func (c *Conductor) GetMatcher() *narrative.NarrativeMatcher {
 	return c.narrativeMatcher
 }
 
-// GetCandidates returns unrelated candidates from Discovery Engine
+// GetCandidates returns unrelated candidates from the default session's Discovery Engine
 func (c *Conductor) GetCandidates() interface{} {
-	return c.discoveryEngine.Registry.GetCandidates()
+	return c.defaultSession.GetCandidates()
 }
 
-// ScanDiscovery runs the full discovery pipeline (Harvester + Virus)
+// ScanDiscovery runs the full discovery pipeline (Harvester + Virus) in the default session
 func (c *Conductor) ScanDiscovery(text string) {
-	// Phase 1: Harvester - Observe ALL capitalized words
-	// Use TokenizeWithOffsets to properly split on punctuation (not just whitespace)
-	tokens := implicitmatcher.TokenizeWithOffsets(text)
-	for _, tok := range tokens {
-		// Check if capitalized in original text (potential entity)
-		if tok.Start < len(text) && tok.End <= len(text) {
-			rawToken := text[tok.Start:tok.End]
-			if len(rawToken) > 0 {
-				first := []rune(rawToken)[0]
-				if unicode.IsUpper(first) {
-					c.discoveryEngine.ObserveToken(rawToken)
-				}
-			}
-		}
+	c.defaultSession.ScanDiscovery(text)
+}
+
+// SeedDiscovery pre-populates every session's discovery registry with known entities.
+// Sessions created later are seeded with the same entities.
+func (c *Conductor) SeedDiscovery(entities []implicitmatcher.RegisteredEntity) {
+	c.mu.Lock()
+	c.seeds = entities
+	sessions := make([]*ScanSession, 0, len(c.sessions)+1)
+	sessions = append(sessions, c.defaultSession)
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
 	}
+	c.mu.Unlock()
 
-	// Phase 2: Virus - Find relational patterns
-	c.discoveryEngine.ScanText(text)
+	for _, s := range sessions {
+		s.SeedDiscovery(entities)
+	}
 }
 
-// SeedDiscovery pre-populates the discovery registry with known entities
-// This gives ScanText promoted sources to work with
-func (c *Conductor) SeedDiscovery(entities []implicitmatcher.RegisteredEntity) {
-	for _, e := range entities {
-		// Add token and force promotion
-		c.discoveryEngine.Registry.AddToken(e.Label)
-		stats := c.discoveryEngine.Registry.GetStats(e.Label)
-		if stats != nil {
-			stats.Status = discovery.StatusPromoted
-			// Parse Kind from interface{}
-			var kind implicitmatcher.EntityKind
-			switch v := e.Kind.(type) {
-			case string:
-				kind = implicitmatcher.ParseKind(v)
-			case float64:
-				kind = implicitmatcher.EntityKind(int(v))
-			case implicitmatcher.EntityKind:
-				kind = v
-			default:
-				kind = implicitmatcher.KindOther
-			}
-			stats.InferredKind = &kind
+// AddSeed seeds every session's discovery with one more known entity,
+// replacing the seed with the same ID and keeping the others
+func (c *Conductor) AddSeed(e implicitmatcher.RegisteredEntity) {
+	c.mu.Lock()
+	c.seeds = append(withoutSeed(c.seeds, e.ID), e)
+	sessions := make([]*ScanSession, 0, len(c.sessions)+1)
+	sessions = append(sessions, c.defaultSession)
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	c.mu.Unlock()
+
+	for _, s := range sessions {
+		s.SeedDiscovery([]implicitmatcher.RegisteredEntity{e})
+	}
+}
+
+// RemoveSeed stops seeding sessions created later with an entity
+func (c *Conductor) RemoveSeed(id string) {
+	c.mu.Lock()
+	c.seeds = withoutSeed(c.seeds, id)
+	c.mu.Unlock()
+}
+
+// withoutSeed copies seeds without the entity id; sessions may still read the old slice
+func withoutSeed(seeds []implicitmatcher.RegisteredEntity, id string) []implicitmatcher.RegisteredEntity {
+	out := make([]implicitmatcher.RegisteredEntity, 0, len(seeds)+1)
+	for _, e := range seeds {
+		if e.ID != id {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// RejectCandidates makes every session's discovery ignore the tokens: they
+// become stopwords, and tracked candidates are marked ignored.
+// Sessions created later ignore them too.
+func (c *Conductor) RejectCandidates(tokens ...string) {
+	c.mu.Lock()
+	c.rejected = append(c.rejected, tokens...)
+	sessions := make([]*ScanSession, 0, len(c.sessions)+1)
+	sessions = append(sessions, c.defaultSession)
+	for _, s := range c.sessions {
+		sessions = append(sessions, s)
+	}
+	c.mu.Unlock()
+
+	for _, s := range sessions {
+		for _, token := range tokens {
+			s.discovery.Registry.Reject(token)
 		}
 	}
 }
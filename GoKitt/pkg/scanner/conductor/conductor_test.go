@@ -3,6 +3,9 @@ package conductor
 import (
 	"strings"
 	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
 )
 
 func TestConductorFullPipeline(t *testing.T) {
@@ -60,3 +63,124 @@ func TestConductorFullPipeline(t *testing.T) {
 		t.Error("Did not resolve 'He' to 'Gandalf'")
 	}
 }
+
+func TestConductorSkipsCodeAndFrontmatter(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	text := "---\nowner: [CHARACTER:Sauron]\n---\n# Notes\n\n[CHARACTER:Gandalf] traveled to [LOCATION:Mountain].\n\n```\n[CHARACTER:Frodo] defeated the [MONSTER:Balrog].\n```"
+	result := c.Scan(text)
+
+	if result.Frontmatter == nil || result.Frontmatter.Attribute("owner") == nil {
+		t.Errorf("Expected parsed frontmatter, got %+v", result.Frontmatter)
+	}
+	if len(result.Syntax) != 2 {
+		t.Errorf("Expected 2 syntax matches outside code and frontmatter, got %d", len(result.Syntax))
+	}
+	if len(result.Narrative) != 1 || !strings.Contains(result.Narrative[0].Event.String(), "TRAVEL") {
+		t.Errorf("Expected only the travel event, got %+v", result.Narrative)
+	}
+	for _, tok := range result.Tokens {
+		if tok.Text == "Frodo" || tok.Text == "owner" || tok.Text == "#" {
+			t.Errorf("Token %q should have been masked", tok.Text)
+		}
+		if text[tok.Range.Start:tok.Range.End] != tok.Text {
+			t.Errorf("Token %q has wrong offsets", tok.Text)
+		}
+	}
+}
+
+func TestNarrativeEventQualifiers(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	text := "[CHARACTER:Aria] did not kill the [CHARACTER:King]. [CHARACTER:Aria] might betray [CHARACTER:Bram]. " +
+		"If [CHARACTER:Aria] killed [CHARACTER:Bram], war would follow. [CHARACTER:Aria] betrayed [CHARACTER:King]."
+	result := c.Scan(text)
+
+	quals := map[narrative.RelationType][]string{}
+	for _, ev := range result.Narrative {
+		quals[ev.Relation] = append(quals[ev.Relation], ev.Polarity.String()+"/"+ev.Modality.String()+"/"+ev.Tense.String())
+	}
+	if got := strings.Join(quals[narrative.RelKills], " "); got != "negated/factual/past affirmed/hypothetical/past" {
+		t.Errorf("KILLS qualifiers = %q", got)
+	}
+	if got := strings.Join(quals[narrative.RelBetrays], " "); got != "affirmed/possible/present affirmed/factual/past" {
+		t.Errorf("BETRAYS qualifiers = %q", got)
+	}
+
+	factual := 0
+	for _, ev := range result.Narrative {
+		if ev.IsFactual() {
+			factual++
+		}
+	}
+	if factual != 1 {
+		t.Errorf("factual events = %d, want 1 (only the last betrayal)", factual)
+	}
+}
+
+func TestPhrasalVerbEvents(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	text := "Boromir turned against Frodo. Sam ran away from the troll."
+	result := c.Scan(text)
+
+	got := map[narrative.RelationType]string{}
+	for _, ev := range result.Narrative {
+		got[ev.Relation] = ev.Subject + ">" + ev.Object
+	}
+	if got[narrative.RelBetrays] != "Boromir>Frodo" {
+		t.Errorf("turned against: %v", got)
+	}
+	if got[narrative.RelDeparts] != "Sam>troll" {
+		t.Errorf("ran away from: %v", got)
+	}
+	if _, ok := got[narrative.RelBecomes]; ok {
+		t.Error("'turned against' must not fall back to 'turn'")
+	}
+}
+
+func TestHiddenInlinePatterns(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	note, err := c.GetSyntaxScanner().Register(syntax.InlinePattern{Name: "gm-note", Open: "%%", Close: "%%", Hidden: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "[CHARACTER:Aria] left. %%Secretly Tom follows her.%% She rode north."
+	result := c.Scan(text)
+
+	found := false
+	for _, m := range result.Syntax {
+		if m.Kind == note {
+			found = m.Label == "Secretly Tom follows her."
+		}
+	}
+	if !found {
+		t.Errorf("missing gm-note match in %+v", result.Syntax)
+	}
+	for _, tok := range result.Tokens {
+		if tok.Text == "Tom" || tok.Text == "Secretly" {
+			t.Errorf("hidden text reached the chunker: %q", tok.Text)
+		}
+	}
+	if strings.Contains(result.CleanText, "Tom") {
+		t.Errorf("hidden text left in CleanText: %q", result.CleanText)
+	}
+}
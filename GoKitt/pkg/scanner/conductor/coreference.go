@@ -0,0 +1,208 @@
+package conductor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
+)
+
+// coreference holds what the coreference pass found in one scan
+type coreference struct {
+	descriptions []ResolvedReference // "the old wizard", in text order
+	possessions  []Possession
+}
+
+// description returns the entity a definite description starting at start refers to
+func (cr coreference) description(start int) (string, bool) {
+	for _, ref := range cr.descriptions {
+		if ref.Range.Start == start {
+			return ref.EntityID, true
+		}
+	}
+	return "", false
+}
+
+// resolveCoreference resolves definite descriptions and possessives, each
+// against the mentions before it
+func resolveCoreference(text string, tokens []chunker.Token, matches []syntax.SyntaxMatch, res *resolver.Resolver, mentions *timeline) coreference {
+	entities := entityMatches(matches)
+
+	var cr coreference
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if insideEntity(entities, t.Range) {
+			continue
+		}
+		mentions.at(t.Range.Start)
+		switch {
+		case t.POS == chunker.Determiner && definite(t.Text):
+			if ref, ok := resolveDescription(text, tokens, i, res); ok {
+				cr.descriptions = append(cr.descriptions, ref)
+			}
+		case t.POS == chunker.Determiner && possessivePronoun(t.Text):
+			if owner := res.Resolve(t.Text, nil); owner != "" {
+				cr.addPossession(tokens, i+1, entities, owner, t.Range.Start)
+			}
+		case t.POS == chunker.ProperNoun:
+			if name, ok := trimPossessive(t.Text); ok {
+				owner := res.Resolve(name, nil)
+				if owner == "" {
+					owner = name
+				}
+				cr.addPossession(tokens, i+1, entities, owner, t.Range.Start)
+			}
+		}
+	}
+
+	// A tagged entity followed by 's: "[CHARACTER:Mira]'s ring"
+	for _, m := range entities {
+		for i, t := range tokens {
+			if t.Range.Start == m.End && (t.Text == "'s" || t.Text == "’s") {
+				cr.addPossession(tokens, i+1, entities, matchID(m), m.Start)
+				break
+			}
+		}
+	}
+	sort.Slice(cr.possessions, func(i, j int) bool {
+		return cr.possessions[i].Range.Start < cr.possessions[j].Range.Start
+	})
+	return cr
+}
+
+// groupMentions finds runs of coordinated entities ("Aria and Tom",
+// "Aria, Tom and Mira"), each mentioned as a group where the run ends,
+// so a later "they" can refer to them
+func groupMentions(text string, entities []syntax.SyntaxMatch) []mention {
+	var groups []mention
+	for i := 0; i < len(entities); {
+		members := []string{matchID(entities[i])}
+		coordinated := false
+		j := i + 1
+		for ; j < len(entities); j++ {
+			gap := strings.TrimSpace(text[entities[j-1].End:entities[j].Start])
+			if gap != "," {
+				if !conjunctions[gap] {
+					break
+				}
+				coordinated = true
+			}
+			members = append(members, matchID(entities[j]))
+		}
+		if coordinated && len(members) > 1 {
+			groups = append(groups, mention{pos: entities[j-1].End, members: members})
+		}
+		i = j
+	}
+	return groups
+}
+
+// conjunctions join entities into a group
+var conjunctions = map[string]bool{"and": true, ", and": true, "&": true}
+
+// resolveDescription reads Determiner Adjective* Noun+ at tokens[i] and
+// resolves it, shortening the noun run until the resolver finds a referent.
+// The tagger takes some verbs for nouns ("the wizard hid"), hence the retries.
+func resolveDescription(text string, tokens []chunker.Token, i int, res *resolver.Resolver) (ResolvedReference, bool) {
+	j := i + 1
+	for j < len(tokens) && tokens[j].POS == chunker.Adjective {
+		j++
+	}
+	end := j
+	for end < len(tokens) && tokens[end].POS == chunker.Noun {
+		end++
+	}
+	for ; end > j; end-- {
+		rng := chunker.NewRange(tokens[i].Range.Start, tokens[end-1].Range.End)
+		phrase := rng.Slice(text)
+		if id := res.ResolveDescription(phrase); id != "" {
+			return ResolvedReference{Text: phrase, EntityID: id, Range: rng}, true
+		}
+	}
+	return ResolvedReference{}, false
+}
+
+// addPossession records owner owning the entity at tokens[i], or else the
+// first noun after any adjectives
+func (cr *coreference) addPossession(tokens []chunker.Token, i int, entities []syntax.SyntaxMatch, owner string, start int) {
+	if i >= len(tokens) {
+		return
+	}
+	for _, m := range entities {
+		if m.Start == tokens[i].Range.Start {
+			cr.possessions = append(cr.possessions, Possession{
+				Owner: owner, Owned: matchID(m), Range: chunker.NewRange(start, m.End),
+			})
+			return
+		}
+	}
+	for i < len(tokens) && tokens[i].POS == chunker.Adjective {
+		i++
+	}
+	first := i
+	for i < len(tokens) && tokens[i].POS == chunker.Noun && !insideEntity(entities, tokens[i].Range) {
+		i++
+	}
+	if i == first {
+		return
+	}
+	// The tagger takes some verbs for nouns ("sword broke"), so only the first noun is kept
+	owned := tokens[first]
+	cr.possessions = append(cr.possessions, Possession{
+		Owner: owner, Owned: strings.ToLower(owned.Text), Range: chunker.NewRange(start, owned.Range.End),
+	})
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// entityMatches returns the entity matches sorted by position
+func entityMatches(matches []syntax.SyntaxMatch) []syntax.SyntaxMatch {
+	var out []syntax.SyntaxMatch
+	for _, m := range matches {
+		if m.Kind == syntax.KindEntity {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start < out[j].Start })
+	return out
+}
+
+func insideEntity(entities []syntax.SyntaxMatch, r chunker.TextRange) bool {
+	for _, m := range entities {
+		if r.Start >= m.Start && r.End <= m.End {
+			return true
+		}
+	}
+	return false
+}
+
+// definite reports a determiner that leads a description of a known entity
+func definite(word string) bool {
+	switch strings.ToLower(word) {
+	case "the", "this", "that", "these", "those":
+		return true
+	}
+	return false
+}
+
+func possessivePronoun(word string) bool {
+	switch strings.ToLower(word) {
+	case "his", "her", "its", "their":
+		return true
+	}
+	return false
+}
+
+// trimPossessive cuts a possessive 's from a name ("Mira's" -> "Mira")
+func trimPossessive(word string) (string, bool) {
+	for _, s := range []string{"'s", "’s"} {
+		if len(word) > len(s) && strings.HasSuffix(word, s) {
+			return word[:len(word)-len(s)], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,126 @@
+package conductor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+)
+
+func TestCoreferencePass(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	text := "[CHARACTER:Aria] and [CHARACTER:Tom] reached the gate. They rested. " +
+		"[CHARACTER:Aldric|King] arrived. The king spoke to Tom. Mira's sword broke. [CHARACTER:Aria]'s [ITEM:Lantern] glowed."
+	result := c.Scan(text)
+
+	refs := make(map[string]string)
+	for _, ref := range result.ResolvedRefs {
+		refs[ref.Text] = ref.EntityID
+	}
+	group := resolver.GroupID([]string{"Aria", "Tom"})
+	if refs["They"] != group {
+		t.Errorf("They should resolve to %s, got %q", group, refs["They"])
+	}
+	if refs["The king"] != "Aldric" {
+		t.Errorf("The king should resolve to Aldric, got %q", refs["The king"])
+	}
+
+	want := map[Possession]bool{
+		{Owner: "Mira", Owned: "sword"}:   false,
+		{Owner: "Aria", Owned: "Lantern"}: false,
+	}
+	for _, p := range result.Possessions {
+		key := Possession{Owner: p.Owner, Owned: p.Owned}
+		if _, ok := want[key]; ok {
+			want[key] = true
+		}
+		if got := p.Range.Slice(text); got == "" {
+			t.Errorf("possession %+v has an empty range", p)
+		}
+	}
+	for p, found := range want {
+		if !found {
+			t.Errorf("missing possession %s OWNS %s in %+v", p.Owner, p.Owned, result.Possessions)
+		}
+	}
+
+	described := false
+	for _, ev := range result.Narrative {
+		if ev.Subject == "Aldric" && strings.HasPrefix(ev.Range.Slice(text), "spoke") {
+			described = true
+		}
+	}
+	if !described {
+		t.Errorf("the king should be the subject of spoke: %+v", result.Narrative)
+	}
+}
+
+func TestCoreferenceReadsMentionsBeforeTheReference(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	// Each reference has a compatible antecedent before it and another after it
+	text := "[CHARACTER:Aria] and [CHARACTER:Tom] reached the gate. They rested. " +
+		"[CHARACTER:Bran] and [CHARACTER:Cole] followed. " +
+		"[CHARACTER:Aldric|King] arrived. The king spoke to Tom. [CHARACTER:Harold|King] waited. " +
+		"[CHARACTER:Mira] drew her sword. She smiled. [CHARACTER:Lena] watched."
+	result := c.Scan(text)
+
+	refs := make(map[string]string)
+	for _, ref := range result.ResolvedRefs {
+		refs[ref.Text] = ref.EntityID
+	}
+	if group := resolver.GroupID([]string{"Aria", "Tom"}); refs["They"] != group {
+		t.Errorf("They should resolve to %s, got %q", group, refs["They"])
+	}
+	if refs["The king"] != "Aldric" {
+		t.Errorf("The king should resolve to Aldric, got %q", refs["The king"])
+	}
+	if refs["She"] != "Mira" {
+		t.Errorf("She should resolve to Mira, got %q", refs["She"])
+	}
+
+	owned := false
+	for _, p := range result.Possessions {
+		if p.Owned == "sword" {
+			owned = p.Owner == "Mira"
+			if !owned {
+				t.Errorf("her sword should be Mira's, got %s", p.Owner)
+			}
+		}
+	}
+	if !owned {
+		t.Errorf("missing possession Mira OWNS sword in %+v", result.Possessions)
+	}
+
+	for _, ev := range result.Narrative {
+		if strings.HasPrefix(ev.Range.Slice(text), "spoke") && ev.Subject != "Aldric" {
+			t.Errorf("the king should be the subject of spoke, got %q", ev.Subject)
+		}
+	}
+}
+
+func TestCoreferenceKeepsHistoryAcrossScans(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	// The next scan continues from the last mention of the previous one
+	c.Scan("She left. [CHARACTER:Mira] stayed behind [CHARACTER:Lena].")
+	result := c.Scan("She waited.")
+	for _, ref := range result.ResolvedRefs {
+		if ref.Text == "She" && ref.EntityID != "Lena" {
+			t.Errorf("She should resolve to Lena, got %q", ref.EntityID)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package conductor
+
+import (
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
+)
+
+// maxParticipants is how many recent speakers a conversation remembers for turn-taking
+const maxParticipants = 2
+
+// dialoguePass holds what the dialogue stage found in one scan
+type dialoguePass struct {
+	utterances []dialogue.Utterance
+	quotes     []dialogue.Quote
+	tagVerbs   []chunker.TextRange // Speech verbs consumed by tags
+}
+
+// attributeDialogue finds quoted speech in each prose block and attributes it.
+// Untagged lines take the speaker tagged elsewhere in their paragraph, or
+// alternate with the previous speaker while a conversation is running.
+// The resolver's context carries the conversation across blocks and scans.
+func (s *ScanSession) attributeDialogue(text string, prose []chunker.TextRange, synMatches []syntax.SyntaxMatch, res *resolver.Resolver, mentions *timeline) dialoguePass {
+	var pass dialoguePass
+	ctx := res.Context
+
+	for _, block := range prose {
+		blockText := text[block.Start:block.End]
+		quotes := dialogue.FindQuotes(blockText)
+		if len(quotes) == 0 {
+			// Narration between lines ends the exchange
+			ctx.InDialogue = false
+			continue
+		}
+
+		// Tags first, so an untagged line can borrow its paragraph's speaker
+		type tagged struct {
+			speaker     string
+			listener    string
+			attribution dialogue.Attribution
+			verb        chunker.TextRange // The tag's verb phrase: "did not say" after the speaker
+		}
+		tags := make([]tagged, len(quotes))
+		paragraphSpeaker := ""
+		for i := range quotes {
+			tag := dialogue.FindTag(blockText, quotes, i, s.isSpeechVerb)
+			if !tag.HasSpeaker() {
+				continue
+			}
+			tag = shiftTag(tag, block.Start)
+			pass.tagVerbs = append(pass.tagVerbs, tag.Verb)
+
+			speaker := s.resolveSpeaker(text, tag.Speaker, tag.Pronoun, synMatches, res, mentions)
+			if speaker == "" {
+				continue
+			}
+			t := tagged{speaker: speaker, attribution: dialogue.ByTag, verb: tagPhrase(tag)}
+			if tag.Pronoun {
+				t.attribution = dialogue.ByPronoun
+			}
+			if tag.HasListener() {
+				t.listener = s.resolveSpeaker(text, tag.Listener, false, synMatches, res, mentions)
+			}
+			tags[i] = t
+			if paragraphSpeaker == "" {
+				paragraphSpeaker = speaker
+			}
+		}
+
+		for i, q := range quotes {
+			q.Range = shiftRange(q.Range, block.Start)
+			q.Content = shiftRange(q.Content, block.Start)
+			pass.quotes = append(pass.quotes, q)
+
+			u := dialogue.Utterance{
+				Speaker:     tags[i].speaker,
+				Listener:    tags[i].listener,
+				Range:       q.Range,
+				Content:     q.Content,
+				Attribution: tags[i].attribution,
+			}
+			if verb := tags[i].verb; !verb.IsEmpty() {
+				u.Qualifier = narrative.Qualify(verb.Slice(text), text[:verb.Start])
+			}
+			switch {
+			case u.Speaker != "":
+			case paragraphSpeaker != "":
+				u.Speaker, u.Attribution = paragraphSpeaker, dialogue.ByParagraph
+			case ctx.InDialogue && i == 0:
+				if next := otherParticipant(ctx, ctx.Speaker); next != "" {
+					u.Speaker, u.Attribution = next, dialogue.ByTurn
+				}
+			case i > 0 && pass.utterances[len(pass.utterances)-1].Speaker != dialogue.Unknown:
+				// Untagged run inside one paragraph: same speaker
+				u.Speaker, u.Attribution = pass.utterances[len(pass.utterances)-1].Speaker, dialogue.ByParagraph
+			}
+
+			if u.Speaker == "" {
+				u.Speaker = dialogue.Unknown
+			} else {
+				if u.Listener == "" {
+					u.Listener = otherParticipant(ctx, u.Speaker)
+				}
+				joinConversation(ctx, u.Speaker, u.Listener)
+				mentions.add(mention{pos: u.Range.Start, id: u.Speaker})
+			}
+			pass.utterances = append(pass.utterances, u)
+		}
+		ctx.InDialogue = true
+	}
+	return pass
+}
+
+// events converts attributed utterances into SPEAKS_TO narrative events spanning the quote.
+// Tagged lines are qualified by their tag ("Frodo did not say"); untagged ones are factual.
+func (p dialoguePass) events() []NarrativeEvent {
+	var events []NarrativeEvent
+	for _, u := range p.utterances {
+		if u.Speaker == dialogue.Unknown {
+			continue
+		}
+		object := u.Listener
+		if object == "" {
+			object = "Unknown"
+		}
+		events = append(events, NarrativeEvent{
+			Event:    narrative.EventDialogue,
+			Relation: narrative.RelSpeaksTo,
+			Subject:  u.Speaker,
+			Object:   object,
+			Range:    u.Range,
+			Polarity: u.Qualifier.Polarity,
+			Modality: u.Qualifier.Modality,
+			Tense:    u.Qualifier.Tense,
+		})
+	}
+	return events
+}
+
+// owns reports whether the dialogue stage accounts for a verb chunk:
+// speech inside quotes isn't narration, and tag verbs are already events
+func (p dialoguePass) owns(r chunker.TextRange) bool {
+	if dialogue.Within(p.quotes, r.Start, r.End) {
+		return true
+	}
+	for _, v := range p.tagVerbs {
+		if v.Overlaps(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpeechVerb uses the narrative lexicon: any verb that maps to SPEAKS_TO
+func (s *ScanSession) isSpeechVerb(word string) bool {
+	match := s.conductor.narrativeMatcher.Lookup(word)
+	return match != nil && match.RelationType == narrative.RelSpeaksTo
+}
+
+// resolveSpeaker maps a tag's name or pronoun to an entity ID.
+// Explicit and implicit entity spans win; unknown names are kept as written,
+// unresolved pronouns give "". Pronouns read the mentions before the tag.
+func (s *ScanSession) resolveSpeaker(text string, r chunker.TextRange, pronoun bool, synMatches []syntax.SyntaxMatch, res *resolver.Resolver, mentions *timeline) string {
+	for _, m := range synMatches {
+		if m.Kind == syntax.KindEntity && r.Overlaps(chunker.TextRange{Start: m.Start, End: m.End}) {
+			return matchID(m)
+		}
+	}
+	name := text[r.Start:r.End]
+	mentions.at(r.Start)
+	if id := res.Resolve(name, nil); id != "" {
+		return id
+	}
+	if pronoun {
+		return ""
+	}
+	return name
+}
+
+// Helpers
+
+// tagPhrase widens a tag's verb back to its speaker, taking in the auxiliaries
+// and negators between them ("Frodo did not say")
+func tagPhrase(t dialogue.Tag) chunker.TextRange {
+	if t.Speaker.End <= t.Verb.Start {
+		return chunker.NewRange(t.Speaker.End, t.Verb.End)
+	}
+	return t.Verb
+}
+
+func shiftTag(t dialogue.Tag, off int) dialogue.Tag {
+	t.Verb = shiftRange(t.Verb, off)
+	t.Speaker = shiftRange(t.Speaker, off)
+	if t.HasListener() {
+		t.Listener = shiftRange(t.Listener, off)
+	}
+	return t
+}
+
+// otherParticipant returns the most recent conversation participant who isn't speaker
+func otherParticipant(ctx *resolver.NarrativeContext, speaker string) string {
+	for _, id := range ctx.ActiveCharacters {
+		if id != speaker {
+			return id
+		}
+	}
+	return ""
+}
+
+// joinConversation makes speaker the current speaker and keeps the listener
+// as the other participant, most recent first
+func joinConversation(ctx *resolver.NarrativeContext, speaker, listener string) {
+	ctx.Speaker = speaker
+	participants := []string{speaker}
+	if listener != "" && listener != speaker {
+		participants = append(participants, listener)
+	}
+	for _, id := range ctx.ActiveCharacters {
+		if len(participants) >= maxParticipants {
+			break
+		}
+		if id != speaker && id != listener {
+			participants = append(participants, id)
+		}
+	}
+	ctx.ActiveCharacters = participants
+}
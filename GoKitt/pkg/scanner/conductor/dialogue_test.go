@@ -0,0 +1,126 @@
+package conductor
+
+import (
+	"testing"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/dialogue"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+)
+
+const conversation = `[CHARACTER:Frodo] looked at [CHARACTER:Sam]. "Sauron will kill us," Frodo said to Sam.
+
+"Then we walk faster."
+
+"Into Mordor?" "Yes."
+
+The hobbits walked on.
+
+"Rest now."`
+
+func TestDialogueAttribution(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	result := c.Scan(conversation)
+
+	want := []struct {
+		speaker, listener string
+		how               dialogue.Attribution
+	}{
+		{"Frodo", "Sam", dialogue.ByTag},
+		{"Sam", "Frodo", dialogue.ByTurn},
+		{"Frodo", "Sam", dialogue.ByTurn},
+		{"Frodo", "Sam", dialogue.ByParagraph},
+		{dialogue.Unknown, "", dialogue.Unattributed}, // Narration ended the exchange
+	}
+	if len(result.Dialogue) != len(want) {
+		t.Fatalf("utterances = %d, want %d", len(result.Dialogue), len(want))
+	}
+	for i, w := range want {
+		u := result.Dialogue[i]
+		if u.Speaker != w.speaker || u.Listener != w.listener || u.Attribution != w.how {
+			t.Errorf("utterance %d (%s) = %s -> %q by %s, want %s -> %q by %s", i,
+				u.Content.Slice(conversation), u.Speaker, u.Listener, u.Attribution, w.speaker, w.listener, w.how)
+		}
+	}
+	if got := result.Dialogue[0].Content.Slice(conversation); got != "Sauron will kill us," {
+		t.Errorf("first quote content = %q", got)
+	}
+
+	quoteSpans := make(map[chunker.TextRange]bool)
+	for _, u := range result.Dialogue {
+		quoteSpans[u.Range] = true
+	}
+	speaks := 0
+	for _, ev := range result.Narrative {
+		switch ev.Relation {
+		case narrative.RelKills:
+			t.Error("verbs inside quotes must not become narrative events")
+		case narrative.RelSpeaksTo:
+			speaks++
+			if !quoteSpans[ev.Range] {
+				t.Errorf("SPEAKS_TO event should span its quote, got %q", ev.Range.Slice(conversation))
+			}
+		}
+	}
+	if speaks != 4 {
+		t.Errorf("SPEAKS_TO events = %d, want 4 (one per attributed line, none for the tag verb)", speaks)
+	}
+
+	if counts := dialogue.LineCounts(result.Dialogue); counts["Frodo"] != 3 || counts["Sam"] != 1 {
+		t.Errorf("line counts = %v", counts)
+	}
+
+	for _, ch := range result.Chunks {
+		for _, q := range result.Dialogue {
+			if ch.Range.Overlaps(q.Range) && !q.Range.Contains(ch.Range) {
+				t.Errorf("chunk %q crosses quote %q", ch.Range.Slice(conversation), q.Range.Slice(conversation))
+			}
+		}
+	}
+}
+
+func TestDialogueTurnsCarryAcrossScans(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	s := c.Session("lotr")
+	s.Scan(`"Come," [CHARACTER:Gandalf] said to [CHARACTER:Pippin].`)
+	next := s.Scan(`"Where to?"`)
+	if len(next.Dialogue) != 1 || next.Dialogue[0].Speaker != "Pippin" {
+		t.Errorf("turn-taking should continue into the next scan: %+v", next.Dialogue)
+	}
+
+	reset := s.ScanWith(`"Where to?"`, ContextReset)
+	if reset.Dialogue[0].Speaker != dialogue.Unknown {
+		t.Errorf("reset context should forget the conversation, got %s", reset.Dialogue[0].Speaker)
+	}
+}
+
+func TestDialogueCarriesTagQualifier(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	result := c.Scan(`[CHARACTER:Frodo] looked at [CHARACTER:Sam]. Frodo did not say to Sam, "Run."`)
+	if len(result.Dialogue) != 1 {
+		t.Fatalf("utterances = %d, want 1", len(result.Dialogue))
+	}
+	if u := result.Dialogue[0]; u.Speaker != "Frodo" || u.Qualifier.Polarity != narrative.Negated {
+		t.Errorf("utterance = %s, %s; want Frodo, negated", u.Speaker, u.Qualifier.Polarity)
+	}
+	for _, ev := range result.Narrative {
+		if ev.Relation == narrative.RelSpeaksTo && ev.Polarity != narrative.Negated {
+			t.Errorf("SPEAKS_TO event should be negated: %+v", ev)
+		}
+	}
+}
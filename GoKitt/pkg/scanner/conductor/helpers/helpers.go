@@ -23,3 +23,24 @@ func FindNextNP(chunks []chunker.Chunk, currentIdx int) *chunker.Chunk {
 	}
 	return nil
 }
+
+// FindNextNPAfter searches forward for the nearest NounPhrase starting at or
+// after offset. A PrepPhrase whose preposition ends before offset (consumed
+// by a phrasal verb: "turned against [the king]") yields its noun phrase.
+func FindNextNPAfter(chunks []chunker.Chunk, currentIdx int, offset int) *chunker.Chunk {
+	for i := currentIdx + 1; i < len(chunks); i++ {
+		c := chunks[i]
+		switch {
+		case c.Kind == chunker.NounPhrase && c.Range.Start >= offset:
+			return &chunks[i]
+		case c.Kind == chunker.PrepPhrase && c.Head.End <= offset && len(c.Modifiers) > 0:
+			// Modifiers are the NP's head followed by its own modifiers
+			start := c.Modifiers[0].Start
+			for _, m := range c.Modifiers[1:] {
+				start = min(start, m.Start)
+			}
+			return &chunker.Chunk{Kind: chunker.NounPhrase, Range: chunker.NewRange(start, c.Range.End), Head: c.Modifiers[0]}
+		}
+	}
+	return nil
+}
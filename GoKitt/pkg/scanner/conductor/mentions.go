@@ -0,0 +1,64 @@
+package conductor
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+)
+
+// mention is an entity, or a group of entities, named at pos in the text
+type mention struct {
+	pos     int
+	id      string
+	members []string // Member IDs of a group ("Aria and Tom"); nil otherwise
+}
+
+// timeline feeds a scan's mentions to the resolver in text order, so each
+// reference is resolved against the mentions before it and none after.
+// Each pass asks in text order; a pass that starts over rewinds the history
+// to where the scan began and replays it.
+type timeline struct {
+	res      *resolver.Resolver
+	start    []string  // Pronoun history before the scan
+	mentions []mention // By position
+	next     int       // mentions[:next] have been observed
+	pos      int
+	stale    bool // A mention was added behind pos
+}
+
+func newTimeline(res *resolver.Resolver) *timeline {
+	return &timeline{res: res, start: res.Context.History()}
+}
+
+// add records a mention, observed once the timeline moves past it
+func (t *timeline) add(m mention) {
+	i := sort.Search(len(t.mentions), func(i int) bool { return t.mentions[i].pos > m.pos })
+	t.mentions = append(t.mentions, mention{})
+	copy(t.mentions[i+1:], t.mentions[i:])
+	t.mentions[i] = m
+	if i < t.next {
+		t.stale = true
+	}
+}
+
+// at brings the resolver's history up to pos: every mention before it is observed
+func (t *timeline) at(pos int) {
+	if pos < t.pos || t.stale {
+		t.res.Context.SetHistory(t.start)
+		t.next, t.stale = 0, false
+	}
+	t.pos = pos
+	for ; t.next < len(t.mentions) && t.mentions[t.next].pos < pos; t.next++ {
+		if m := t.mentions[t.next]; m.members != nil {
+			t.res.ObserveGroup(m.members)
+		} else {
+			t.res.ObserveMention(m.id)
+		}
+	}
+}
+
+// finish observes every mention, leaving the history as of the end of the text
+func (t *timeline) finish() {
+	t.at(math.MaxInt)
+}
@@ -0,0 +1,327 @@
+package conductor
+
+import (
+	"strings"
+
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
+	"github.com/kittclouds/gokitt/pkg/scanner/markdown"
+	"github.com/kittclouds/gokitt/pkg/scanner/resolver"
+	"github.com/kittclouds/gokitt/pkg/scanner/syntax"
+)
+
+// ContextMode controls how resolver context flows into a scan
+type ContextMode int
+
+const (
+	// ContextCarry continues the session's running context and keeps what the scan learns
+	ContextCarry ContextMode = iota
+	// ContextFork starts from the session's context but discards what the scan learns
+	ContextFork
+	// ContextReset starts with no pronoun history and discards what the scan learns
+	ContextReset
+)
+
+// String returns the mode name used by ParseContextMode
+func (m ContextMode) String() string {
+	switch m {
+	case ContextFork:
+		return "fork"
+	case ContextReset:
+		return "reset"
+	default:
+		return "carry"
+	}
+}
+
+// ParseContextMode converts "carry", "fork" or "reset" to a ContextMode.
+// Anything else is ContextCarry.
+func ParseContextMode(s string) ContextMode {
+	switch strings.ToLower(s) {
+	case "fork":
+		return ContextFork
+	case "reset":
+		return ContextReset
+	default:
+		return ContextCarry
+	}
+}
+
+// ScanSession holds the resolver and discovery state for one narrative.
+// Entities, pronoun history and candidate counts never cross sessions.
+type ScanSession struct {
+	conductor   *Conductor
+	narrativeID string
+	resolver    *resolver.Resolver
+	discovery   *discovery.DiscoveryEngine
+}
+
+// newSession creates an empty session; callers hold c.mu
+func (c *Conductor) newSession(narrativeID string) *ScanSession {
+	s := &ScanSession{
+		conductor:   c,
+		narrativeID: narrativeID,
+		resolver:    resolver.New(),
+		// Threshold 2 for demo
+		discovery: discovery.NewEngine(2, c.narrativeMatcher),
+	}
+	for _, token := range c.rejected {
+		s.discovery.Registry.Reject(token)
+	}
+	return s
+}
+
+// Session returns the session for a narrative, creating it on first use.
+// An empty narrative ID is the default session used by Scan.
+func (c *Conductor) Session(narrativeID string) *ScanSession {
+	if narrativeID == "" {
+		return c.defaultSession
+	}
+
+	c.mu.Lock()
+	s, ok := c.sessions[narrativeID]
+	if !ok {
+		s = c.newSession(narrativeID)
+		c.sessions[narrativeID] = s
+	}
+	seeds := c.seeds
+	c.mu.Unlock()
+
+	if !ok && len(seeds) > 0 {
+		s.SeedDiscovery(seeds)
+	}
+	return s
+}
+
+// DropSession discards a narrative's session; the next Session call starts fresh.
+// Dropping the default session replaces it with an empty one.
+func (c *Conductor) DropSession(narrativeID string) {
+	c.mu.Lock()
+	if narrativeID != "" {
+		delete(c.sessions, narrativeID)
+		c.mu.Unlock()
+		return
+	}
+	c.defaultSession = c.newSession("")
+	seeds := c.seeds
+	c.mu.Unlock()
+
+	if len(seeds) > 0 {
+		c.defaultSession.SeedDiscovery(seeds)
+	}
+}
+
+// NarrativeID returns the narrative this session is scoped to ("" for the default session)
+func (s *ScanSession) NarrativeID() string {
+	return s.narrativeID
+}
+
+// Scan processes text with the session's running context (ContextCarry)
+func (s *ScanSession) Scan(text string) ScanResult {
+	return s.scan(text, s.resolver)
+}
+
+// ScanWith processes a single note under the given context mode
+func (s *ScanSession) ScanWith(text string, mode ContextMode) ScanResult {
+	return s.scan(text, s.contextFor(mode))
+}
+
+// ScanSequence scans notes in order (e.g. the chapters of a book) so coreference
+// carries from each note into the next. The mode decides where the sequence
+// starts and whether its context is kept in the session afterwards.
+func (s *ScanSession) ScanSequence(texts []string, mode ContextMode) []ScanResult {
+	res := s.contextFor(mode)
+	results := make([]ScanResult, 0, len(texts))
+	for _, text := range texts {
+		results = append(results, s.scan(text, res))
+	}
+	return results
+}
+
+// NoteScan scans one note a part at a time (e.g. paragraph by paragraph)
+// with a single resolver context, as ScanWith scans a whole note.
+// A caller that keeps results can skip parts whose text and starting
+// context (ContextKey) are unchanged, then Resume after them.
+type NoteScan struct {
+	session  *ScanSession
+	resolver *resolver.Resolver
+	mode     ContextMode
+}
+
+// BeginNote starts a note under the given context mode
+func (s *ScanSession) BeginNote(mode ContextMode) *NoteScan {
+	return &NoteScan{session: s, resolver: s.contextFor(mode), mode: mode}
+}
+
+// Scan processes the next part of the note
+func (n *NoteScan) Scan(text string) ScanResult {
+	return n.session.scan(text, n.resolver)
+}
+
+// ContextKey fingerprints the context the next part would be scanned with
+func (n *NoteScan) ContextKey() uint64 {
+	return n.resolver.Context.Key()
+}
+
+// Snapshot returns a copy of the current context, to Resume from later
+func (n *NoteScan) Snapshot() *resolver.NarrativeContext {
+	return n.resolver.Context.Clone()
+}
+
+// Resume continues from a snapshot, e.g. the one taken after a part the
+// caller reuses instead of scanning. Under ContextCarry the session continues from it too.
+func (n *NoteScan) Resume(ctx *resolver.NarrativeContext) {
+	n.resolver = resolver.Restore(ctx)
+	if n.mode == ContextCarry {
+		n.session.resolver = n.resolver
+	}
+}
+
+// Reset clears the session's pronoun history, keeping registered entities
+func (s *ScanSession) Reset() {
+	s.resolver.ResetHistory()
+}
+
+func (s *ScanSession) contextFor(mode ContextMode) *resolver.Resolver {
+	switch mode {
+	case ContextFork:
+		return s.resolver.Fork()
+	case ContextReset:
+		fork := s.resolver.Fork()
+		fork.ResetHistory()
+		return fork
+	default:
+		return s.resolver
+	}
+}
+
+// GetCandidates returns unrelated candidates from the session's Discovery Engine
+func (s *ScanSession) GetCandidates() interface{} {
+	return s.discovery.Registry.GetCandidates()
+}
+
+// TakeChangedCandidates returns the discovery candidates changed since the last call
+func (s *ScanSession) TakeChangedCandidates() []discovery.Candidate {
+	return s.discovery.Registry.TakeDirty()
+}
+
+// GetCandidate returns one tracked discovery candidate
+func (s *ScanSession) GetCandidate(token string) (discovery.Candidate, bool) {
+	return s.discovery.Registry.GetCandidate(token)
+}
+
+// RestoreCandidates puts back candidates saved from GetCandidates,
+// e.g. when a persisted registry is loaded
+func (s *ScanSession) RestoreCandidates(candidates []discovery.Candidate) {
+	for _, c := range candidates {
+		s.discovery.Registry.Restore(c)
+	}
+}
+
+// ScanDiscovery runs the full discovery pipeline (Harvester + Virus)
+func (s *ScanSession) ScanDiscovery(text string) {
+	s.ScanDiscoveryNote("", text)
+}
+
+// ScanDiscoveryNote runs discovery over one note, recording it as the first
+// note of names not seen before
+func (s *ScanSession) ScanDiscoveryNote(noteID, text string) {
+	// Code and frontmatter are not prose
+	text = markdown.Parse(text).Mask(text)
+
+	// Phase 1: Harvester - Observe capitalized names ("Lady Mira Ashdown"),
+	// from the whole text and from its noun phrases, counted once at their longest
+	spans := []chunker.TextRange{chunker.NewRange(0, len(text))}
+	for _, chunk := range s.conductor.chunker.Chunk(text).Chunks {
+		if chunk.Kind == chunker.NounPhrase {
+			spans = append(spans, chunk.Range)
+		}
+	}
+	s.discovery.ObservePhrases(noteID, text, spans)
+
+	// Phase 2: Virus - Find relational patterns
+	s.discovery.ScanText(text)
+}
+
+// SeedDiscovery pre-populates the discovery registry with known entities
+// This gives ScanText promoted sources to work with
+func (s *ScanSession) SeedDiscovery(entities []implicitmatcher.RegisteredEntity) {
+	for _, e := range entities {
+		// Add token and force promotion
+		s.discovery.Registry.AddToken(e.Label)
+		stats := s.discovery.Registry.GetStats(e.Label)
+		if stats != nil {
+			stats.Status = discovery.StatusPromoted
+			// Parse Kind from interface{}
+			var kind implicitmatcher.EntityKind
+			switch v := e.Kind.(type) {
+			case string:
+				kind = implicitmatcher.ParseKind(v)
+			case float64:
+				kind = implicitmatcher.EntityKind(int(v))
+			case implicitmatcher.EntityKind:
+				kind = v
+			default:
+				kind = implicitmatcher.KindOther
+			}
+			stats.InferredKind = &kind
+		}
+	}
+}
+
+// Helpers
+
+func (s *ScanSession) registerExplicitEntities(res *resolver.Resolver, mentions *timeline, matches []syntax.SyntaxMatch) {
+	for _, m := range matches {
+		if m.Kind == syntax.KindEntity {
+			res.RegisterEntity(resolver.EntityMetadata{
+				ID:      m.Label,
+				Name:    m.Label,
+				Kind:    m.EntityKind,
+				Subtype: m.Subtype,
+				Aliases: []string{},
+				Gender:  genderForKind(m.EntityKind),
+			})
+			mentions.add(mention{pos: m.Start, id: m.Label})
+
+			// Also tell Discovery about it (as PROMOTED + Known Kind)
+			s.discovery.ObserveToken(m.Label)
+			// Force set kind in registry
+			kind := implicitmatcher.ParseKind(m.EntityKind)
+			s.discovery.Registry.ProposeInference(m.Label, kind)
+		}
+	}
+}
+
+// matchID is the resolver ID of an entity match: the dictionary entity an
+// implicit match chose, or a tag's label
+func matchID(m syntax.SyntaxMatch) string {
+	if m.EntityID != "" {
+		return m.EntityID
+	}
+	return m.Label
+}
+
+// genderForKind gives things and places neutral gender, so "it" can find them
+func genderForKind(kind string) resolver.Gender {
+	switch strings.ToUpper(kind) {
+	case "LOCATION", "PLACE", "OBJECT", "ITEM", "MONSTER":
+		return resolver.GenderNeutral
+	}
+	return resolver.GenderUnknown
+}
+
+func (s *ScanSession) resolveKind(text string) implicitmatcher.EntityKind {
+	// 1. Check Resolver/Explicit
+	// (Resolver tracks EntityMetadata but not DAFSA Kind directly, needs alignment)
+	// For now, assume Character if Proper Noun and unknown
+
+	// 2. Check Discovery Registry
+	stats := s.discovery.Registry.GetStats(text)
+	if stats != nil && stats.InferredKind != nil {
+		return *stats.InferredKind
+	}
+
+	return implicitmatcher.KindCharacter // Aggressive default for demo
+}
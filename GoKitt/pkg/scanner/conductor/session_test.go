@@ -0,0 +1,258 @@
+package conductor
+
+import (
+	"testing"
+
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/discovery"
+)
+
+func resolvesHe(result ScanResult, entityID string) bool {
+	for _, ref := range result.ResolvedRefs {
+		if ref.Text == "He" && ref.EntityID == entityID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSessionContextModes(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	s := c.Session("lotr")
+	s.Scan("[CHARACTER:Gandalf] traveled to [LOCATION:Mountain].")
+
+	if !resolvesHe(s.ScanWith("He defeated the balrog.", ContextFork), "Gandalf") {
+		t.Error("fork should inherit the session's pronoun history")
+	}
+	if resolvesHe(s.ScanWith("He defeated the balrog.", ContextReset), "Gandalf") {
+		t.Error("reset should start without pronoun history")
+	}
+
+	// A forked note registering Frodo must not change what "He" means later
+	s.ScanWith("[CHARACTER:Frodo] left the Shire.", ContextFork)
+	if !resolvesHe(s.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("forked scan leaked into the session")
+	}
+
+	s.Reset()
+	if resolvesHe(s.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("Reset should clear pronoun history")
+	}
+}
+
+func TestNoteScanCarriesWithinTheNote(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	s := c.Session("lotr")
+	note := s.BeginNote(ContextReset)
+	note.Scan("[CHARACTER:Gandalf] traveled to [LOCATION:Mountain].")
+	if !resolvesHe(note.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("a note's later paragraphs should see its earlier mentions")
+	}
+	if resolvesHe(s.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("a reset note leaked into the session")
+	}
+}
+
+func TestSessionsAreScopedByNarrative(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	if c.Session("") != c.defaultSession || c.Session("a") != c.Session("a") {
+		t.Fatal("Session should return the same session per narrative")
+	}
+
+	c.Session("a").Scan("[CHARACTER:Gandalf] traveled to [LOCATION:Mountain].")
+	if resolvesHe(c.Session("b").Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("pronoun history leaked across narratives")
+	}
+	if resolvesHe(c.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("pronoun history leaked into the default session")
+	}
+	if got := c.Session("b").GetCandidates(); len(got.([]discovery.Candidate)) != 0 {
+		t.Errorf("discovery candidates leaked across narratives: %v", got)
+	}
+
+	c.DropSession("a")
+	if resolvesHe(c.Session("a").Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("dropped session kept its context")
+	}
+}
+
+func TestScanSequenceCarriesAcrossNotes(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	s := c.Session("lotr")
+	chapters := []string{
+		"[CHARACTER:Gandalf] traveled to [LOCATION:Mountain].",
+		"He defeated the balrog.",
+	}
+
+	results := s.ScanSequence(chapters, ContextFork)
+	if len(results) != 2 || !resolvesHe(results[1], "Gandalf") {
+		t.Error("coreference should carry from one chapter into the next")
+	}
+	if resolvesHe(s.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("forked sequence leaked into the session")
+	}
+
+	s.ScanSequence(chapters, ContextCarry)
+	if !resolvesHe(s.Scan("He defeated the balrog."), "Gandalf") {
+		t.Error("carried sequence should update the session")
+	}
+}
+
+func TestRejectCandidatesAppliesToEverySession(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	c.Session("a").ScanDiscoveryNote("note-1", "The Dread Host marched. The Dread Host burned the fields.")
+	if got, ok := c.Session("a").GetCandidate("Dread Host"); !ok || got.FirstNote != "note-1" || got.Status != int(discovery.StatusPromoted) {
+		t.Fatalf("Dread Host = %+v", got)
+	}
+
+	c.RejectCandidates("Dread Host")
+	if got, _ := c.Session("a").GetCandidate("Dread Host"); got.Status != int(discovery.StatusIgnored) {
+		t.Errorf("existing session status = %d, want ignored", got.Status)
+	}
+
+	// A session created after the rejection never tracks the name
+	c.Session("b").ScanDiscovery("The Dread Host marched. The Dread Host burned the fields.")
+	if _, ok := c.Session("b").GetCandidate("Dread Host"); ok {
+		t.Error("new session tracked a rejected name")
+	}
+}
+
+// implicitLabel returns the label of the first match reading surface
+func implicitLabel(result ScanResult, surface string) string {
+	for _, m := range result.Syntax {
+		if m.Text == surface {
+			return m.Label
+		}
+	}
+	return ""
+}
+
+func TestImplicitMatchesAreScopedByNarrative(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	dict, err := implicitmatcher.Compile([]implicitmatcher.RegisteredEntity{
+		{ID: "ash-kanto", Label: "Ash Ketchum", Aliases: []string{"Ash"}, Kind: "CHARACTER", NarrativeID: "kanto"},
+		{ID: "brock", Label: "Brock", Kind: "CHARACTER", NarrativeID: "kanto"},
+		{ID: "ash-ember", Label: "Ash", Kind: "CHARACTER", NarrativeID: "ember"},
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	c.SetDictionary(dict)
+
+	if got := implicitLabel(c.Session("kanto").Scan("Ash smiled."), "Ash"); got != "Ash Ketchum" {
+		t.Errorf("kanto Ash = %q, want Ash Ketchum", got)
+	}
+	if got := implicitLabel(c.Session("ember").Scan("Ash smiled."), "Ash"); got != "Ash" {
+		t.Errorf("ember Ash = %q, want Ash", got)
+	}
+
+	// Unscoped, a co-occurring character settles it...
+	result := c.Scan("Brock waved. Ash smiled.")
+	if got := implicitLabel(result, "Ash"); got != "Ash Ketchum" || len(result.Ambiguities) != 0 {
+		t.Errorf("Ash next to Brock = %q, ambiguities %+v", got, result.Ambiguities)
+	}
+
+	// ...but a paragraph of its own is reported, not guessed silently
+	result = c.Session("").ScanWith("Brock waved.\n\nAsh smiled.", ContextReset)
+	if len(result.Ambiguities) != 1 {
+		t.Fatalf("ambiguities = %+v, want the lone Ash", result.Ambiguities)
+	}
+	a := result.Ambiguities[0]
+	if a.Text != "Ash" || len(a.Candidates) != 2 || a.Chosen != a.Candidates[0] {
+		t.Errorf("ambiguity = %+v", a)
+	}
+}
+
+func TestSameNamedEntitiesKeepTheirOwnHistory(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	dict, err := implicitmatcher.Compile([]implicitmatcher.RegisteredEntity{
+		{ID: "ash-kanto", Label: "Ash", Kind: "CHARACTER", NarrativeID: "kanto"},
+		{ID: "brock", Label: "Brock", Kind: "CHARACTER", NarrativeID: "kanto"},
+		{ID: "ash-ember", Label: "Ash", Kind: "CHARACTER", NarrativeID: "ember"},
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	c.SetDictionary(dict)
+
+	// Brock settles the first Ash; recency, kept per entity ID, the second
+	text := "Brock waved. Ash smiled.\n\nAsh laughed."
+	result := c.Session("").ScanWith(text, ContextReset)
+	if len(result.Ambiguities) != 0 {
+		t.Errorf("ambiguities = %+v", result.Ambiguities)
+	}
+	for _, m := range result.Syntax {
+		if m.Text == "Ash" && m.EntityID != "ash-kanto" {
+			t.Errorf("Ash at %d = %q, want ash-kanto", m.Start, m.EntityID)
+		}
+	}
+	for _, ref := range result.ResolvedRefs {
+		if ref.Text == "Ash" && ref.EntityID != "ash-kanto" {
+			t.Errorf("reference %q at %d = %q", ref.Text, ref.Range.Start, ref.EntityID)
+		}
+	}
+	if name := result.Entities["ash-kanto"]; name.Name != "Ash" || name.Kind != "CHARACTER" {
+		t.Errorf("Entities[ash-kanto] = %+v", name)
+	}
+}
+
+func TestAddSeedKeepsTheOtherSeeds(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create conductor: %v", err)
+	}
+	defer c.Close()
+
+	c.SeedDiscovery([]implicitmatcher.RegisteredEntity{
+		{ID: "e-gandalf", Label: "Gandalf", Kind: "CHARACTER"},
+		{ID: "e-frodo", Label: "Frodo", Kind: "CHARACTER"},
+	})
+	c.AddSeed(implicitmatcher.RegisteredEntity{ID: "e-shire", Label: "Shire", Kind: "PLACE"})
+	c.RemoveSeed("e-frodo")
+
+	// Sessions created later are seeded with every remaining entity
+	s := c.Session("lotr")
+	for _, label := range []string{"Gandalf", "Shire"} {
+		if got, ok := s.GetCandidate(label); !ok || got.Status != int(discovery.StatusPromoted) {
+			t.Errorf("%s should be seeded as promoted, got %+v", label, got)
+		}
+	}
+	if _, ok := s.GetCandidate("Frodo"); ok {
+		t.Error("a removed seed should not reach new sessions")
+	}
+}
@@ -0,0 +1,369 @@
+// Package dialogue finds quoted speech and the speech tags around it.
+//
+// Tag parsing is purely textual ("Frodo said", "said Frodo", "he asked
+// Sam"); resolving names to entities and filling in untagged lines by
+// turn-taking is left to the caller, which owns the narrative context.
+package dialogue
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
+)
+
+// Unknown is the speaker of a line nobody could be attributed to
+const Unknown = "Unknown"
+
+// Attribution records how an utterance got its speaker
+type Attribution uint8
+
+const (
+	Unattributed Attribution = iota
+	ByTag                    // A speech tag names the speaker ("Frodo said")
+	ByPronoun                // A speech tag uses a pronoun ("he said")
+	ByParagraph              // Another quote in the same paragraph is tagged
+	ByTurn                   // Alternation with the previous speaker
+)
+
+// String returns the attribution name
+func (a Attribution) String() string {
+	switch a {
+	case ByTag:
+		return "tag"
+	case ByPronoun:
+		return "pronoun"
+	case ByParagraph:
+		return "paragraph"
+	case ByTurn:
+		return "turn"
+	default:
+		return "none"
+	}
+}
+
+// Quote is one span of quoted speech
+type Quote struct {
+	Range   chunker.TextRange // Including the quote marks
+	Content chunker.TextRange // Between the marks
+}
+
+// Tag is the speech tag attached to a quote. Empty ranges mean "not found".
+type Tag struct {
+	Verb     chunker.TextRange
+	Speaker  chunker.TextRange
+	Listener chunker.TextRange
+	Pronoun  bool // Speaker is a pronoun
+}
+
+// HasSpeaker reports whether the tag names a speaker
+func (t Tag) HasSpeaker() bool {
+	return !t.Speaker.IsEmpty()
+}
+
+// HasListener reports whether the tag names who is addressed
+func (t Tag) HasListener() bool {
+	return !t.Listener.IsEmpty()
+}
+
+// Utterance is an attributed quote
+type Utterance struct {
+	Speaker     string // EntityID, or Unknown
+	Listener    string // EntityID, or "" when nobody is known to be addressed
+	Range       chunker.TextRange
+	Content     chunker.TextRange
+	Attribution Attribution
+	Qualifier   narrative.Qualifier // From the tag ("Frodo did not say"); factual when untagged
+}
+
+// FindQuotes returns the double-quoted spans of text in order.
+// Straight quotes pair up in order; curly quotes pair by direction.
+// Single quotes are ignored since they double as apostrophes.
+// An unclosed quote runs to the end of the text, as when a speech
+// continues into the next paragraph.
+func FindQuotes(text string) []Quote {
+	var quotes []Quote
+	open := -1
+	var openWidth int
+	for i, r := range text {
+		switch r {
+		case '"':
+			if open < 0 {
+				open, openWidth = i, 1
+			} else {
+				quotes = append(quotes, newQuote(open, openWidth, i+1, 1))
+				open = -1
+			}
+		case '“':
+			if open < 0 {
+				open, openWidth = i, utf8.RuneLen(r)
+			}
+		case '”':
+			if open >= 0 {
+				quotes = append(quotes, newQuote(open, openWidth, i+utf8.RuneLen(r), utf8.RuneLen(r)))
+				open = -1
+			}
+		}
+	}
+	if open >= 0 && len(strings.TrimSpace(text[open+openWidth:])) > 0 {
+		quotes = append(quotes, newQuote(open, openWidth, len(text), 0))
+	}
+	return quotes
+}
+
+func newQuote(start, openWidth, end, closeWidth int) Quote {
+	return Quote{
+		Range:   chunker.TextRange{Start: start, End: end},
+		Content: chunker.TextRange{Start: start + openWidth, End: end - closeWidth},
+	}
+}
+
+// Within reports whether [start, end) lies inside one of the quotes
+func Within(quotes []Quote, start, end int) bool {
+	for _, q := range quotes {
+		if q.Range.Contains(chunker.TextRange{Start: start, End: end}) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindTag reads the speech tag of quotes[i]: first the narration right after
+// the quote (`"Hi," Frodo said.`, `"Hi," said Frodo.`), then the narration
+// leading into it (`Frodo said to Sam, "Hi."`).
+// isSpeechVerb decides which words introduce speech.
+func FindTag(text string, quotes []Quote, i int, isSpeechVerb func(string) bool) Tag {
+	q := quotes[i]
+
+	// After: up to the next quote or the end of the clause
+	end := len(text)
+	if i+1 < len(quotes) {
+		end = quotes[i+1].Range.Start
+	}
+	after := text[q.Range.End:end]
+	after = after[:clauseEnd(after)]
+	if tag, ok := tagAfter(words(after, q.Range.End), isSpeechVerb); ok {
+		return tag
+	}
+
+	// Before: back to the previous quote or sentence end; must lead in with , or :
+	start := 0
+	if i > 0 {
+		start = quotes[i-1].Range.End
+	}
+	before := text[start:q.Range.Start]
+	trimmed := strings.TrimRightFunc(before, unicode.IsSpace)
+	if !strings.HasSuffix(trimmed, ",") && !strings.HasSuffix(trimmed, ":") {
+		return Tag{}
+	}
+	trimmed = trimmed[:len(trimmed)-1]
+	if cut := strings.LastIndexAny(trimmed, ".!?;\n"); cut >= 0 {
+		start += cut + 1
+		trimmed = trimmed[cut+1:]
+	}
+	return tagBefore(words(trimmed, start), isSpeechVerb)
+}
+
+// clauseEnd returns the offset of the first clause break in s, or len(s).
+// A colon only breaks when followed by space, so [KIND:Label] tags stay whole.
+func clauseEnd(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '.', '!', '?', ';', '\n':
+			return i
+		case ':':
+			if i+1 == len(s) || s[i+1] == ' ' {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// LineCounts returns the number of utterances per speaker
+func LineCounts(utterances []Utterance) map[string]int {
+	counts := make(map[string]int)
+	for _, u := range utterances {
+		counts[u.Speaker]++
+	}
+	return counts
+}
+
+// BySpeaker groups utterances by speaker, keeping text order
+func BySpeaker(utterances []Utterance) map[string][]Utterance {
+	index := make(map[string][]Utterance)
+	for _, u := range utterances {
+		index[u.Speaker] = append(index[u.Speaker], u)
+	}
+	return index
+}
+
+// =============================================================================
+// Tag parsing
+// =============================================================================
+
+type word struct {
+	text string
+	rng  chunker.TextRange
+}
+
+// words splits s into letter runs (with inner apostrophes), offset by base
+func words(s string, base int) []word {
+	var out []word
+	start := -1
+	for i, r := range s {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) ||
+			(start >= 0 && (r == '\'' || r == '’'))
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			out = append(out, word{s[start:i], chunker.TextRange{Start: base + start, End: base + i}})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, word{s[start:], chunker.TextRange{Start: base + start, End: base + len(s)}})
+	}
+	return out
+}
+
+// tagAfter matches "NAME [adverb] VERB ...", "PRONOUN VERB ..." or "VERB NAME ..."
+// at the very start of the narration after a quote.
+func tagAfter(ws []word, isSpeechVerb func(string) bool) (Tag, bool) {
+	if len(ws) == 0 {
+		return Tag{}, false
+	}
+
+	// Inverted: said Frodo / said he
+	if isSpeechVerb(ws[0].text) {
+		tag := Tag{Verb: ws[0].rng}
+		n := speakerAt(ws, 1, &tag)
+		if n == 0 {
+			return Tag{}, false
+		}
+		tag.Listener = listenerAt(ws, 1+n)
+		return tag, true
+	}
+
+	var tag Tag
+	n := speakerAt(ws, 0, &tag)
+	if n == 0 {
+		return Tag{}, false
+	}
+	v := n
+	if v < len(ws) && isAdverb(ws[v].text) {
+		v++
+	}
+	if v >= len(ws) || !isSpeechVerb(ws[v].text) {
+		return Tag{}, false
+	}
+	tag.Verb = ws[v].rng
+	tag.Listener = listenerAt(ws, v+1)
+	return tag, true
+}
+
+// tagBefore matches a clause leading into a quote: the subject is its first
+// name or pronoun, the verb its last speech verb ("Frodo turned and said,").
+func tagBefore(ws []word, isSpeechVerb func(string) bool) Tag {
+	verb := -1
+	for j := len(ws) - 1; j >= 0; j-- {
+		if isSpeechVerb(ws[j].text) {
+			verb = j
+			break
+		}
+	}
+	if verb < 0 {
+		return Tag{}
+	}
+
+	tag := Tag{Verb: ws[verb].rng}
+	for j := 0; j < verb; j++ {
+		if speakerAt(ws[:verb], j, &tag) > 0 {
+			break
+		}
+	}
+	if !tag.HasSpeaker() {
+		return Tag{}
+	}
+	tag.Listener = listenerAt(ws, verb+1)
+	return tag
+}
+
+// speakerAt reads a pronoun or a run of capitalized words at ws[i], sets
+// tag.Speaker and returns the number of words used
+func speakerAt(ws []word, i int, tag *Tag) int {
+	if i >= len(ws) {
+		return 0
+	}
+	if isSpeakerPronoun(ws[i].text) {
+		tag.Speaker = ws[i].rng
+		tag.Pronoun = true
+		return 1
+	}
+	n := nameRun(ws, i)
+	if n > 0 {
+		tag.Speaker = chunker.TextRange{Start: ws[i].rng.Start, End: ws[i+n-1].rng.End}
+		tag.Pronoun = false
+	}
+	return n
+}
+
+// listenerAt reads "to NAME" or a direct NAME object ("told Sam") at ws[i]
+func listenerAt(ws []word, i int) chunker.TextRange {
+	if i < len(ws) && strings.EqualFold(ws[i].text, "to") {
+		i++
+	}
+	if n := nameRun(ws, i); n > 0 {
+		return chunker.TextRange{Start: ws[i].rng.Start, End: ws[i+n-1].rng.End}
+	}
+	if i < len(ws) && isObjectPronoun(ws[i].text) {
+		return ws[i].rng
+	}
+	return chunker.TextRange{}
+}
+
+// nameRun counts capitalized words starting at ws[i]
+func nameRun(ws []word, i int) int {
+	n := 0
+	for i+n < len(ws) && isCapitalized(ws[i+n].text) && !isFunctionWord(ws[i+n].text) {
+		n++
+	}
+	return n
+}
+
+func isCapitalized(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
+}
+
+func isAdverb(s string) bool {
+	return len(s) > 3 && strings.HasSuffix(strings.ToLower(s), "ly")
+}
+
+func isSpeakerPronoun(s string) bool {
+	switch strings.ToLower(s) {
+	case "he", "she", "they":
+		return true
+	}
+	return false
+}
+
+func isObjectPronoun(s string) bool {
+	switch strings.ToLower(s) {
+	case "him", "her", "them":
+		return true
+	}
+	return false
+}
+
+// isFunctionWord filters capitalized words that start sentences rather than name anyone
+func isFunctionWord(s string) bool {
+	switch strings.ToLower(s) {
+	case "i", "a", "an", "the", "and", "but", "or", "then", "so", "yet",
+		"when", "while", "as", "if", "after", "before", "at", "in", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package dialogue
+
+import (
+	"strings"
+	"testing"
+)
+
+func speechVerb(w string) bool {
+	switch strings.ToLower(w) {
+	case "said", "asked", "told", "replied":
+		return true
+	}
+	return false
+}
+
+func TestFindQuotes(t *testing.T) {
+	text := `"Hello," he said. “It’s late.” Then "unclosed`
+	quotes := FindQuotes(text)
+	if len(quotes) != 3 {
+		t.Fatalf("quotes = %d", len(quotes))
+	}
+	want := []string{"Hello,", "It’s late.", "unclosed"}
+	for i, q := range quotes {
+		if got := q.Content.Slice(text); got != want[i] {
+			t.Errorf("quote %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if !Within(quotes, quotes[1].Content.Start, quotes[1].Content.End) || Within(quotes, 10, 14) {
+		t.Error("Within misreports quote membership")
+	}
+}
+
+func TestFindTag(t *testing.T) {
+	cases := []struct {
+		text     string
+		speaker  string
+		listener string
+		pronoun  bool
+	}{
+		{`"Run," Frodo said.`, "Frodo", "", false},
+		{`"Run," said Samwise Gamgee.`, "Samwise Gamgee", "", false},
+		{`"Run," she quietly said to Sam.`, "she", "Sam", true},
+		{`"Where?" Frodo asked Gandalf.`, "Frodo", "Gandalf", false},
+		{`Frodo turned to Sam and said, "Run."`, "Frodo", "", false},
+		{`Gandalf told him: "Fly, you fools."`, "Gandalf", "him", false},
+		{`"Run." Frodo left the room.`, "", "", false},
+		{`The wind said nothing. "Run."`, "", "", false},
+	}
+	for _, tc := range cases {
+		quotes := FindQuotes(tc.text)
+		tag := FindTag(tc.text, quotes, 0, speechVerb)
+		if got := tag.Speaker.Slice(tc.text); got != tc.speaker {
+			t.Errorf("%s: speaker = %q, want %q", tc.text, got, tc.speaker)
+			continue
+		}
+		if got := tag.Listener.Slice(tc.text); got != tc.listener {
+			t.Errorf("%s: listener = %q, want %q", tc.text, got, tc.listener)
+		}
+		if tag.Pronoun != tc.pronoun {
+			t.Errorf("%s: pronoun = %v", tc.text, tag.Pronoun)
+		}
+	}
+}
+
+func TestLineCounts(t *testing.T) {
+	utts := []Utterance{{Speaker: "Frodo"}, {Speaker: "Sam"}, {Speaker: "Frodo"}}
+	if got := LineCounts(utts); got["Frodo"] != 2 || got["Sam"] != 1 {
+		t.Errorf("counts = %v", got)
+	}
+	if got := BySpeaker(utts)["Frodo"]; len(got) != 2 {
+		t.Errorf("index = %v", got)
+	}
+}
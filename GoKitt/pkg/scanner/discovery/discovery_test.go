@@ -1,9 +1,11 @@
 package discovery
 
 import (
+	"strings"
 	"testing"
 
 	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 )
 
@@ -70,3 +72,157 @@ func TestDiscoveryEngine_StopWords(t *testing.T) {
 		t.Error("Stopword 'The' should not have stats")
 	}
 }
+
+func TestHarvestPhrases(t *testing.T) {
+	r := NewRegistry(2)
+	text := "The Iron Citadel fell. Lady Mira Ashdown met Dr. Varen at the Bay of Dragons, and Captain said nothing of Mira's sword."
+	want := []Phrase{
+		{Name: "Iron Citadel"},
+		{Name: "Mira Ashdown", Title: "Lady"},
+		{Name: "Varen", Title: "Dr"},
+		{Name: "Bay of Dragons"},
+		{Name: "Mira"},
+	}
+
+	got := r.HarvestPhrases(text)
+	if len(got) != len(want) {
+		t.Fatalf("phrases = %+v", got)
+	}
+	for i, p := range got {
+		if p.Name != want[i].Name || p.Title != want[i].Title {
+			t.Errorf("phrase %d = %q (title %q), want %q (title %q)", i, p.Name, p.Title, want[i].Name, want[i].Title)
+		}
+		if span := text[p.Start:p.End]; !strings.HasSuffix(span, p.Name) || !strings.HasPrefix(span, p.Title) {
+			t.Errorf("phrase %d spans %q", i, span)
+		}
+	}
+}
+
+func TestObservePhrasesPromotesMaximalPhrase(t *testing.T) {
+	matcher, err := narrative.New()
+	if err != nil {
+		t.Fatalf("Failed to create narrative matcher: %v", err)
+	}
+	defer matcher.Close()
+
+	engine := NewEngine(2, matcher)
+	engine.Registry.AddToken("Ashdown")
+
+	text := "Lady Mira Ashdown rode north."
+	whole := chunker.NewRange(0, len(text))
+	name := chunker.NewRange(5, 17) // "Mira Ashdown", as a noun phrase
+	engine.ObservePhrases("", text, []chunker.TextRange{whole, name})
+	engine.ObservePhrases("", "Then Mira Ashdown slept.", []chunker.TextRange{chunker.NewRange(0, 24)})
+
+	stats := engine.Registry.GetStats("Mira Ashdown")
+	if stats == nil || stats.Count != 2 || stats.Status != StatusPromoted {
+		t.Fatalf("Mira Ashdown = %+v", stats)
+	}
+	if len(stats.Titles) != 1 || stats.Titles[0] != "Lady" {
+		t.Errorf("titles = %v", stats.Titles)
+	}
+	if engine.Registry.GetStats("Mira") != nil || engine.Registry.GetStats("Lady") != nil {
+		t.Error("parts of the phrase were counted on their own")
+	}
+
+	var ashdown *Candidate
+	for _, c := range engine.Registry.GetCandidates() {
+		if c.Token == "Ashdown" {
+			ashdown = &c
+		}
+	}
+	if ashdown == nil || ashdown.PartOf != "Mira Ashdown" {
+		t.Errorf("Ashdown candidate = %+v", ashdown)
+	}
+}
+
+func TestDiscoveryEngine_ScanTextPhrases(t *testing.T) {
+	matcher, err := narrative.New()
+	if err != nil {
+		t.Fatalf("Failed to create narrative matcher: %v", err)
+	}
+	defer matcher.Close()
+
+	engine := NewEngine(1, matcher)
+	engine.Registry.AddToken("Mira Ashdown")
+	stats := engine.Registry.GetStats("Mira Ashdown")
+	kind := implicitmatcher.KindCharacter
+	stats.InferredKind = &kind
+
+	engine.ScanText("Lady Mira Ashdown set out for the Iron Citadel.")
+
+	target := engine.Registry.GetStats("Iron Citadel")
+	if target == nil || target.InferredKind == nil || *target.InferredKind != implicitmatcher.KindPlace {
+		t.Fatalf("Iron Citadel = %+v", target)
+	}
+}
+
+func TestRejectAndRestore(t *testing.T) {
+	engine := NewEngine(2, nil)
+	text := "Lady Mira Ashdown rode north. The Dread Host followed her."
+	engine.ObservePhrases("note-1", text, []chunker.TextRange{chunker.NewRange(0, len(text))})
+	engine.ObservePhrases("note-2", "Mira Ashdown slept.", []chunker.TextRange{chunker.NewRange(0, 19)})
+
+	mira, ok := engine.Registry.GetCandidate("Mira Ashdown")
+	if !ok || mira.FirstNote != "note-1" || mira.Status != int(StatusPromoted) {
+		t.Fatalf("Mira Ashdown = %+v", mira)
+	}
+	if want := []string{"Lady Mira Ashdown rode north.", "Mira Ashdown slept."}; !slicesEqual(mira.Contexts, want) {
+		t.Errorf("contexts = %q, want %q", mira.Contexts, want)
+	}
+
+	engine.Registry.Reject("Dread Host")
+	engine.ObservePhrases("note-3", "The Dread Host returned.", []chunker.TextRange{chunker.NewRange(0, 24)})
+	host, _ := engine.Registry.GetCandidate("Dread Host")
+	if host.Status != int(StatusIgnored) || host.Count != 1 {
+		t.Errorf("rejected candidate = %+v", host)
+	}
+
+	// A fresh registry restored from the saved candidates keeps both decisions
+	restored := NewRegistry(2)
+	for _, c := range engine.Registry.GetCandidates() {
+		restored.Restore(c)
+	}
+	if got, _ := restored.GetCandidate("Mira Ashdown"); got.Kind != mira.Kind || got.Count != 2 || !slicesEqual(got.Titles, []string{"Lady"}) {
+		t.Errorf("restored Mira Ashdown = %+v", got)
+	}
+	if restored.AddPhrase(Phrase{Name: "Dread Host"}) || restored.GetStats("Dread Host").Count != 1 {
+		t.Error("restored registry counted a rejected name")
+	}
+}
+
+func TestTakeDirtyReturnsOnlyChangedCandidates(t *testing.T) {
+	r := NewRegistry(2)
+	r.AddToken("Mira")
+	r.AddToken("Varen")
+	if got := len(r.TakeDirty()); got != 2 {
+		t.Fatalf("dirty = %d, want 2", got)
+	}
+	if got := r.TakeDirty(); len(got) != 0 {
+		t.Errorf("nothing changed, got %+v", got)
+	}
+
+	r.AddToken("Mira")
+	got := r.TakeDirty()
+	if len(got) != 1 || got[0].Token != "Mira" || got[0].Status != int(StatusPromoted) {
+		t.Errorf("dirty after a second Mira = %+v", got)
+	}
+
+	// Restored candidates match the store already
+	r.Restore(Candidate{Token: "Iron Citadel", Count: 4})
+	if got := r.TakeDirty(); len(got) != 0 {
+		t.Errorf("restored candidate reported dirty: %+v", got)
+	}
+}
+
+func slicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,12 @@
 package discovery
 
 import (
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
 	"github.com/kittclouds/gokitt/pkg/scanner/narrative"
 )
 
@@ -28,6 +31,25 @@ func (e *DiscoveryEngine) ObserveToken(token string) {
 	e.Registry.AddToken(token)
 }
 
+// ObservePhrases records the names inside each span of text (whole blocks,
+// noun-phrase chunks) once each, at their longest: phrases found by several
+// spans are merged where they overlap. Each name keeps the sentence it was
+// seen in as an example, and noteID ("" if unknown) as its first note.
+func (e *DiscoveryEngine) ObservePhrases(noteID, text string, spans []chunker.TextRange) {
+	var phrases []Phrase
+	for _, span := range spans {
+		for _, p := range e.Registry.HarvestPhrases(span.Slice(text)) {
+			p.Start += span.Start
+			p.End += span.Start
+			phrases = append(phrases, p)
+		}
+	}
+	for _, p := range e.Registry.MergePhrases(text, phrases) {
+		e.Registry.AddPhrase(p)
+		e.Registry.AddContext(p.Name, noteID, sentenceAround(text, p.Start, p.End))
+	}
+}
+
 // ObserveRelation records a relation and potentially infers target type
 func (e *DiscoveryEngine) ObserveRelation(sourceKind implicitmatcher.EntityKind, verbMatch *narrative.VerbMatch, targetToken string) {
 	// 1. Infer target kind based on source + event
@@ -39,55 +61,61 @@ func (e *DiscoveryEngine) ObserveRelation(sourceKind implicitmatcher.EntityKind,
 	}
 }
 
-// ScanText is a simple heuristic scanner (The Virus) that looks for patterns in raw text.
-// Uses TokenizeWithOffsets to properly split on punctuation.
+// ScanText is a simple heuristic scanner (The Virus) that looks for patterns in raw text:
+// a promoted name, a verb, then a new name ("Lady Mira Ashdown fought House Varen").
 func (e *DiscoveryEngine) ScanText(text string) {
-	tokenObjs := implicitmatcher.TokenizeWithOffsets(text)
-	if len(tokenObjs) < 3 {
-		return
-	}
+	phrases := e.Registry.HarvestPhrases(text)
 
-	// Extract raw tokens from text for matching
-	tokens := make([]string, len(tokenObjs))
-	for i, tok := range tokenObjs {
-		if tok.Start < len(text) && tok.End <= len(text) {
-			tokens[i] = text[tok.Start:tok.End]
-		}
-	}
-
-	for i := 0; i < len(tokens)-2; i++ {
-		sourceTok := tokens[i]
-		verbTok := tokens[i+1]
-		targetTok := tokens[i+2]
+	for i := 0; i+1 < len(phrases); i++ {
+		source, target := phrases[i], phrases[i+1]
 
 		// 1. Check Source (Must be Known & Promoted & Have Kind)
-		sourceStats := e.Registry.GetStats(sourceTok)
+		sourceStats := e.Registry.GetStats(source.Name)
 		if sourceStats == nil || sourceStats.Status != StatusPromoted || sourceStats.InferredKind == nil {
 			continue
 		}
 
-		// 2. Check Target (Must look like a candidate: Capitalized)
-		if !isCapitalized(targetTok) {
+		// 2. Check Verb: everything between the names, bar a closing article
+		gap := strings.TrimSpace(text[source.End:target.Start])
+		verbMatch, n := e.Matcher.LookupPhrase(gap)
+		if verbMatch == nil {
 			continue
 		}
-
-		// 3. Check Verb
-		verbMatch := e.Matcher.Lookup(verbTok)
-		if verbMatch == nil {
+		if rest := strings.TrimSpace(gap[n:]); rest != "" && !strings.EqualFold(rest, "the") {
 			continue
 		}
 
-		// 4. Observe Relation
-		// (Also observe the target token itself to bump its count)
-		e.Registry.AddToken(targetTok)
-		e.ObserveRelation(*sourceStats.InferredKind, verbMatch, targetTok)
+		// 3. Observe Relation
+		// (Also observe the target phrase itself to bump its count)
+		e.Registry.AddPhrase(target)
+		e.ObserveRelation(*sourceStats.InferredKind, verbMatch, target.Name)
 	}
 }
 
-func isCapitalized(s string) bool {
-	if s == "" {
-		return false
+// maxContextBytes caps each side of an example sentence around a name
+const maxContextBytes = 120
+
+// sentenceAround returns the sentence holding text[start:end], cut at line
+// breaks and clipped to maxContextBytes either side, with spaces collapsed
+func sentenceAround(text string, start, end int) string {
+	from := max(0, start-maxContextBytes)
+	if i := strings.LastIndexAny(text[from:start], ".!?\n"); i >= 0 {
+		from += i + 1
+	}
+	to := min(len(text), end+maxContextBytes)
+	if i := strings.IndexAny(text[end:to], ".!?\n"); i >= 0 {
+		to = end + i + 1
 	}
-	r := rune(s[0])
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from++
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to--
+	}
+	return strings.Join(strings.Fields(text[from:to]), " ")
+}
+
+func isCapitalized(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
 	return unicode.IsUpper(r)
 }
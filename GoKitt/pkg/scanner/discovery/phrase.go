@@ -0,0 +1,194 @@
+package discovery
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Phrase is a candidate name of one or more words, found in text
+type Phrase struct {
+	Name  string // "Mira Ashdown"
+	Title string // "Lady"; empty if the name has none
+	Start int    // Byte range of the whole phrase, title included
+	End   int
+}
+
+// titles precede a name and are linked to it rather than counted as one
+var titles = map[string]bool{
+	"lady": true, "lord": true, "sir": true, "dame": true, "madam": true,
+	"king": true, "queen": true, "prince": true, "princess": true, "emperor": true, "empress": true,
+	"duke": true, "duchess": true, "count": true, "countess": true, "baron": true, "baroness": true,
+	"captain": true, "general": true, "commander": true, "admiral": true, "sergeant": true, "lieutenant": true,
+	"master": true, "mistress": true, "father": true, "mother": true, "brother": true, "sister": true,
+	"saint": true, "st": true, "doctor": true, "dr": true, "professor": true, "prof": true,
+	"mr": true, "mrs": true, "ms": true, "miss": true, "uncle": true, "aunt": true,
+}
+
+// connectors may sit inside a name between capitalized words ("Bay of Dragons")
+var connectors = map[string]bool{
+	"of": true, "the": true, "de": true, "du": true, "von": true, "van": true,
+}
+
+// HarvestPhrases finds runs of capitalized words in text: "Iron Citadel",
+// "House Varen", "Bay of Dragons". Runs stop at punctuation and line breaks.
+// A leading title ("Lady", "Captain") becomes the phrase's Title; stopwords
+// at either end are dropped, and runs left with no name are skipped.
+func (r *CandidateRegistry) HarvestPhrases(text string) []Phrase {
+	var phrases []Phrase
+	var run []phraseWord
+	flush := func() {
+		if p, ok := r.phraseFrom(text, run); ok {
+			phrases = append(phrases, p)
+		}
+		run = run[:0]
+	}
+
+	var prev *phraseWord
+	words := phraseWords(text)
+	for i, w := range words {
+		if prev != nil && !inlineGap(*prev, text[prev.end:w.start]) {
+			flush()
+		}
+		switch {
+		case isCapitalized(w.text):
+			run = append(run, w)
+		case len(run) > 0 && connectors[strings.ToLower(w.text)]:
+			run = append(run, w)
+		default:
+			flush()
+		}
+		prev = &words[i]
+		if w.possessive {
+			flush() // "Mira's sword": the name ends here
+		}
+	}
+	flush()
+	return phrases
+}
+
+// MergePhrases unions overlapping phrases, so each name is counted once at
+// its longest. The result is in text order.
+func (r *CandidateRegistry) MergePhrases(text string, phrases []Phrase) []Phrase {
+	if len(phrases) < 2 {
+		return phrases
+	}
+	sorted := append([]Phrase(nil), phrases...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].End > sorted[j].End
+	})
+
+	merged := []Phrase{sorted[0]}
+	for _, p := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if p.Start >= last.End {
+			merged = append(merged, p)
+			continue
+		}
+		if p.End > last.End {
+			// Partial overlap: re-read the union as one phrase
+			if union := r.HarvestPhrases(text[last.Start:p.End]); len(union) == 1 {
+				u := union[0]
+				u.Start += last.Start
+				u.End += last.Start
+				*last = u
+			}
+		}
+	}
+	return merged
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+type phraseWord struct {
+	text       string // Without a possessive 's
+	start, end int
+	possessive bool
+}
+
+// phraseWords splits text into words of letters and digits with inner
+// apostrophes and hyphens. A possessive 's is cut off and flagged.
+func phraseWords(text string) []phraseWord {
+	var out []phraseWord
+	start := -1
+	emit := func(end int) {
+		w := phraseWord{text: text[start:end], start: start, end: end}
+		w.text = strings.TrimRight(w.text, "'’-")
+		for _, s := range []string{"'s", "’s"} {
+			if len(w.text) > len(s) && strings.HasSuffix(w.text, s) {
+				w.text, w.possessive = w.text[:len(w.text)-len(s)], true
+			}
+		}
+		w.end = start + len(w.text)
+		out = append(out, w)
+		start = -1
+	}
+	for i, c := range text {
+		inWord := unicode.IsLetter(c) || unicode.IsDigit(c) ||
+			(start >= 0 && (c == '\'' || c == '’' || c == '-'))
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			emit(i)
+		}
+	}
+	if start >= 0 {
+		emit(len(text))
+	}
+	return out
+}
+
+// inlineGap reports whether the text after prev keeps it in one name with
+// the next word: spaces, after the period of an abbreviated title ("Dr. Varen")
+func inlineGap(prev phraseWord, gap string) bool {
+	if strings.HasPrefix(gap, ".") && titles[strings.ToLower(prev.text)] {
+		gap = gap[1:]
+	}
+	return gap != "" && strings.Trim(gap, " \t") == ""
+}
+
+// phraseFrom turns a run of words into a phrase, splitting off a title and
+// trimming stopwords and connectors from both ends
+func (r *CandidateRegistry) phraseFrom(text string, run []phraseWord) (Phrase, bool) {
+	var title *phraseWord
+	i := 0
+	for ; i < len(run); i++ {
+		key := strings.ToLower(run[i].text)
+		if titles[key] {
+			title = &run[i]
+		} else if !r.isStopWord(key) && !connectors[key] {
+			break
+		}
+	}
+	j := len(run)
+	for j > i {
+		key := strings.ToLower(run[j-1].text)
+		if !r.isStopWord(key) && !connectors[key] && !titles[key] {
+			break
+		}
+		j--
+	}
+	if i >= j {
+		return Phrase{}, false
+	}
+
+	// A title only counts when it directly leads the name
+	if title != nil && title != &run[i-1] {
+		title = nil
+	}
+	p := Phrase{
+		Name:  text[run[i].start:run[j-1].end],
+		Start: run[i].start,
+		End:   run[j-1].end,
+	}
+	if title != nil {
+		p.Title = title.text
+		p.Start = title.start
+	}
+	return p, true
+}
@@ -78,14 +78,22 @@ type CandidateStats struct {
 	Status       CandidateStatus
 	InferredKind *implicitmatcher.EntityKind // Pointer to allow nil (unknown)
 	Display      string                      // Best display form seen
+	Titles       []string                    // Titles seen leading the name ("Lady", "Captain")
+	PartOf       CanonicalToken              // Longest phrase this word was seen inside, if any
+	FirstNote    string                      // Note the name was first seen in, when known
+	Contexts     []string                    // Up to maxContexts example sentences
 }
 
+// maxContexts caps the example sentences kept per candidate
+const maxContexts = 3
+
 // CandidateRegistry tracks potential new entities
 type CandidateRegistry struct {
 	Stats              map[CanonicalToken]*CandidateStats
 	PromotionThreshold int
-	StopWords          map[string]bool      // Custom stopwords
-	stopwordChecker    *stopwords.Stopwords // Robust English stopwords
+	StopWords          map[string]bool         // Custom stopwords
+	stopwordChecker    *stopwords.Stopwords    // Robust English stopwords
+	dirty              map[CanonicalToken]bool // Changed since the last TakeDirty
 
 	// Simplify graph for now: just track co-occurrence counts?
 	// Or just ignore for MVP.
@@ -98,6 +106,7 @@ func NewRegistry(threshold int) *CandidateRegistry {
 		PromotionThreshold: threshold,
 		StopWords:          make(map[string]bool),
 		stopwordChecker:    stopwords.MustGet("en"),
+		dirty:              make(map[CanonicalToken]bool),
 	}
 
 	// Also load our dafsa stopwords as a backup
@@ -113,29 +122,59 @@ func (r *CandidateRegistry) AddStopWord(word string) {
 	r.StopWords[strings.ToLower(word)] = true
 }
 
+// Reject ignores a token from now on: it becomes a stopword, and a tracked
+// candidate is marked StatusIgnored so it stays listed but never counts again.
+func (r *CandidateRegistry) Reject(raw string) {
+	key, _, valid := Canonicalize(raw)
+	if !valid {
+		return
+	}
+	r.AddStopWord(string(key))
+	if stats, ok := r.Stats[key]; ok {
+		stats.Status = StatusIgnored
+		r.dirty[key] = true
+	}
+}
+
 // AddToken processes a token. Returns true if promoted this time.
 func (r *CandidateRegistry) AddToken(raw string) bool {
 	key, display, valid := Canonicalize(raw)
-	if !valid {
+	if !valid || r.isStopWord(string(key)) {
 		return false
 	}
+	return r.count(key, display)
+}
 
-	// 1. Check custom stopwords map
-	if r.StopWords[string(key)] {
+// AddPhrase counts a harvested phrase under its name, links its title, and
+// points candidates for the phrase's single words at it.
+// Returns true if promoted this time.
+func (r *CandidateRegistry) AddPhrase(p Phrase) bool {
+	key, display, valid := Canonicalize(p.Name)
+	if !valid || r.isStopWord(string(key)) {
 		return false
 	}
+	promoted := r.count(key, display)
 
-	// 2. Check robust stopwords library
-	if r.stopwordChecker != nil && r.stopwordChecker.Contains(string(key)) {
-		return false
+	stats := r.Stats[key]
+	if p.Title != "" && !containsFold(stats.Titles, p.Title) {
+		stats.Titles = append(stats.Titles, p.Title)
 	}
-
-	// 3. Check NER-specific stopwords (common capitalized words)
-	if nerStopwords[string(key)] {
-		return false
+	if words := strings.Fields(string(key)); len(words) > 1 {
+		for _, w := range words {
+			if part, ok := r.Stats[CanonicalToken(w)]; ok {
+				if current, ok := r.Stats[part.PartOf]; !ok || len(current.Display) < len(display) {
+					part.PartOf = key
+					r.dirty[CanonicalToken(w)] = true
+				}
+			}
+		}
 	}
+	return promoted
+}
 
-	// 4. Get/Create stats
+// count bumps a candidate and promotes it at the threshold
+func (r *CandidateRegistry) count(key CanonicalToken, display string) bool {
+	// 1. Get/Create stats
 	stats, exists := r.Stats[key]
 	if !exists {
 		stats = &CandidateStats{
@@ -145,6 +184,7 @@ func (r *CandidateRegistry) AddToken(raw string) bool {
 		}
 		r.Stats[key] = stats
 	}
+	r.dirty[key] = true
 
 	// If already ignored/promoted, just increment
 	if stats.Status != StatusWatching {
@@ -154,7 +194,7 @@ func (r *CandidateRegistry) AddToken(raw string) bool {
 
 	stats.Count++
 
-	// 3. Check threshold
+	// 2. Check threshold
 	if stats.Count >= r.PromotionThreshold {
 		stats.Status = StatusPromoted
 		return true
@@ -163,6 +203,43 @@ func (r *CandidateRegistry) AddToken(raw string) bool {
 	return false
 }
 
+// AddContext records where a tracked candidate was seen: the first note,
+// and example sentences until maxContexts are kept
+func (r *CandidateRegistry) AddContext(raw, noteID, context string) {
+	key, _, valid := Canonicalize(raw)
+	if !valid {
+		return
+	}
+	stats, ok := r.Stats[key]
+	if !ok {
+		return
+	}
+	if stats.FirstNote == "" && noteID != "" {
+		stats.FirstNote = noteID
+		r.dirty[key] = true
+	}
+	if context != "" && len(stats.Contexts) < maxContexts && !containsFold(stats.Contexts, context) {
+		stats.Contexts = append(stats.Contexts, context)
+		r.dirty[key] = true
+	}
+}
+
+// isStopWord checks the custom, English and NER-specific stopword lists
+func (r *CandidateRegistry) isStopWord(key string) bool {
+	// 1. Check custom stopwords map
+	if r.StopWords[key] {
+		return true
+	}
+
+	// 2. Check robust stopwords library
+	if r.stopwordChecker != nil && r.stopwordChecker.Contains(key) {
+		return true
+	}
+
+	// 3. Check NER-specific stopwords (common capitalized words)
+	return nerStopwords[key]
+}
+
 // GetStatus returns the status of a token
 func (r *CandidateRegistry) GetStatus(raw string) CandidateStatus {
 	key, _, valid := Canonicalize(raw)
@@ -187,6 +264,7 @@ func (r *CandidateRegistry) ProposeInference(raw string, kind implicitmatcher.En
 		if stats.InferredKind == nil {
 			k := kind // copy value to heap
 			stats.InferredKind = &k
+			r.dirty[key] = true
 		}
 	}
 }
@@ -199,29 +277,106 @@ func (r *CandidateRegistry) GetStats(raw string) *CandidateStats {
 
 // Candidate is a public view of a discovery candidate
 type Candidate struct {
-	Token  string  `json:"token"`
-	Count  int     `json:"count"`
-	Status int     `json:"status"`
-	Kind   string  `json:"kind"`
-	Score  float64 `json:"score"`
+	Token     string   `json:"token"`
+	Count     int      `json:"count"`
+	Status    int      `json:"status"`
+	Kind      string   `json:"kind"`
+	Score     float64  `json:"score"`
+	Titles    []string `json:"titles,omitempty"`
+	PartOf    string   `json:"partOf,omitempty"` // Display form of the longer phrase holding this word
+	FirstNote string   `json:"firstNote,omitempty"`
+	Contexts  []string `json:"contexts,omitempty"`
 }
 
 // GetCandidates returns all tracked candidates
 func (r *CandidateRegistry) GetCandidates() []Candidate {
 	var list []Candidate
 	for _, stats := range r.Stats {
-		kindStr := "UNKNOWN"
-		if stats.InferredKind != nil {
-			kindStr = stats.InferredKind.String()
-		}
+		list = append(list, r.candidate(stats))
+	}
+	return list
+}
 
-		list = append(list, Candidate{
-			Token:  stats.Display,
-			Count:  stats.Count,
-			Status: int(stats.Status),
-			Kind:   kindStr,
-			Score:  float64(stats.Count),
-		})
+// GetCandidate returns the public view of one tracked candidate
+func (r *CandidateRegistry) GetCandidate(raw string) (Candidate, bool) {
+	stats := r.GetStats(raw)
+	if stats == nil {
+		return Candidate{}, false
+	}
+	return r.candidate(stats), true
+}
+
+// Restore puts back a candidate saved from GetCandidates, replacing any stats
+// already tracked for it. A rejected candidate's token becomes a stopword again.
+func (r *CandidateRegistry) Restore(c Candidate) {
+	key, display, valid := Canonicalize(c.Token)
+	if !valid {
+		return
+	}
+	stats := &CandidateStats{
+		Count:     c.Count,
+		Status:    CandidateStatus(c.Status),
+		Display:   display,
+		Titles:    c.Titles,
+		FirstNote: c.FirstNote,
+		Contexts:  c.Contexts,
+	}
+	if c.Kind != "" && c.Kind != "UNKNOWN" {
+		kind := implicitmatcher.ParseKind(c.Kind)
+		stats.InferredKind = &kind
+	}
+	if c.PartOf != "" {
+		stats.PartOf, _, _ = Canonicalize(c.PartOf)
+	}
+	r.Stats[key] = stats
+	delete(r.dirty, key) // Restored as saved
+	if stats.Status == StatusIgnored {
+		r.AddStopWord(string(key))
 	}
+}
+
+// TakeDirty returns the candidates changed since the last call, so a caller
+// persisting the registry writes only those
+func (r *CandidateRegistry) TakeDirty() []Candidate {
+	var list []Candidate
+	for key := range r.dirty {
+		if stats, ok := r.Stats[key]; ok {
+			list = append(list, r.candidate(stats))
+		}
+	}
+	r.dirty = make(map[CanonicalToken]bool)
 	return list
 }
+
+func (r *CandidateRegistry) candidate(stats *CandidateStats) Candidate {
+	kindStr := "UNKNOWN"
+	if stats.InferredKind != nil {
+		kindStr = stats.InferredKind.String()
+	}
+
+	partOf := ""
+	if phrase, ok := r.Stats[stats.PartOf]; ok {
+		partOf = phrase.Display
+	}
+
+	return Candidate{
+		Token:     stats.Display,
+		Count:     stats.Count,
+		Status:    int(stats.Status),
+		Kind:      kindStr,
+		Score:     float64(stats.Count),
+		Titles:    stats.Titles,
+		PartOf:    partOf,
+		FirstNote: stats.FirstNote,
+		Contexts:  stats.Contexts,
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, s) {
+			return true
+		}
+	}
+	return false
+}
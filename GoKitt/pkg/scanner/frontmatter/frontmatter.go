@@ -0,0 +1,253 @@
+// Package frontmatter reads the YAML frontmatter of entity notes.
+//
+// A few keys describe the entity itself (kind, subtype, aliases, title);
+// everything else becomes a typed attribute such as faction, status or born.
+package frontmatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	implicitmatcher "github.com/kittclouds/gokitt/pkg/implicit-matcher"
+	"github.com/kittclouds/gokitt/pkg/scanner/markdown"
+	"gopkg.in/yaml.v3"
+)
+
+// AttributeType is the value type of an attribute
+type AttributeType string
+
+const (
+	TypeString AttributeType = "string"
+	TypeNumber AttributeType = "number"
+	TypeBool   AttributeType = "bool"
+	TypeDate   AttributeType = "date" // Value is "2006-01-02" or RFC 3339
+	TypeList   AttributeType = "list"
+	TypeObject AttributeType = "object"
+)
+
+// Attribute is one frontmatter key that is not an entity field
+type Attribute struct {
+	Key   string        `json:"key"`
+	Type  AttributeType `json:"type"`
+	Value any           `json:"value"` // string, float64, bool, []any or map[string]any
+}
+
+// Frontmatter is the entity description found at the top of a note
+type Frontmatter struct {
+	Label      string      `json:"label,omitempty"`   // title or name
+	Kind       string      `json:"kind,omitempty"`    // Upper-cased, e.g. CHARACTER
+	Subtype    string      `json:"subtype,omitempty"` // As written
+	Aliases    []string    `json:"aliases,omitempty"`
+	Attributes []Attribute `json:"attributes,omitempty"` // Sorted by key
+}
+
+// Keys mapped onto entity fields instead of attributes (case-insensitive)
+var (
+	labelKeys   = []string{"title", "name"}
+	kindKeys    = []string{"kind"}
+	subtypeKeys = []string{"subtype"}
+	aliasKeys   = []string{"aliases", "alias"}
+)
+
+// Extract parses the frontmatter of a Markdown note.
+// Returns nil, nil when the note has none.
+func Extract(text string) (*Frontmatter, error) {
+	block := markdown.Parse(text).Frontmatter()
+	if block == nil {
+		return nil, nil
+	}
+	return Parse(block.Content.Slice(text))
+}
+
+// Parse reads a YAML mapping
+func Parse(src string) (*Frontmatter, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal([]byte(src), &raw); err != nil {
+		return nil, fmt.Errorf("parse frontmatter: %w", err)
+	}
+
+	fm := &Frontmatter{}
+	for key, value := range raw {
+		switch {
+		case matchesKey(key, labelKeys):
+			fm.Label = scalarString(value)
+		case matchesKey(key, kindKeys):
+			fm.Kind = strings.ToUpper(scalarString(value))
+		case matchesKey(key, subtypeKeys):
+			fm.Subtype = scalarString(value)
+		case matchesKey(key, aliasKeys):
+			fm.Aliases = append(fm.Aliases, stringList(value)...)
+		default:
+			if value == nil {
+				continue
+			}
+			typ, v := typedValue(value)
+			fm.Attributes = append(fm.Attributes, Attribute{Key: key, Type: typ, Value: v})
+		}
+	}
+
+	fm.Aliases = cleanAliases(fm.Aliases, fm.Label)
+	sort.Slice(fm.Attributes, func(i, j int) bool {
+		return fm.Attributes[i].Key < fm.Attributes[j].Key
+	})
+	return fm, nil
+}
+
+// IsEntity reports whether the frontmatter declares an entity kind
+func (f *Frontmatter) IsEntity() bool {
+	return f != nil && f.Kind != ""
+}
+
+// Attribute returns the attribute with the given key, or nil
+func (f *Frontmatter) Attribute(key string) *Attribute {
+	for i := range f.Attributes {
+		if strings.EqualFold(f.Attributes[i].Key, key) {
+			return &f.Attributes[i]
+		}
+	}
+	return nil
+}
+
+// Registered builds dictionary input, so declared aliases become match patterns.
+// label is used when the frontmatter has no title of its own.
+func (f *Frontmatter) Registered(id, label, narrativeID string) implicitmatcher.RegisteredEntity {
+	if f.Label != "" {
+		label = f.Label
+	}
+	return implicitmatcher.RegisteredEntity{
+		ID:          id,
+		Label:       label,
+		Aliases:     cleanAliases(f.Aliases, label),
+		Kind:        f.Kind,
+		NarrativeID: narrativeID,
+	}
+}
+
+// MergeAliases returns existing plus any new aliases, without duplicates
+// (case-insensitive) and in first-seen order
+func MergeAliases(existing, added []string) []string {
+	out := make([]string, 0, len(existing)+len(added))
+	seen := make(map[string]bool, len(existing)+len(added))
+	for _, list := range [][]string{existing, added} {
+		for _, alias := range list {
+			key := strings.ToLower(strings.TrimSpace(alias))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, strings.TrimSpace(alias))
+		}
+	}
+	return out
+}
+
+// Helpers
+
+func matchesKey(key string, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func scalarString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(x)
+	case time.Time:
+		return formatDate(x)
+	default:
+		return strings.TrimSpace(fmt.Sprint(x))
+	}
+}
+
+// stringList accepts a list or a single comma-separated string
+func stringList(v any) []string {
+	switch x := v.(type) {
+	case []any:
+		out := make([]string, 0, len(x))
+		for _, item := range x {
+			out = append(out, scalarString(item))
+		}
+		return out
+	case string:
+		return strings.Split(x, ",")
+	default:
+		return []string{scalarString(v)}
+	}
+}
+
+func cleanAliases(aliases []string, label string) []string {
+	out := MergeAliases(nil, aliases)
+	if label == "" {
+		return out
+	}
+	kept := out[:0]
+	for _, alias := range out {
+		if !strings.EqualFold(alias, label) {
+			kept = append(kept, alias)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// typedValue classifies a YAML value and normalizes it to JSON-friendly types
+func typedValue(v any) (AttributeType, any) {
+	switch x := v.(type) {
+	case bool:
+		return TypeBool, x
+	case int:
+		return TypeNumber, float64(x)
+	case int64:
+		return TypeNumber, float64(x)
+	case uint64:
+		return TypeNumber, float64(x)
+	case float64:
+		return TypeNumber, x
+	case time.Time:
+		return TypeDate, formatDate(x)
+	case string:
+		if isDate(x) {
+			return TypeDate, x
+		}
+		return TypeString, x
+	case []any:
+		list := make([]any, len(x))
+		for i, item := range x {
+			_, list[i] = typedValue(item)
+		}
+		return TypeList, list
+	case map[string]any:
+		obj := make(map[string]any, len(x))
+		for k, item := range x {
+			_, obj[k] = typedValue(item)
+		}
+		return TypeObject, obj
+	default:
+		return TypeString, fmt.Sprint(x)
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+func isDate(s string) bool {
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
@@ -0,0 +1,96 @@
+package frontmatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+const aragorn = `---
+title: Aragorn
+kind: character
+subtype: Ranger
+aliases: [Strider, Elessar, aragorn]
+faction: Dúnedain
+status: alive
+born: 2931-03-01
+height: 1.98
+crowned: true
+titles:
+  - King of Gondor
+  - 1
+---
+# Aragorn
+
+Aragorn son of Arathorn.
+`
+
+func TestExtract(t *testing.T) {
+	fm, err := Extract(aragorn)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if fm.Label != "Aragorn" || fm.Kind != "CHARACTER" || fm.Subtype != "Ranger" || !fm.IsEntity() {
+		t.Errorf("entity fields = %+v", fm)
+	}
+	if !reflect.DeepEqual(fm.Aliases, []string{"Strider", "Elessar"}) {
+		t.Errorf("aliases = %v", fm.Aliases)
+	}
+
+	want := []Attribute{
+		{Key: "born", Type: TypeDate, Value: "2931-03-01"},
+		{Key: "crowned", Type: TypeBool, Value: true},
+		{Key: "faction", Type: TypeString, Value: "Dúnedain"},
+		{Key: "height", Type: TypeNumber, Value: 1.98},
+		{Key: "status", Type: TypeString, Value: "alive"},
+		{Key: "titles", Type: TypeList, Value: []any{"King of Gondor", float64(1)}},
+	}
+	if !reflect.DeepEqual(fm.Attributes, want) {
+		t.Errorf("attributes = %+v", fm.Attributes)
+	}
+	if a := fm.Attribute("Faction"); a == nil || a.Value != "Dúnedain" {
+		t.Errorf("Attribute lookup = %+v", a)
+	}
+}
+
+func TestExtractWithoutFrontmatter(t *testing.T) {
+	fm, err := Extract("# Just a note\n\n---\nkind: character\n---")
+	if fm != nil || err != nil {
+		t.Errorf("expected nil, nil; got %+v, %v", fm, err)
+	}
+
+	if _, err := Extract("---\n[unclosed\n---\n"); err == nil {
+		t.Error("expected a YAML error")
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	fm, err := Parse("kind: place\nalias: Minas Tirith, the White City")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	e := fm.Registered("e1", "Minas Tirith", "n1")
+	if e.Label != "Minas Tirith" || e.Kind != "PLACE" || e.NarrativeID != "n1" {
+		t.Errorf("registered = %+v", e)
+	}
+	if !reflect.DeepEqual(e.Aliases, []string{"the White City"}) {
+		t.Errorf("aliases = %v", e.Aliases)
+	}
+
+	merged := MergeAliases([]string{"Strider"}, []string{"strider", "Elessar", " "})
+	if !reflect.DeepEqual(merged, []string{"Strider", "Elessar"}) {
+		t.Errorf("merged = %v", merged)
+	}
+}
+
+func TestTypeIsAnAttribute(t *testing.T) {
+	fm, err := Parse("title: Meeting notes\ntype: journal")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if fm.IsEntity() {
+		t.Errorf("type should not make an entity: %+v", fm)
+	}
+	if a := fm.Attribute("type"); a == nil || a.Value != "journal" {
+		t.Errorf("type attribute = %+v", a)
+	}
+}
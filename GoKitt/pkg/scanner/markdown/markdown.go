@@ -0,0 +1,874 @@
+// Package markdown finds the block structure of a Markdown note.
+//
+// It is a small line-based parser, not a full CommonMark implementation: it
+// recognizes ATX and setext headings, bullet and ordered lists, blockquotes,
+// fenced code blocks, GFM tables, thematic breaks and YAML frontmatter, which
+// is enough to keep code and metadata out of the NLP pipeline and to give the
+// CST real sections. All offsets are byte offsets into the original text.
+package markdown
+
+import (
+	"strings"
+
+	"github.com/kittclouds/gokitt/pkg/scanner/chunker"
+)
+
+// BlockKind is the type of a Markdown block
+type BlockKind int
+
+const (
+	BlockParagraph BlockKind = iota
+	BlockSection             // A heading and everything up to the next heading of the same or higher level
+	BlockHeading
+	BlockList
+	BlockListItem
+	BlockQuote
+	BlockCode // Fenced code block
+	BlockTable
+	BlockTableRow
+	BlockTableCell
+	BlockFrontmatter
+)
+
+// String returns a readable name
+func (k BlockKind) String() string {
+	switch k {
+	case BlockParagraph:
+		return "Paragraph"
+	case BlockSection:
+		return "Section"
+	case BlockHeading:
+		return "Heading"
+	case BlockList:
+		return "List"
+	case BlockListItem:
+		return "ListItem"
+	case BlockQuote:
+		return "BlockQuote"
+	case BlockCode:
+		return "Code"
+	case BlockTable:
+		return "Table"
+	case BlockTableRow:
+		return "TableRow"
+	case BlockTableCell:
+		return "TableCell"
+	case BlockFrontmatter:
+		return "Frontmatter"
+	default:
+		return "Unknown"
+	}
+}
+
+// Block is one node of the block tree
+type Block struct {
+	Kind     BlockKind
+	Range    chunker.TextRange // Whole block, markers included
+	Content  chunker.TextRange // Text inside the markers (heading text, code body, frontmatter body, cell text)
+	Level    int               // Heading and section level (1-6)
+	Children []*Block
+}
+
+// IsProse reports whether the block holds running text (paragraphs, heading text, table cells)
+func (b *Block) IsProse() bool {
+	return b.Kind == BlockParagraph || b.Kind == BlockHeading || b.Kind == BlockTableCell
+}
+
+// Document is a parsed note
+type Document struct {
+	Blocks []*Block            // Top-level blocks; headings are nested into sections
+	Markup []chunker.TextRange // Markers that are not prose: #, >, list bullets, table pipes, rules
+	Code   []chunker.TextRange // Inline code spans
+}
+
+// Parse builds the block tree of a note
+func Parse(text string) *Document {
+	p := &parser{text: text}
+	doc := &Document{Blocks: nestSections(p.parseTop())}
+	doc.Markup = p.markup
+
+	Walk(doc.Blocks, func(b *Block) {
+		if b.IsProse() {
+			doc.Code = append(doc.Code, inlineCode(text, b.Content)...)
+		}
+	})
+	return doc
+}
+
+// SplitBlocks returns the ranges of the top-level blocks without nesting
+// sections, so each range can be parsed on its own with the same result.
+// Unlike a split on blank lines, it never cuts through code or frontmatter.
+func SplitBlocks(text string) []chunker.TextRange {
+	p := &parser{text: text}
+	blocks := p.parseTop()
+	ranges := make([]chunker.TextRange, len(blocks))
+	for i, b := range blocks {
+		ranges[i] = b.Range
+	}
+	return ranges
+}
+
+// Walk visits blocks depth-first in document order
+func Walk(blocks []*Block, fn func(*Block)) {
+	for _, b := range blocks {
+		fn(b)
+		Walk(b.Children, fn)
+	}
+}
+
+// Frontmatter returns the YAML frontmatter block, or nil
+func (d *Document) Frontmatter() *Block {
+	if len(d.Blocks) > 0 && d.Blocks[0].Kind == BlockFrontmatter {
+		return d.Blocks[0]
+	}
+	return nil
+}
+
+// Prose returns the ranges holding running text, in document order.
+// Each range is a separate unit for chunking; markers inside it are masked.
+func (d *Document) Prose() []chunker.TextRange {
+	var ranges []chunker.TextRange
+	Walk(d.Blocks, func(b *Block) {
+		if b.IsProse() && !b.Content.IsEmpty() {
+			ranges = append(ranges, b.Content)
+		}
+	})
+	return ranges
+}
+
+// Mask returns text with code, frontmatter and markers replaced by spaces.
+// Offsets and line breaks are preserved, so results map straight back to text.
+func (d *Document) Mask(text string) string {
+	buf := []byte(text)
+	blank := func(r chunker.TextRange) {
+		for i := r.Start; i < r.End && i < len(buf); i++ {
+			if buf[i] != '\n' && buf[i] != '\r' {
+				buf[i] = ' '
+			}
+		}
+	}
+
+	Walk(d.Blocks, func(b *Block) {
+		if b.Kind == BlockCode || b.Kind == BlockFrontmatter {
+			blank(b.Range)
+		}
+	})
+	for _, r := range d.Markup {
+		blank(r)
+	}
+	for _, r := range d.Code {
+		blank(r)
+	}
+	return string(buf)
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+// line is the content of one source line after container prefixes are stripped.
+// [start, end) excludes the line break.
+type line struct {
+	start, end int
+}
+
+type parser struct {
+	text   string
+	markup []chunker.TextRange
+}
+
+func (p *parser) str(l line) string {
+	return p.text[l.start:l.end]
+}
+
+func (p *parser) mark(start, end int) {
+	if end > start {
+		p.markup = append(p.markup, chunker.NewRange(start, end))
+	}
+}
+
+// parseTop parses the whole text, starting with optional frontmatter
+func (p *parser) parseTop() []*Block {
+	lines := splitLines(p.text)
+	var blocks []*Block
+	if fm, next := p.frontmatter(lines); fm != nil {
+		blocks = append(blocks, fm)
+		lines = lines[next:]
+	}
+	return append(blocks, p.parseBlocks(lines)...)
+}
+
+// frontmatter recognizes a YAML block delimited by --- at the very start of the note
+func (p *parser) frontmatter(lines []line) (*Block, int) {
+	if len(lines) < 2 || lines[0].start != 0 || strings.TrimRight(p.str(lines[0]), " \t") != "---" {
+		return nil, 0
+	}
+	for j := 1; j < len(lines); j++ {
+		s := strings.TrimRight(p.str(lines[j]), " \t")
+		if s != "---" && s != "..." {
+			continue
+		}
+		content := chunker.NewRange(lines[j].start, lines[j].start)
+		if j > 1 {
+			content = chunker.NewRange(lines[1].start, lines[j-1].end)
+		}
+		return &Block{
+			Kind:    BlockFrontmatter,
+			Range:   chunker.NewRange(0, lines[j].end),
+			Content: content,
+		}, j + 1
+	}
+	return nil, 0
+}
+
+func (p *parser) parseBlocks(lines []line) []*Block {
+	var blocks []*Block
+	for i := 0; i < len(lines); {
+		s := p.str(lines[i])
+		switch {
+		case isBlank(s):
+			i++
+		case fenceOpen(s) != "":
+			var b *Block
+			b, i = p.parseFence(lines, i)
+			blocks = append(blocks, b)
+		case headingLevel(s) > 0:
+			blocks = append(blocks, p.parseHeading(lines[i]))
+			i++
+		case isThematicBreak(s):
+			ind := indentWidth(s)
+			p.mark(lines[i].start+ind, lines[i].end)
+			i++
+		case isQuote(s):
+			var b *Block
+			b, i = p.parseQuote(lines, i)
+			blocks = append(blocks, b)
+		case listMarker(s) > 0:
+			var b *Block
+			b, i = p.parseList(lines, i)
+			blocks = append(blocks, b)
+		case i+1 < len(lines) && p.isTableStart(lines[i], lines[i+1]):
+			var b *Block
+			b, i = p.parseTable(lines, i)
+			blocks = append(blocks, b)
+		default:
+			var b *Block
+			b, i = p.parseParagraph(lines, i)
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func (p *parser) parseFence(lines []line, i int) (*Block, int) {
+	s := p.str(lines[i])
+	ind := indentWidth(s)
+	fence := fenceOpen(s)
+
+	end := len(lines)
+	for j := i + 1; j < len(lines); j++ {
+		t := p.str(lines[j])
+		if indentWidth(t) <= 3 && isFenceClose(strings.TrimLeft(t, " "), fence) {
+			end = j
+			break
+		}
+	}
+
+	b := &Block{Kind: BlockCode}
+	last := end
+	if end == len(lines) {
+		last = len(lines) - 1 // Unclosed: runs to the end of the container
+	}
+	b.Range = chunker.NewRange(lines[i].start+ind, lines[last].end)
+	if end > i+1 {
+		b.Content = chunker.NewRange(lines[i+1].start, lines[end-1].end)
+	} else {
+		b.Content = chunker.NewRange(lines[i].end, lines[i].end)
+	}
+	return b, last + 1
+}
+
+func (p *parser) parseHeading(l line) *Block {
+	s := p.str(l)
+	ind := indentWidth(s)
+	level := headingLevel(s)
+	start := l.start + ind
+
+	p.mark(start, start+level)
+
+	// Content: skip spaces after the hashes, drop an optional closing sequence
+	cs := start + level
+	for cs < l.end && isSpace(p.text[cs]) {
+		cs++
+	}
+	ce := l.end
+	for ce > cs && isSpace(p.text[ce-1]) {
+		ce--
+	}
+	if closing := ce; closing > cs {
+		for closing > cs && p.text[closing-1] == '#' {
+			closing--
+		}
+		if closing < ce && (closing == cs || isSpace(p.text[closing-1])) {
+			p.mark(closing, ce)
+			ce = closing
+			for ce > cs && isSpace(p.text[ce-1]) {
+				ce--
+			}
+		}
+	}
+
+	return &Block{
+		Kind:    BlockHeading,
+		Range:   chunker.NewRange(start, l.end),
+		Content: chunker.NewRange(cs, ce),
+		Level:   level,
+	}
+}
+
+func (p *parser) parseParagraph(lines []line, i int) (*Block, int) {
+	j := i + 1
+	for j < len(lines) {
+		s := p.str(lines[j])
+		if isBlank(s) {
+			break
+		}
+		// Setext heading: the paragraph so far becomes a heading
+		if level := setextLevel(s); level > 0 {
+			ind := indentWidth(s)
+			p.mark(lines[j].start+ind, lines[j].end)
+			return &Block{
+				Kind:    BlockHeading,
+				Range:   chunker.NewRange(lines[i].start+indentWidth(p.str(lines[i])), lines[j].end),
+				Content: p.trimmed(lines[i], lines[j-1]),
+				Level:   level,
+			}, j + 1
+		}
+		if p.interruptsParagraph(s) {
+			break
+		}
+		j++
+	}
+
+	r := p.trimmed(lines[i], lines[j-1])
+	return &Block{Kind: BlockParagraph, Range: r, Content: r}, j
+}
+
+// trimmed returns the range from the first to the last line without outer whitespace
+func (p *parser) trimmed(first, last line) chunker.TextRange {
+	start, end := first.start, last.end
+	for start < end && isSpace(p.text[start]) {
+		start++
+	}
+	for end > start && isSpace(p.text[end-1]) {
+		end--
+	}
+	return chunker.NewRange(start, end)
+}
+
+func (p *parser) interruptsParagraph(s string) bool {
+	if fenceOpen(s) != "" || headingLevel(s) > 0 || isThematicBreak(s) || isQuote(s) {
+		return true
+	}
+	// Only bullets and lists starting at 1 interrupt a paragraph, so prose
+	// like "1984. It was cold" wrapped onto a new line stays a paragraph
+	if n := listMarker(s); n > 0 {
+		rest := strings.TrimLeft(s, " ")
+		marker := rest[:n]
+		return !isDigit(marker[0]) || marker[:len(marker)-1] == "1"
+	}
+	return false
+}
+
+func (p *parser) parseQuote(lines []line, i int) (*Block, int) {
+	var inner []line
+	start := -1
+	end := i
+	for j := i; j < len(lines); j++ {
+		s := p.str(lines[j])
+		if isQuote(s) {
+			pos := lines[j].start + indentWidth(s)
+			if start < 0 {
+				start = pos
+			}
+			p.mark(pos, pos+1)
+			cs := pos + 1
+			if cs < lines[j].end && p.text[cs] == ' ' {
+				cs++
+			}
+			inner = append(inner, line{cs, lines[j].end})
+			end = j + 1
+			continue
+		}
+		// Lazy continuation of a quoted paragraph
+		if len(inner) > 0 && !isBlank(s) && !isBlank(p.str(inner[len(inner)-1])) && !p.interruptsParagraph(s) {
+			inner = append(inner, lines[j])
+			end = j + 1
+			continue
+		}
+		break
+	}
+
+	return &Block{
+		Kind:     BlockQuote,
+		Range:    chunker.NewRange(start, lines[end-1].end),
+		Content:  chunker.NewRange(inner[0].start, lines[end-1].end),
+		Children: p.parseBlocks(inner),
+	}, end
+}
+
+func (p *parser) parseList(lines []line, i int) (*Block, int) {
+	list := &Block{Kind: BlockList}
+	first := strings.TrimLeft(p.str(lines[i]), " ")
+	bullet := listKind(first[:listMarker(first)])
+
+	for i < len(lines) {
+		s := p.str(lines[i])
+		if listMarker(s) == 0 || listKind(strings.TrimLeft(s, " ")[:listMarker(s)]) != bullet {
+			break
+		}
+		var item *Block
+		item, i = p.parseListItem(lines, i)
+		list.Children = append(list.Children, item)
+
+		// Blank lines between items keep the list going (a loose list)
+		k := i
+		for k < len(lines) && isBlank(p.str(lines[k])) {
+			k++
+		}
+		if k < len(lines) && k > i {
+			t := p.str(lines[k])
+			if listMarker(t) > 0 && listKind(strings.TrimLeft(t, " ")[:listMarker(t)]) == bullet {
+				i = k
+			}
+		}
+	}
+
+	list.Range = chunker.NewRange(list.Children[0].Range.Start, list.Children[len(list.Children)-1].Range.End)
+	list.Content = list.Range
+	return list, i
+}
+
+func (p *parser) parseListItem(lines []line, i int) (*Block, int) {
+	s := p.str(lines[i])
+	ind := indentWidth(s)
+	markerLen := listMarker(s)
+	start := lines[i].start + ind
+	p.mark(start, start+markerLen)
+
+	// Content column: marker plus 1-4 spaces
+	spaces := 0
+	for k := ind + markerLen; k < len(s) && s[k] == ' '; k++ {
+		spaces++
+	}
+	if spaces == 0 || spaces > 4 || ind+markerLen+spaces >= len(s) {
+		spaces = 1
+	}
+	width := ind + markerLen + spaces
+	cs := lines[i].start + width
+	if cs > lines[i].end {
+		cs = lines[i].end
+	}
+
+	inner := []line{{cs, lines[i].end}}
+	last := i
+	j := i + 1
+	for j < len(lines) {
+		t := p.str(lines[j])
+		if isBlank(t) {
+			// Blank lines belong to the item only if indented content follows
+			k := j
+			for k < len(lines) && isBlank(p.str(lines[k])) {
+				k++
+			}
+			if k == len(lines) || indentWidth(p.str(lines[k])) < width {
+				break
+			}
+			for ; j < k; j++ {
+				inner = append(inner, line{lines[j].start, lines[j].start})
+			}
+			continue
+		}
+		if indentWidth(t) >= width {
+			inner = append(inner, line{lines[j].start + width, lines[j].end})
+			last = j
+			j++
+			continue
+		}
+		// Lazy continuation of the item's paragraph
+		if !isBlank(p.str(inner[len(inner)-1])) && listMarker(t) == 0 && !p.interruptsParagraph(t) {
+			inner = append(inner, lines[j])
+			last = j
+			j++
+			continue
+		}
+		break
+	}
+
+	return &Block{
+		Kind:     BlockListItem,
+		Range:    chunker.NewRange(start, lines[last].end),
+		Content:  chunker.NewRange(cs, lines[last].end),
+		Children: p.parseBlocks(inner),
+	}, last + 1
+}
+
+func (p *parser) isTableStart(header, delim line) bool {
+	h := p.str(header)
+	if !strings.Contains(h, "|") || indentWidth(h) > 3 {
+		return false
+	}
+	cells, _ := p.splitCells(header)
+	n := delimiterCells(p.str(delim))
+	return n > 0 && n == len(cells)
+}
+
+func (p *parser) parseTable(lines []line, i int) (*Block, int) {
+	table := &Block{Kind: BlockTable}
+	table.Children = append(table.Children, p.parseRow(lines[i]))
+
+	d := p.str(lines[i+1])
+	p.mark(lines[i+1].start+indentWidth(d), lines[i+1].end)
+
+	j := i + 2
+	for j < len(lines) {
+		s := p.str(lines[j])
+		if isBlank(s) || !strings.Contains(s, "|") || p.interruptsParagraph(s) {
+			break
+		}
+		table.Children = append(table.Children, p.parseRow(lines[j]))
+		j++
+	}
+
+	table.Range = chunker.NewRange(table.Children[0].Range.Start, table.Children[len(table.Children)-1].Range.End)
+	table.Content = table.Range
+	return table, j
+}
+
+func (p *parser) parseRow(l line) *Block {
+	row := &Block{Kind: BlockTableRow, Range: p.trimmed(l, l)}
+	row.Content = row.Range
+	cells, pipes := p.splitCells(l)
+	for _, pipe := range pipes {
+		p.mark(pipe, pipe+1)
+	}
+	for _, cell := range cells {
+		if !cell.IsEmpty() {
+			row.Children = append(row.Children, &Block{Kind: BlockTableCell, Range: cell, Content: cell})
+		}
+	}
+	return row
+}
+
+// splitCells splits a table row on unescaped pipes, returning trimmed cell
+// ranges and the pipe offsets
+func (p *parser) splitCells(l line) ([]chunker.TextRange, []int) {
+	r := p.trimmed(l, l)
+	var cells []chunker.TextRange
+	var pipes []int
+	cellStart := r.Start
+	for k := r.Start; k < r.End; k++ {
+		if p.text[k] == '\\' {
+			k++
+			continue
+		}
+		if p.text[k] != '|' {
+			continue
+		}
+		pipes = append(pipes, k)
+		if k > r.Start {
+			cells = append(cells, p.trimmed(line{cellStart, k}, line{cellStart, k}))
+		}
+		cellStart = k + 1
+	}
+	if cellStart < r.End {
+		cells = append(cells, p.trimmed(line{cellStart, r.End}, line{cellStart, r.End}))
+	}
+	return cells, pipes
+}
+
+// nestSections wraps each top-level heading and the blocks after it into a
+// section, closing sections when a heading of the same or higher level starts.
+func nestSections(blocks []*Block) []*Block {
+	var out []*Block
+	var open []*Block
+	attach := func(b *Block) {
+		if len(open) == 0 {
+			out = append(out, b)
+			return
+		}
+		parent := open[len(open)-1]
+		parent.Children = append(parent.Children, b)
+	}
+
+	for _, b := range blocks {
+		if b.Kind != BlockHeading {
+			attach(b)
+			continue
+		}
+		for len(open) > 0 && open[len(open)-1].Level >= b.Level {
+			open = open[:len(open)-1]
+		}
+		section := &Block{Kind: BlockSection, Level: b.Level, Children: []*Block{b}}
+		attach(section)
+		open = append(open, section)
+	}
+
+	for _, b := range out {
+		fitSection(b)
+	}
+	return out
+}
+
+// fitSection sets section ranges to cover their children
+func fitSection(b *Block) {
+	if b.Kind != BlockSection {
+		return
+	}
+	for _, child := range b.Children {
+		fitSection(child)
+	}
+	b.Range = chunker.NewRange(b.Children[0].Range.Start, b.Children[len(b.Children)-1].Range.End)
+	b.Content = b.Range
+}
+
+// inlineCode finds `code` spans: a backtick run closed by a run of the same length
+func inlineCode(text string, r chunker.TextRange) []chunker.TextRange {
+	var spans []chunker.TextRange
+	for i := r.Start; i < r.End; {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		n := backtickRun(text, i, r.End)
+		closed := false
+		for j := i + n; j < r.End; {
+			if text[j] != '`' {
+				j++
+				continue
+			}
+			m := backtickRun(text, j, r.End)
+			if m == n {
+				spans = append(spans, chunker.NewRange(i, j+m))
+				i = j + m
+				closed = true
+				break
+			}
+			j += m
+		}
+		if !closed {
+			i += n
+		}
+	}
+	return spans
+}
+
+func backtickRun(text string, i, end int) int {
+	n := 0
+	for i+n < end && text[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// ============================================================================
+// Line classification
+// ============================================================================
+
+func splitLines(text string) []line {
+	var lines []line
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		if i < len(text) && text[i] != '\n' {
+			continue
+		}
+		end := i
+		if end > start && text[end-1] == '\r' {
+			end--
+		}
+		if i < len(text) || start < len(text) {
+			lines = append(lines, line{start, end})
+		}
+		start = i + 1
+	}
+	return lines
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// indentWidth counts leading spaces
+func indentWidth(s string) int {
+	n := 0
+	for n < len(s) && s[n] == ' ' {
+		n++
+	}
+	return n
+}
+
+// fenceOpen returns the opening fence (``` or ~~~, possibly longer) or ""
+func fenceOpen(s string) string {
+	if indentWidth(s) > 3 {
+		return ""
+	}
+	rest := strings.TrimLeft(s, " ")
+	if len(rest) < 3 || (rest[0] != '`' && rest[0] != '~') {
+		return ""
+	}
+	n := 0
+	for n < len(rest) && rest[n] == rest[0] {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	// A backtick fence's info string can't contain backticks
+	if rest[0] == '`' && strings.Contains(rest[n:], "`") {
+		return ""
+	}
+	return rest[:n]
+}
+
+func isFenceClose(s, fence string) bool {
+	n := 0
+	for n < len(s) && s[n] == fence[0] {
+		n++
+	}
+	return n >= len(fence) && isBlank(s[n:])
+}
+
+// headingLevel returns 1-6 for an ATX heading line, 0 otherwise
+func headingLevel(s string) int {
+	if indentWidth(s) > 3 {
+		return 0
+	}
+	rest := strings.TrimLeft(s, " ")
+	n := 0
+	for n < len(rest) && rest[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 {
+		return 0
+	}
+	if n < len(rest) && rest[n] != ' ' && rest[n] != '\t' {
+		return 0
+	}
+	return n
+}
+
+// setextLevel returns 1 for a === underline, 2 for ---, 0 otherwise
+func setextLevel(s string) int {
+	if indentWidth(s) > 3 {
+		return 0
+	}
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return 0
+	}
+	if strings.Trim(rest, "=") == "" {
+		return 1
+	}
+	if strings.Trim(rest, "-") == "" {
+		return 2
+	}
+	return 0
+}
+
+func isThematicBreak(s string) bool {
+	if indentWidth(s) > 3 {
+		return false
+	}
+	rest := strings.TrimSpace(s)
+	if rest == "" || (rest[0] != '-' && rest[0] != '*' && rest[0] != '_') {
+		return false
+	}
+	n := 0
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case rest[0]:
+			n++
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
+func isQuote(s string) bool {
+	if indentWidth(s) > 3 {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimLeft(s, " "), ">")
+}
+
+// listMarker returns the byte length of a list marker (-, *, +, 1., 1)) or 0
+func listMarker(s string) int {
+	if indentWidth(s) > 3 {
+		return 0
+	}
+	rest := strings.TrimLeft(s, " ")
+	if rest == "" {
+		return 0
+	}
+	n := 0
+	switch {
+	case rest[0] == '-' || rest[0] == '*' || rest[0] == '+':
+		n = 1
+	case isDigit(rest[0]):
+		for n < len(rest) && n < 9 && isDigit(rest[n]) {
+			n++
+		}
+		if n >= len(rest) || (rest[n] != '.' && rest[n] != ')') {
+			return 0
+		}
+		n++
+	default:
+		return 0
+	}
+	if n < len(rest) && rest[n] != ' ' && rest[n] != '\t' {
+		return 0
+	}
+	return n
+}
+
+// listKind groups markers that continue the same list: the bullet character,
+// or the delimiter of an ordered marker
+func listKind(marker string) string {
+	if isDigit(marker[0]) {
+		return marker[len(marker)-1:]
+	}
+	return marker
+}
+
+// delimiterCells returns the column count of a table delimiter row (|---|:--:|), or 0
+func delimiterCells(s string) int {
+	if indentWidth(s) > 3 {
+		return 0
+	}
+	rest := strings.TrimSpace(s)
+	rest = strings.TrimPrefix(rest, "|")
+	rest = strings.TrimSuffix(rest, "|")
+	if rest == "" {
+		return 0
+	}
+	cells := strings.Split(rest, "|")
+	for _, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		cell = strings.TrimPrefix(cell, ":")
+		cell = strings.TrimSuffix(cell, ":")
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return 0
+		}
+	}
+	return len(cells)
+}
@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+const note = `---
+title: The Fellowship
+tags: [lotr]
+---
+# Book One
+
+Frodo left the Shire.
+
+## Chapter 1
+
+- Sam followed Frodo.
+- Merry and Pippin:
+  1. stole mushrooms
+  2. ran
+
+> Gandalf warned them.
+> He was worried.
+
+` + "```go\nfunc Frodo() { Sam.Defeats(Sauron) }\n```" + `
+
+| Name | Race |
+|------|:----:|
+| Frodo | Hobbit |
+
+# Book Two
+Use ` + "`Sauron.Attack()`" + ` carefully.
+`
+
+func kinds(blocks []*Block) []string {
+	var out []string
+	for _, b := range blocks {
+		out = append(out, b.Kind.String())
+	}
+	return out
+}
+
+func TestParseStructure(t *testing.T) {
+	doc := Parse(note)
+
+	if got := strings.Join(kinds(doc.Blocks), ","); got != "Frontmatter,Section,Section" {
+		t.Fatalf("top level = %s", got)
+	}
+	if fm := doc.Frontmatter(); fm == nil || fm.Content.Slice(note) != "title: The Fellowship\ntags: [lotr]" {
+		t.Errorf("frontmatter = %+v", fm)
+	}
+
+	book := doc.Blocks[1]
+	if book.Level != 1 || strings.Join(kinds(book.Children), ",") != "Heading,Paragraph,Section" {
+		t.Fatalf("book one = level %d %v", book.Level, kinds(book.Children))
+	}
+	if got := book.Children[0].Content.Slice(note); got != "Book One" {
+		t.Errorf("heading text = %q", got)
+	}
+
+	chapter := book.Children[2]
+	if got := strings.Join(kinds(chapter.Children), ","); got != "Heading,List,BlockQuote,Code,Table" {
+		t.Fatalf("chapter = %s", got)
+	}
+
+	list := chapter.Children[1]
+	if len(list.Children) != 2 {
+		t.Fatalf("list items = %d", len(list.Children))
+	}
+	nested := list.Children[1].Children
+	if strings.Join(kinds(nested), ",") != "Paragraph,List" || len(nested[1].Children) != 2 {
+		t.Errorf("nested list = %v", kinds(nested))
+	}
+
+	quote := chapter.Children[2]
+	if len(quote.Children) != 1 || quote.Children[0].Kind != BlockParagraph {
+		t.Errorf("quote children = %v", kinds(quote.Children))
+	}
+
+	table := chapter.Children[4]
+	if len(table.Children) != 2 {
+		t.Fatalf("table rows = %d", len(table.Children))
+	}
+	var cells []string
+	for _, row := range table.Children {
+		for _, cell := range row.Children {
+			cells = append(cells, cell.Content.Slice(note))
+		}
+	}
+	if got := strings.Join(cells, ","); got != "Name,Race,Frodo,Hobbit" {
+		t.Errorf("cells = %s", got)
+	}
+
+	if doc.Blocks[2].Range.End != len(strings.TrimRight(note, "\n")) {
+		t.Errorf("last section should run to the end of the note")
+	}
+}
+
+func TestMaskHidesCodeAndMetadata(t *testing.T) {
+	doc := Parse(note)
+	masked := doc.Mask(note)
+
+	if len(masked) != len(note) || strings.Count(masked, "\n") != strings.Count(note, "\n") {
+		t.Fatal("mask must preserve offsets and line breaks")
+	}
+	for _, hidden := range []string{"title:", "Sauron", "#", "|", "> ", "- Sam", "---"} {
+		if strings.Contains(masked, hidden) {
+			t.Errorf("masked text still contains %q", hidden)
+		}
+	}
+	for _, kept := range []string{"Frodo left the Shire.", "Sam followed Frodo.", "Gandalf warned them.", "Hobbit", "carefully."} {
+		if !strings.Contains(masked, kept) {
+			t.Errorf("masked text lost %q", kept)
+		}
+	}
+
+	prose := doc.Prose()
+	if len(prose) != 14 { // 3 headings, 2 paragraphs, 4 list items, 1 quote, 4 cells
+		t.Errorf("prose ranges = %d", len(prose))
+	}
+}
+
+func TestSplitBlocksKeepsFencesWhole(t *testing.T) {
+	text := "Intro.\n\n```\nfirst\n\nsecond\n```\n\nOutro."
+	ranges := SplitBlocks(text)
+	if len(ranges) != 3 {
+		t.Fatalf("ranges = %d", len(ranges))
+	}
+	if got := ranges[1].Slice(text); got != "```\nfirst\n\nsecond\n```" {
+		t.Errorf("code block split: %q", got)
+	}
+}
+
+func TestParagraphEdgeCases(t *testing.T) {
+	text := "Title\n=====\nThe year was\n1984. It was cold.\n\n- - -\n\nA | B is not a table."
+	doc := Parse(text)
+	if len(doc.Blocks) != 1 || doc.Blocks[0].Kind != BlockSection || doc.Blocks[0].Children[0].Content.Slice(text) != "Title" {
+		t.Fatalf("setext heading: %v", kinds(doc.Blocks))
+	}
+	section := doc.Blocks[0]
+	if len(section.Children) != 3 || section.Children[1].Content.Slice(text) != "The year was\n1984. It was cold." {
+		t.Errorf("ordered marker must not interrupt a paragraph: %v", kinds(section.Children))
+	}
+	if last := section.Children[2]; last.Kind != BlockParagraph {
+		t.Errorf("pipe in prose parsed as %s", last.Kind)
+	}
+}
@@ -249,6 +249,9 @@ func (r RelationType) String() string {
 	case RelIs:
 		return "IS"
 	default:
+		if name, ok := customRelationName(r); ok {
+			return name
+		}
 		return "UNKNOWN"
 	}
 }
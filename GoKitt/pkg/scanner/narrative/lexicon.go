@@ -0,0 +1,223 @@
+package narrative
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// LexiconEntry is one user-defined verb, as stored per world.
+// Names are those printed by EventClass.String and RelationType.String;
+// an unknown relation name becomes a custom RelationType.
+type LexiconEntry struct {
+	Verb         string `json:"verb"`                   // A word or phrasal verb ("swear fealty to")
+	Event        string `json:"event,omitempty"`        // Defaults to UNKNOWN
+	Relation     string `json:"relation"`               // Built-in ("KILLS") or custom ("SWORN_TO")
+	Transitivity string `json:"transitivity,omitempty"` // "transitive" (default), "intransitive" or "ditransitive"
+}
+
+// Match resolves the entry's names, registering a custom relation if needed
+func (e LexiconEntry) Match() (VerbMatch, error) {
+	return e.resolve(true)
+}
+
+// Validate checks the entry's names like Match, but leaves an unknown
+// relation unregistered, so entries can be checked before they are saved
+func (e LexiconEntry) Validate() error {
+	_, err := e.resolve(false)
+	return err
+}
+
+func (e LexiconEntry) resolve(register bool) (VerbMatch, error) {
+	if strings.TrimSpace(e.Verb) == "" {
+		return VerbMatch{}, fmt.Errorf("lexicon entry without a verb")
+	}
+
+	event := EventUnknown
+	if e.Event != "" {
+		var ok bool
+		if event, ok = ParseEventClass(e.Event); !ok {
+			return VerbMatch{}, fmt.Errorf("verb %q: unknown event class %q", e.Verb, e.Event)
+		}
+	}
+	transitivity, ok := ParseTransitivity(e.Transitivity)
+	if !ok {
+		return VerbMatch{}, fmt.Errorf("verb %q: unknown transitivity %q", e.Verb, e.Transitivity)
+	}
+	if !register {
+		if err := ValidateRelationName(e.Relation); err != nil {
+			return VerbMatch{}, fmt.Errorf("verb %q: %w", e.Verb, err)
+		}
+		return VerbMatch{EventClass: event, Transitivity: transitivity}, nil
+	}
+	relation, err := RegisterRelation(e.Relation)
+	if err != nil {
+		return VerbMatch{}, fmt.Errorf("verb %q: %w", e.Verb, err)
+	}
+	return VerbMatch{EventClass: event, RelationType: relation, Transitivity: transitivity}, nil
+}
+
+// LoadLexicon replaces the matcher's user-defined verbs with entries and
+// recompiles the FST. Runtime additions from AddVerb are dropped. Nothing
+// changes if any entry is invalid, and no relation is registered for it.
+func (m *NarrativeMatcher) LoadLexicon(entries []LexiconEntry) error {
+	for _, e := range entries {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+	}
+	custom := make(map[string]VerbMatch, len(entries))
+	for _, e := range entries {
+		match, err := e.Match()
+		if err != nil {
+			return err
+		}
+		custom[m.key(e.Verb)] = match
+	}
+
+	previous := m.custom
+	m.custom = custom
+	if err := m.compile(); err != nil {
+		m.custom = previous
+		return err
+	}
+	m.overlay = make(map[string]VerbMatch)
+	return nil
+}
+
+// Compile folds the runtime overlay (AddVerb) into the FST
+func (m *NarrativeMatcher) Compile() error {
+	for key, match := range m.overlay {
+		m.custom[key] = match
+	}
+	if err := m.compile(); err != nil {
+		return err
+	}
+	m.overlay = make(map[string]VerbMatch)
+	return nil
+}
+
+// =============================================================================
+// Names
+// =============================================================================
+
+// Custom relation types take the IDs after the built-in ones. They are
+// registered process-wide so RelationType.String can name them.
+const (
+	firstCustomRelation RelationType = 64
+	lastCustomRelation  RelationType = 254
+)
+
+var customRelations = struct {
+	sync.RWMutex
+	names map[RelationType]string
+	ids   map[string]RelationType
+	next  RelationType
+}{
+	names: make(map[RelationType]string),
+	ids:   make(map[string]RelationType),
+	next:  firstCustomRelation,
+}
+
+// ParseRelation finds a built-in or registered relation type by name (case-insensitive)
+func ParseRelation(name string) (RelationType, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "UNKNOWN" {
+		return 0, false
+	}
+	for r := RelInteracts; r <= RelIs; r++ {
+		if r.String() == name {
+			return r, true
+		}
+	}
+	customRelations.RLock()
+	defer customRelations.RUnlock()
+	r, ok := customRelations.ids[name]
+	return r, ok
+}
+
+// ValidateRelationName reports whether RegisterRelation would accept name,
+// without registering it
+func ValidateRelationName(name string) error {
+	if _, ok := ParseRelation(name); ok {
+		return nil
+	}
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" || name == "UNKNOWN" || strings.IndexFunc(name, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_')
+	}) >= 0 {
+		return fmt.Errorf("invalid relation name %q", name)
+	}
+	customRelations.RLock()
+	defer customRelations.RUnlock()
+	if customRelations.next > lastCustomRelation {
+		return fmt.Errorf("relation %q: no custom relation types left", name)
+	}
+	return nil
+}
+
+// RegisterRelation returns the relation type with this name, creating a custom
+// one if it doesn't exist yet. Names are upper-cased letters, digits and underscores.
+func RegisterRelation(name string) (RelationType, error) {
+	if r, ok := ParseRelation(name); ok {
+		return r, nil
+	}
+	if err := ValidateRelationName(name); err != nil {
+		return 0, err
+	}
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	customRelations.Lock()
+	defer customRelations.Unlock()
+	if r, ok := customRelations.ids[name]; ok {
+		return r, nil
+	}
+	if customRelations.next > lastCustomRelation {
+		return 0, fmt.Errorf("relation %q: no custom relation types left", name)
+	}
+	r := customRelations.next
+	customRelations.next++
+	customRelations.names[r] = name
+	customRelations.ids[name] = r
+	return r, nil
+}
+
+// IsCustom reports whether r was created by RegisterRelation
+func (r RelationType) IsCustom() bool {
+	return r >= firstCustomRelation
+}
+
+func customRelationName(r RelationType) (string, bool) {
+	customRelations.RLock()
+	defer customRelations.RUnlock()
+	name, ok := customRelations.names[r]
+	return name, ok
+}
+
+// ParseEventClass finds an event class by name (case-insensitive)
+func ParseEventClass(name string) (EventClass, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "UNKNOWN" {
+		return EventUnknown, true
+	}
+	for e := EventClass(0); e < EventUnknown; e++ {
+		if e.String() == name {
+			return e, true
+		}
+	}
+	return EventUnknown, false
+}
+
+// ParseTransitivity reads "transitive", "intransitive", "ditransitive" or
+// "" (transitive)
+func ParseTransitivity(name string) (Transitivity, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "transitive":
+		return Transitive, true
+	case "intransitive":
+		return Intransitive, true
+	case "ditransitive":
+		return Ditransitive, true
+	}
+	return TransitiveNone, false
+}
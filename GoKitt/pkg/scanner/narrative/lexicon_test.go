@@ -0,0 +1,114 @@
+package narrative
+
+import "testing"
+
+func TestRegisterRelation(t *testing.T) {
+	if r, err := RegisterRelation("kills"); err != nil || r != RelKills {
+		t.Errorf("built-in name: got %s, %v", r, err)
+	}
+
+	sworn, err := RegisterRelation("sworn_to")
+	if err != nil {
+		t.Fatalf("RegisterRelation: %v", err)
+	}
+	if !sworn.IsCustom() || sworn.String() != "SWORN_TO" {
+		t.Errorf("custom relation = %d %q", sworn, sworn)
+	}
+	if again, _ := RegisterRelation("SWORN_TO"); again != sworn {
+		t.Errorf("re-registering gave %d, want %d", again, sworn)
+	}
+	if r, ok := ParseRelation("Sworn_To"); !ok || r != sworn {
+		t.Errorf("ParseRelation = %d, %v", r, ok)
+	}
+
+	for _, bad := range []string{"", "UNKNOWN", "sworn to", "oath-bound"} {
+		if _, err := RegisterRelation(bad); err == nil {
+			t.Errorf("RegisterRelation(%q) should fail", bad)
+		}
+	}
+}
+
+func TestValidateDoesNotRegister(t *testing.T) {
+	e := LexiconEntry{Verb: "bind", Relation: "OATH_BOUND_TO"}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if _, ok := ParseRelation("OATH_BOUND_TO"); ok {
+		t.Error("Validate registered the relation")
+	}
+
+	matcher, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	defer matcher.Close()
+	err = matcher.LoadLexicon([]LexiconEntry{e, {Verb: "curse", Relation: "oath-bound"}})
+	if err == nil {
+		t.Fatal("LoadLexicon should reject an invalid relation")
+	}
+	if _, ok := ParseRelation("OATH_BOUND_TO"); ok {
+		t.Error("a rejected lexicon registered its relations")
+	}
+}
+
+func TestLoadLexicon(t *testing.T) {
+	matcher, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	defer matcher.Close()
+
+	size := matcher.DictionarySize()
+	err = matcher.LoadLexicon([]LexiconEntry{
+		{Verb: "pledge", Event: "PROMISE", Relation: "SWORN_TO"},
+		{Verb: "swear fealty to", Event: "promise", Relation: "sworn_to"},
+		{Verb: "attack", Event: "BETRAYAL", Relation: "BETRAYS"}, // Overrides the built-in
+	})
+	if err != nil {
+		t.Fatalf("LoadLexicon: %v", err)
+	}
+	if got := matcher.DictionarySize(); got != size+2 {
+		t.Errorf("DictionarySize = %d, want %d", got, size+2)
+	}
+
+	if m := matcher.Lookup("pledged"); m == nil || m.RelationType.String() != "SWORN_TO" || m.EventClass != EventPromise {
+		t.Errorf("pledged = %+v", m)
+	}
+	if m, n := matcher.LookupPhrase("swore fealty to the king"); m == nil || n != len("swore fealty to") {
+		t.Errorf("phrase = %+v, %d", m, n)
+	}
+	if m := matcher.Lookup("attacked"); m == nil || m.RelationType != RelBetrays {
+		t.Errorf("override = %+v", m)
+	}
+
+	// Invalid entries leave the lexicon as it was
+	if err := matcher.LoadLexicon([]LexiconEntry{{Verb: "vanish", Event: "NOPE", Relation: "LEAVES"}}); err == nil {
+		t.Error("expected an error for an unknown event class")
+	}
+	if matcher.Lookup("pledge") == nil {
+		t.Error("failed load dropped the lexicon")
+	}
+
+	// Compile folds runtime additions into the FST
+	matcher.AddVerb("oathbreak", EventBetrayal, RelBetrays, Transitive)
+	if err := matcher.Compile(); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if matcher.OverlaySize() != 0 || matcher.DictionarySize() != size+3 {
+		t.Errorf("after Compile: overlay %d, dictionary %d", matcher.OverlaySize(), matcher.DictionarySize())
+	}
+	if matcher.Lookup("oathbreak") == nil {
+		t.Error("compiled verb not found")
+	}
+
+	// Reloading replaces everything user-defined
+	if err := matcher.LoadLexicon(nil); err != nil {
+		t.Fatalf("LoadLexicon(nil): %v", err)
+	}
+	if matcher.DictionarySize() != size || matcher.Lookup("pledge") != nil {
+		t.Error("empty lexicon should restore the built-in dictionary")
+	}
+	if m := matcher.Lookup("attack"); m == nil || m.RelationType != RelAttacks {
+		t.Errorf("built-in attack = %+v", m)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"sort"
 	"strings"
+	"unicode"
 
 	vellum "github.com/kittclouds/gokitt/pkg/fst"
+	"github.com/kittclouds/gokitt/pkg/stem"
 )
 
 // VerbMatch is the result of looking up a verb
@@ -15,10 +17,14 @@ type VerbMatch struct {
 	Transitivity Transitivity
 }
 
-// NarrativeMatcher uses FST to map verb stems to events
+// NarrativeMatcher uses FST to map verb stems to events.
+// Phrasal verbs are keyed by their stemmed words joined with spaces ("turn against").
 type NarrativeMatcher struct {
-	fst     *vellum.FST
-	overlay map[string]VerbMatch // Runtime additions
+	fst      *vellum.FST
+	builtin  []verbEntry          // verbEntries and phrasalEntries, keyed
+	custom   map[string]VerbMatch // Compiled-in lexicon additions (see LoadLexicon)
+	overlay  map[string]VerbMatch // Runtime additions not yet compiled
+	maxWords int                  // Longest key, in words
 }
 
 // verbEntry is a static verb→event mapping
@@ -29,63 +35,53 @@ type verbEntry struct {
 	transitivity Transitivity
 }
 
-// VERB_ENTRIES: sorted list of verb stems → (EventClass, RelationType, Transitivity)
-// VERB_ENTRIES: sorted list of verb stems → (EventClass, RelationType, Transitivity)
-// Note: Stems must be lowercase.
+// verbEntries: base verb → (EventClass, RelationType, Transitivity).
+// Words are written out and keyed through stem.Term in New, so inflections
+// and irregular forms ("fought", "said") find their base.
 var verbEntries = []verbEntry{
 	// Battle/Combat
 	{"attack", EventBattle, RelAttacks, Transitive},
-	{"battl", EventBattle, RelFights, Intransitive}, // battle with
+	{"battle", EventBattle, RelFights, Intransitive}, // battle with
 	{"defeat", EventBattle, RelDefeats, Transitive},
 	{"duel", EventDuel, RelFights, Intransitive},
-	{"fight", EventBattle, RelFights, Transitive},  // fight X
-	{"fought", EventBattle, RelFights, Transitive}, // Irregular past of 'fight'
+	{"fight", EventBattle, RelFights, Transitive}, // fight X
 	{"kill", EventDeath, RelKills, Transitive},
 	{"slay", EventDeath, RelKills, Transitive},
 	{"wound", EventBattle, RelAttacks, Transitive},
 
 	// Travel/Movement
 	{"approach", EventTravel, RelArrives, Intransitive},
-	{"arriv", EventTravel, RelArrives, Intransitive},
+	{"arrive", EventTravel, RelArrives, Intransitive},
 	{"depart", EventTravel, RelDeparts, Intransitive},
 	{"enter", EventTravel, RelArrives, Transitive},
 	{"exit", EventTravel, RelDeparts, Transitive},
 	{"journey", EventTravel, RelTravels, Intransitive},
-	{"leav", EventTravel, RelDeparts, Transitive},
+	{"leave", EventTravel, RelDeparts, Transitive},
 	{"sail", EventTravel, RelTravels, Intransitive},
 	{"travel", EventTravel, RelTravels, Intransitive},
 	{"visit", EventTravel, RelArrives, Transitive},
 
 	// Discovery/Knowledge
 	{"conceal", EventConceals, RelConceals, Transitive},
-	{"discov", EventDiscovery, RelDiscovers, Transitive},
+	{"discover", EventDiscovery, RelDiscovers, Transitive},
 	{"find", EventDiscovery, RelFinds, Transitive},
-	{"hid", EventConceals, RelConceals, Transitive}, // hide -> hid
+	{"hide", EventConceals, RelConceals, Transitive},
 	{"learn", EventDiscovery, RelDiscovers, Transitive},
-	{"li", EventDeceives, RelDeceives, Intransitive}, // lie -> li
+	{"lie", EventDeceives, RelDeceives, Intransitive},
 	{"reveal", EventReveals, RelReveals, Transitive},
 	{"uncover", EventDiscovery, RelDiscovers, Transitive},
 
 	// State Change/Copula
-	{"are", EventState, RelIs, Transitive},
-	{"be", EventState, RelIs, Transitive},
-	{"becam", EventTransform, RelBecomes, Transitive}, // became -> becam? NO, stemming logic is weak. Let's add 'became'.
-	{"became", EventTransform, RelBecomes, Transitive},
+	{"be", EventState, RelIs, Transitive}, // is, are, was, were, been
 	{"become", EventTransform, RelBecomes, Transitive},
-	{"been", EventState, RelIs, Transitive},
-	{"is", EventState, RelIs, Transitive},
 	{"transform", EventTransform, RelBecomes, Transitive},
 	{"turn", EventTransform, RelBecomes, Intransitive}, // turn into
-	{"was", EventState, RelIs, Transitive},
-	{"were", EventState, RelIs, Transitive},
 
 	// Perception/Observation (New)
 	{"hear", EventDiscovery, RelObserves, Transitive},
-	{"heard", EventDiscovery, RelObserves, Transitive}, // Irregular past
-	{"look", EventDiscovery, RelObserves, Transitive},  // look at
-	{"notic", EventDiscovery, RelObserves, Transitive},
-	{"observ", EventDiscovery, RelObserves, Transitive},
-	{"saw", EventDiscovery, RelObserves, Transitive}, // Irregular past of 'see'
+	{"look", EventDiscovery, RelObserves, Transitive}, // look at
+	{"notice", EventDiscovery, RelObserves, Transitive},
+	{"observe", EventDiscovery, RelObserves, Transitive},
 	{"see", EventDiscovery, RelObserves, Transitive},
 	{"watch", EventDiscovery, RelObserves, Transitive},
 	{"witness", EventDiscovery, RelObserves, Transitive},
@@ -97,70 +93,132 @@ var verbEntries = []verbEntry{
 	{"take", EventAcquire, RelTakes, Transitive},
 
 	// Causality
-	{"caus", EventCause, RelCauses, Transitive},
-	{"enabl", EventCause, RelEnables, Transitive},
+	{"cause", EventCause, RelCauses, Transitive},
+	{"enable", EventCause, RelEnables, Transitive},
 	{"prevent", EventPrevent, RelPrevents, Transitive},
 
 	// Dialogue/Speech (New & Expanded)
-	{"accus", EventAccusation, RelAccuses, Transitive},
+	{"accuse", EventAccusation, RelAccuses, Transitive},
 	{"ask", EventDialogue, RelSpeaksTo, Transitive},
 	{"bargain", EventBargain, RelInteracts, Intransitive},
 	{"call", EventDialogue, RelSpeaksTo, Transitive},
 	{"claim", EventDialogue, RelSpeaksTo, Transitive},
 	{"command", EventDialogue, RelRules, Transitive},
-	{"crie", EventDialogue, RelSpeaksTo, Intransitive}, // cry -> crie/cri? Porter: cry->cri
-	{"declar", EventDialogue, RelSpeaksTo, Transitive},
+	{"cry", EventDialogue, RelSpeaksTo, Intransitive},
+	{"declare", EventDialogue, RelSpeaksTo, Transitive},
 	{"explain", EventDialogue, RelSpeaksTo, Ditransitive},
 	{"mention", EventDialogue, RelMentions, Transitive},
-	{"promis", EventPromise, RelPromises, Ditransitive},
-	{"repli", EventDialogue, RelSpeaksTo, Intransitive}, // reply -> repli
-	{"said", EventDialogue, RelSpeaksTo, Ditransitive},  // Irregular past of 'say'
+	{"promise", EventPromise, RelPromises, Ditransitive},
+	{"reply", EventDialogue, RelSpeaksTo, Intransitive},
 	{"say", EventDialogue, RelSpeaksTo, Ditransitive},
 	{"shout", EventDialogue, RelSpeaksTo, Transitive},
 	{"speak", EventDialogue, RelSpeaksTo, Intransitive},
-	{"spoke", EventDialogue, RelSpeaksTo, Intransitive}, // Irregular past of 'speak'
 	{"state", EventDialogue, RelSpeaksTo, Transitive},
 	{"suggest", EventDialogue, RelSpeaksTo, Transitive},
 	{"tell", EventDialogue, RelSpeaksTo, Ditransitive},
-	{"told", EventDialogue, RelSpeaksTo, Ditransitive}, // Irregular past of 'tell'
 	{"threaten", EventThreat, RelThreatens, Transitive},
 	{"whisper", EventDialogue, RelSpeaksTo, Transitive},
 	{"yell", EventDialogue, RelSpeaksTo, Intransitive},
 
 	// Social/Relationship
-	{"alli", EventMeet, RelInteracts, Intransitive}, // ally
+	{"ally", EventMeet, RelInteracts, Intransitive},
 	{"betray", EventBetrayal, RelBetrays, Transitive},
-	{"deceiv", EventDeceives, RelDeceives, Transitive},
+	{"deceive", EventDeceives, RelDeceives, Transitive},
 	{"follow", EventMeet, RelServes, Transitive},
 	{"friend", EventMeet, RelInteracts, Transitive}, // befriend
 	{"help", EventRescue, RelSaves, Transitive},
 	{"join", EventMeet, RelInteracts, Transitive},
-	{"serv", EventMeet, RelServes, Transitive},
+	{"serve", EventMeet, RelServes, Transitive},
 	{"support", EventMeet, RelAllies, Transitive}, // No RelSupport, use Allies/Serves
 
 	// Emotions
-	{"admir", EventMeet, RelLoves, Transitive},  // close enough
+	{"admire", EventMeet, RelLoves, Transitive}, // close enough
 	{"fear", EventBattle, RelHates, Transitive}, // actually 'fears' isn't Hates, but indicates relation
-	{"hat", EventBattle, RelHates, Transitive},
-	{"lov", EventMeet, RelLoves, Transitive},
+	{"hate", EventBattle, RelHates, Transitive},
+	{"love", EventMeet, RelLoves, Transitive},
 	{"trust", EventMeet, RelAllies, Transitive},
 
 	// Rescue
-	{"rescu", EventRescue, RelSaves, Transitive},
-	{"sav", EventRescue, RelSaves, Transitive},
+	{"rescue", EventRescue, RelSaves, Transitive},
+	{"save", EventRescue, RelSaves, Transitive},
 
 	// Meeting
-	{"encount", EventMeet, RelInteracts, Transitive},
+	{"encounter", EventMeet, RelInteracts, Transitive},
 	{"meet", EventMeet, RelInteracts, Transitive},
 
 	// Creation/Destruction
 	{"build", EventCreate, RelCreates, Transitive},
-	{"creat", EventCreate, RelCreates, Transitive},
+	{"create", EventCreate, RelCreates, Transitive},
 	{"destroy", EventDeath, RelDestroys, Transitive},
 	{"make", EventCreate, RelCreates, Transitive},
 
 	// Authority
-	{"rul", EventTrial, RelRules, Transitive},
+	{"rule", EventTrial, RelRules, Transitive},
+}
+
+// phrasalEntries: verbs with their particle or preposition, keyed word by
+// word in New, so "turned against", "turns against" and "ran away" / "run away"
+// share one key.
+var phrasalEntries = []verbEntry{
+	// Travel/Movement
+	{"set out", EventTravel, RelTravels, Intransitive},
+	{"set out for", EventTravel, RelTravels, Transitive},
+	{"set off", EventTravel, RelDeparts, Intransitive},
+	{"set off for", EventTravel, RelTravels, Transitive},
+	{"set sail", EventTravel, RelTravels, Intransitive},
+	{"set sail for", EventTravel, RelTravels, Transitive},
+	{"head for", EventTravel, RelTravels, Transitive},
+	{"head to", EventTravel, RelTravels, Transitive},
+	{"run away", EventTravel, RelDeparts, Intransitive},
+	{"run away from", EventTravel, RelDeparts, Transitive},
+	{"get away from", EventTravel, RelDeparts, Transitive},
+	{"go back to", EventTravel, RelArrives, Transitive},
+	{"come back to", EventTravel, RelArrives, Transitive},
+
+	// Battle/Combat
+	{"turn against", EventBetrayal, RelBetrays, Transitive},
+	{"fight against", EventBattle, RelFights, Transitive},
+	{"fight with", EventBattle, RelFights, Transitive},
+	{"stand up to", EventBattle, RelFights, Transitive},
+	{"strike down", EventDeath, RelKills, Transitive},
+	{"cut down", EventDeath, RelKills, Transitive},
+	{"take down", EventBattle, RelDefeats, Transitive},
+	{"wipe out", EventDeath, RelDestroys, Transitive},
+	{"burn down", EventDeath, RelDestroys, Transitive},
+
+	// Social/Relationship
+	{"fall in love with", EventMeet, RelLoves, Transitive},
+	{"side with", EventMeet, RelAllies, Transitive},
+	{"team up with", EventMeet, RelAllies, Transitive},
+	{"join forces with", EventMeet, RelAllies, Transitive},
+	{"look up to", EventMeet, RelLoves, Transitive},
+	{"run into", EventMeet, RelInteracts, Transitive},
+	{"break up with", EventMeet, RelInteracts, Transitive},
+
+	// Discovery/Knowledge
+	{"come across", EventDiscovery, RelFinds, Transitive},
+	{"find out", EventDiscovery, RelDiscovers, Transitive},
+	{"give away", EventReveals, RelReveals, Transitive},
+	{"cover up", EventConceals, RelConceals, Transitive},
+	{"lie to", EventDeceives, RelDeceives, Transitive},
+
+	// State Change
+	{"turn into", EventTransform, RelBecomes, Transitive},
+
+	// Possession
+	{"take over", EventAcquire, RelRules, Transitive},
+	{"give up", EventLose, RelGives, Transitive},
+	{"hand over", EventAcquire, RelGives, Ditransitive},
+	{"make off with", EventTheft, RelSteals, Transitive},
+	{"run off with", EventTheft, RelSteals, Transitive},
+
+	// Dialogue/Speech
+	{"speak to", EventDialogue, RelSpeaksTo, Transitive},
+	{"talk to", EventDialogue, RelSpeaksTo, Transitive},
+
+	// Authority
+	{"rule over", EventTrial, RelRules, Transitive},
+	{"reign over", EventTrial, RelRules, Transitive},
 }
 
 // packValue encodes EventClass, RelationType, Transitivity into uint64
@@ -176,83 +234,141 @@ func unpackValue(v uint64) (EventClass, RelationType, Transitivity) {
 
 // New creates a NarrativeMatcher with the embedded verb dictionary
 func New() (*NarrativeMatcher, error) {
-	// Sort entries for FST (must be lexicographic)
-	sorted := make([]verbEntry, len(verbEntries))
-	copy(sorted, verbEntries)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].stem < sorted[j].stem
-	})
+	// Every entry is keyed the way lookups are, word by word
+	m := &NarrativeMatcher{
+		custom:  make(map[string]VerbMatch),
+		overlay: make(map[string]VerbMatch),
+	}
+	m.builtin = make([]verbEntry, 0, len(verbEntries)+len(phrasalEntries))
+	for _, entries := range [][]verbEntry{verbEntries, phrasalEntries} {
+		for _, entry := range entries {
+			entry.stem = m.key(entry.stem)
+			m.builtin = append(m.builtin, entry)
+		}
+	}
+
+	if err := m.compile(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// compile builds the FST from the built-in entries and the custom lexicon.
+// Custom entries replace built-in ones with the same key.
+func (m *NarrativeMatcher) compile() error {
+	values := make(map[string]uint64, len(m.builtin)+len(m.custom))
+	for _, entry := range m.builtin {
+		values[entry.stem] = packValue(entry.event, entry.relation, entry.transitivity)
+	}
+	for key, match := range m.custom {
+		values[key] = packValue(match.EventClass, match.RelationType, match.Transitivity)
+	}
+
+	// Sort keys for FST (must be lexicographic)
+	keys := make([]string, 0, len(values))
+	maxWords := 1
+	for key := range values {
+		keys = append(keys, key)
+		maxWords = max(maxWords, strings.Count(key, " ")+1)
+	}
+	sort.Strings(keys)
 
 	// Build FST
 	var buf bytes.Buffer
 	builder, err := vellum.New(&buf, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	for _, entry := range sorted {
-		val := packValue(entry.event, entry.relation, entry.transitivity)
-		err = builder.Insert([]byte(entry.stem), val)
-		if err != nil {
-			return nil, err
+	for _, key := range keys {
+		if err := builder.Insert([]byte(key), values[key]); err != nil {
+			return err
 		}
 	}
 
-	err = builder.Close()
-	if err != nil {
-		return nil, err
+	if err := builder.Close(); err != nil {
+		return err
 	}
 
 	// Load FST
 	fst, err := vellum.Load(buf.Bytes())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &NarrativeMatcher{
-		fst:     fst,
-		overlay: make(map[string]VerbMatch),
-	}, nil
+	if m.fst != nil {
+		m.fst.Close()
+	}
+	m.fst = fst
+	m.maxWords = maxWords
+	for key := range m.overlay {
+		m.maxWords = max(m.maxWords, strings.Count(key, " ")+1)
+	}
+	return nil
 }
 
-// Common suffixes for simplistic stemming
-var suffixes = []string{"ing", "ed", "es", "s", "er", "tion", "ness"}
-
-// Stem applies simple Porter-like stemming to a verb
+// Stem reduces a word to its lookup key: irregular forms to their base,
+// then Porter2 ("fought" -> "fight", "arrives" -> "arriv")
 func (m *NarrativeMatcher) Stem(word string) string {
-	// Optimization: 90% of calls are already lower from Chunker?
-	// Chunker keeps original case in Token.Text, so likely Mixed case.
-	// But `Lookup` calls `Stem`.
-
-	// Fast path: check if lower
-	isLower := true
-	for i := 0; i < len(word); i++ {
-		c := word[i]
-		if c >= 'A' && c <= 'Z' {
-			isLower = false
+	return stem.Term(word)
+}
+
+// Lookup finds the event/relation for a verb, or a phrasal verb given in full
+func (m *NarrativeMatcher) Lookup(verb string) *VerbMatch {
+	return m.lookupKey(m.key(verb))
+}
+
+// LookupPhrase finds the longest verb or phrasal verb at the start of text
+// ("ran away from the guards" matches "run away from") and returns it with
+// the number of bytes of text it covers. Matching stops at punctuation.
+func (m *NarrativeMatcher) LookupPhrase(text string) (*VerbMatch, int) {
+	stems := make([]string, 0, m.maxWords)
+	ends := make([]int, 0, m.maxWords)
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '-'
+		if inWord {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			stems = append(stems, m.Stem(text[start:i]))
+			ends = append(ends, i)
+			start = -1
+		}
+		if len(stems) == m.maxWords || !unicode.IsSpace(r) {
 			break
 		}
 	}
-
-	lower := word
-	if !isLower {
-		lower = strings.ToLower(word)
+	if start >= 0 && len(stems) < m.maxWords {
+		stems = append(stems, m.Stem(text[start:]))
+		ends = append(ends, len(text))
 	}
 
-	// Remove common suffixes
-	for _, suffix := range suffixes {
-		if strings.HasSuffix(lower, suffix) && len(lower) > len(suffix)+2 {
-			return lower[:len(lower)-len(suffix)]
+	for n := len(stems); n > 0; n-- {
+		if match := m.lookupKey(strings.Join(stems[:n], " ")); match != nil {
+			return match, ends[n-1]
 		}
 	}
-
-	return lower
+	return nil, 0
 }
 
-// Lookup finds the event/relation for a verb
-func (m *NarrativeMatcher) Lookup(verb string) *VerbMatch {
-	stem := m.Stem(verb)
+// key stems each word of a verb or phrasal verb
+func (m *NarrativeMatcher) key(verb string) string {
+	words := strings.Fields(verb)
+	if len(words) == 1 {
+		return m.Stem(words[0])
+	}
+	for i, w := range words {
+		words[i] = m.Stem(w)
+	}
+	return strings.Join(words, " ")
+}
 
+// lookupKey checks the overlay, then the FST
+func (m *NarrativeMatcher) lookupKey(stem string) *VerbMatch {
 	// Check overlay first (runtime additions)
 	if match, ok := m.overlay[stem]; ok {
 		return &match
@@ -272,9 +388,52 @@ func (m *NarrativeMatcher) Lookup(verb string) *VerbMatch {
 	}
 }
 
-// AddVerb adds a verb mapping at runtime
+// LookupFuzzy finds the closest verb stem within maxDistance edits, so that
+// typos like "betrayd" or "atacked" still resolve. It returns the match and
+// its edit distance; exact hits are reported at distance 0.
+func (m *NarrativeMatcher) LookupFuzzy(verb string, maxDistance uint8) (*VerbMatch, uint8) {
+	if match := m.Lookup(verb); match != nil {
+		return match, 0
+	}
+	if maxDistance == 0 {
+		return nil, 0
+	}
+
+	builder, err := vellum.NewLevenshteinAutomatonBuilder(maxDistance, true)
+	if err != nil {
+		return nil, 0
+	}
+	dfa, err := builder.BuildDfa(m.Stem(verb), maxDistance)
+	if err != nil {
+		return nil, 0
+	}
+
+	itr, err := m.fst.Search(dfa, nil, nil)
+	var best uint64
+	bestDist := maxDistance + 1
+	for err == nil {
+		_, val := itr.Current()
+		if d := itr.EditDistance(); d < bestDist {
+			best, bestDist = val, d
+		}
+		err = itr.Next()
+	}
+	if bestDist > maxDistance {
+		return nil, 0
+	}
+
+	event, relation, transitivity := unpackValue(best)
+	return &VerbMatch{
+		EventClass:   event,
+		RelationType: relation,
+		Transitivity: transitivity,
+	}, bestDist
+}
+
+// AddVerb adds a verb (or phrasal verb, "stand up to") mapping at runtime
 func (m *NarrativeMatcher) AddVerb(verb string, event EventClass, relation RelationType, transitivity Transitivity) {
-	stem := m.Stem(verb)
+	stem := m.key(verb)
+	m.maxWords = max(m.maxWords, strings.Count(stem, " ")+1)
 	m.overlay[stem] = VerbMatch{
 		EventClass:   event,
 		RelationType: relation,
@@ -45,6 +45,12 @@ func TestNarrativeMatcherStemming(t *testing.T) {
 		{"killing", EventDeath},
 		{"travels", EventTravel},
 		{"traveled", EventTravel},
+		{"fought", EventBattle},
+		{"stole", EventTheft},
+		{"stolen", EventTheft},
+		{"arrives", EventTravel},
+		{"hid", EventConceals},
+		{"were", EventState},
 	}
 
 	for _, tc := range tests {
@@ -111,3 +117,74 @@ func TestDictionarySize(t *testing.T) {
 		t.Errorf("Expected at least 30 entries, got %d", size)
 	}
 }
+
+func TestNarrativeMatcherLookupFuzzy(t *testing.T) {
+	matcher, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	defer matcher.Close()
+
+	match, dist := matcher.LookupFuzzy("atacked", 1)
+	if match == nil {
+		t.Fatal("Expected fuzzy match for 'atacked'")
+	}
+	if match.RelationType != RelAttacks || dist != 1 {
+		t.Errorf("Expected RelAttacks at distance 1, got %s at %d", match.RelationType, dist)
+	}
+
+	match, dist = matcher.LookupFuzzy("betrays", 1)
+	if match == nil || match.RelationType != RelBetrays || dist != 0 {
+		t.Errorf("Expected exact RelBetrays, got %+v at %d", match, dist)
+	}
+
+	if match, _ := matcher.LookupFuzzy("xyzzy", 1); match != nil {
+		t.Error("Expected nil for unknown verb")
+	}
+}
+
+func TestNarrativeMatcherPhrasal(t *testing.T) {
+	matcher, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	defer matcher.Close()
+
+	cases := []struct {
+		text     string
+		relation RelationType
+		covered  string
+	}{
+		{"ran away from the guards", RelDeparts, "ran away from"},
+		{"ran away.", RelDeparts, "ran away"},
+		{"turned against the king", RelBetrays, "turned against"},
+		{"fell in love with Sam", RelLoves, "fell in love with"},
+		{"took over the kingdom", RelRules, "took over"},
+		{"sets out for Mordor", RelTravels, "sets out for"},
+		{"attacked the orcs", RelAttacks, "attacked"},
+		{"turned, against all hope", RelBecomes, "turned"}, // Punctuation ends the phrase
+	}
+	for _, tc := range cases {
+		match, n := matcher.LookupPhrase(tc.text)
+		if match == nil {
+			t.Errorf("%q: no match", tc.text)
+			continue
+		}
+		if match.RelationType != tc.relation || tc.text[:n] != tc.covered {
+			t.Errorf("%q: got %s over %q, want %s over %q", tc.text, match.RelationType, tc.text[:n], tc.relation, tc.covered)
+		}
+	}
+
+	if match, _ := matcher.LookupPhrase("xyzzy away"); match != nil {
+		t.Error("Expected nil for unknown phrase")
+	}
+
+	// Runtime phrasal verbs take part in longest match
+	matcher.AddVerb("swore fealty to", EventMeet, RelServes, Transitive)
+	if match, n := matcher.LookupPhrase("swore fealty to the queen"); match == nil || match.RelationType != RelServes || n != len("swore fealty to") {
+		t.Errorf("overlay phrasal verb not matched: %+v over %d", match, n)
+	}
+	if match := matcher.Lookup("turns against"); match == nil || match.RelationType != RelBetrays {
+		t.Error("Lookup should accept a phrasal verb given in full")
+	}
+}
@@ -0,0 +1,237 @@
+package narrative
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Polarity records whether an event is asserted or denied
+type Polarity uint8
+
+const (
+	Affirmed Polarity = 0
+	Negated  Polarity = 1 // "did not kill", "never betrayed"
+)
+
+// String returns a readable name
+func (p Polarity) String() string {
+	if p == Negated {
+		return "negated"
+	}
+	return "affirmed"
+}
+
+// Modality records how certain the text is that an event happens
+type Modality uint8
+
+const (
+	Factual      Modality = 0
+	Possible     Modality = 1 // "might betray", "could kill"
+	Intended     Modality = 2 // "plans to betray", "was going to kill"
+	Hypothetical Modality = 3 // "if Aria kills him", "would kill"
+	Reported     Modality = 4 // "Bram said Aria killed", "allegedly killed"
+)
+
+// String returns a readable name
+func (m Modality) String() string {
+	switch m {
+	case Possible:
+		return "possible"
+	case Intended:
+		return "intended"
+	case Hypothetical:
+		return "hypothetical"
+	case Reported:
+		return "reported"
+	default:
+		return "factual"
+	}
+}
+
+// Tense is the time of an event relative to the narration
+type Tense uint8
+
+const (
+	Present Tense = 0
+	Past    Tense = 1
+	Future  Tense = 2
+)
+
+// String returns a readable name
+func (t Tense) String() string {
+	switch t {
+	case Past:
+		return "past"
+	case Future:
+		return "future"
+	default:
+		return "present"
+	}
+}
+
+// Qualifier is the polarity, modality and tense of one verb phrase
+type Qualifier struct {
+	Polarity Polarity
+	Modality Modality
+	Tense    Tense
+}
+
+// IsFactual reports whether the event is asserted to happen (or to have happened)
+func (q Qualifier) IsFactual() bool {
+	return q.Polarity == Affirmed && q.Modality == Factual
+}
+
+// Qualify reads a verb phrase ("did not kill", "might have betrayed") and the
+// text leading up to it. Only the current clause of context counts: it decides
+// hypotheticals ("if ..."), reported speech ("Bram said ...") and intentions
+// ("plans to ..."); the phrase's own auxiliaries, modals and negators decide
+// the rest.
+func Qualify(phrase, context string) Qualifier {
+	var q Qualifier
+	words := qualifierWords(phrase)
+	clause := qualifierWords(context[clauseStart(context):])
+
+	perfect, auxiliary, pastForm := false, false, false
+	for i, w := range words {
+		if negators[w] || strings.HasSuffix(w, "n't") {
+			q.Polarity = Negated
+		}
+		aux := strings.TrimSuffix(w, "n't")
+		switch {
+		case aux == "will" || aux == "shall" || aux == "wo":
+			q.Tense = Future
+		case aux == "would":
+			q.Modality = Hypothetical
+		case possibleModals[aux] || aux == "ca":
+			if q.Modality == Factual {
+				q.Modality = Possible
+			}
+		case pastAuxiliaries[aux]:
+			q.Tense = Past
+		case aux == "have" || aux == "has":
+			perfect = i+1 < len(words)
+		case presentAuxiliaries[aux]:
+		default:
+			if reportingAdverbs[w] && q.Modality == Factual {
+				q.Modality = Reported // "allegedly killed"
+			}
+			pastForm = pastForm || isPastForm(w)
+			continue
+		}
+		auxiliary = true
+	}
+	if perfect {
+		q.Tense = Past // "has killed", "might have killed"
+	}
+	if !auxiliary && pastForm {
+		q.Tense = Past
+	}
+
+	// Context: "going to" / "plans to" right before the phrase
+	if n := len(clause); n >= 2 && clause[n-1] == "to" && intentVerbs[clause[n-2]] {
+		q.Modality = Intended
+		if clause[n-2] == "going" {
+			q.Tense = Future
+			if n >= 3 && pastAuxiliaries[clause[n-3]] {
+				q.Tense = Past
+			}
+		}
+	}
+	for _, w := range clause {
+		switch {
+		case conditionals[w]:
+			q.Modality = Hypothetical
+		case (reportingVerbs[w] || reportingAdverbs[w]) && q.Modality != Hypothetical:
+			q.Modality = Reported
+		}
+	}
+	return q
+}
+
+// Helpers
+
+// clauseStart returns the offset just past the last clause break in s.
+// A colon only breaks when followed by space, so [KIND:Label] tags stay whole.
+func clauseStart(s string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		switch s[i] {
+		case ',', ';', '.', '!', '?', '\n', '"':
+			return i + 1
+		case ':':
+			if i+1 == len(s) || s[i+1] == ' ' {
+				return i + 1
+			}
+		case 0x9c, 0x9d: // Last byte of “ and ”
+			if i >= 2 && s[i-2] == 0xe2 && s[i-1] == 0x80 {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// qualifierWords lower-cases s and splits it into words, keeping apostrophes
+func qualifierWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+}
+
+// isPastForm recognises regular -ed forms and common irregular past tenses
+func isPastForm(w string) bool {
+	return (strings.HasSuffix(w, "ed") && len(w) > 4) || irregularPast[w]
+}
+
+var negators = map[string]bool{
+	"not": true, "never": true, "no": true, "cannot": true, "nor": true,
+}
+
+var possibleModals = map[string]bool{
+	"can": true, "cannot": true, "could": true, "may": true, "might": true,
+	"must": true, "should": true, "ought": true,
+}
+
+var pastAuxiliaries = map[string]bool{
+	"was": true, "were": true, "had": true, "did": true,
+}
+
+var presentAuxiliaries = map[string]bool{
+	"is": true, "are": true, "am": true, "be": true, "been": true, "being": true,
+	"do": true, "does": true,
+}
+
+var intentVerbs = map[string]bool{
+	"going": true, "want": true, "wants": true, "wanted": true,
+	"plan": true, "plans": true, "planned": true, "plot": true, "plots": true, "plotted": true,
+	"intend": true, "intends": true, "intended": true, "mean": true, "means": true, "meant": true,
+	"try": true, "tries": true, "tried": true, "attempt": true, "attempts": true, "attempted": true,
+	"vow": true, "vows": true, "vowed": true, "swear": true, "swears": true, "swore": true,
+	"hope": true, "hopes": true, "hoped": true, "decide": true, "decides": true, "decided": true,
+	"promise": true, "promises": true, "promised": true, "threaten": true, "threatens": true, "threatened": true,
+}
+
+var conditionals = map[string]bool{
+	"if": true, "unless": true, "suppose": true, "supposing": true, "imagine": true, "whether": true,
+}
+
+var reportingVerbs = map[string]bool{
+	"said": true, "says": true, "say": true, "claimed": true, "claims": true, "claim": true,
+	"reported": true, "reports": true, "heard": true, "rumored": true, "rumoured": true,
+	"believed": true, "believes": true, "thought": true, "thinks": true, "alleged": true,
+	"according": true,
+}
+
+var reportingAdverbs = map[string]bool{
+	"allegedly": true, "reportedly": true, "supposedly": true, "apparently": true,
+}
+
+var irregularPast = map[string]bool{
+	"said": true, "told": true, "met": true, "fought": true, "stole": true, "took": true,
+	"gave": true, "found": true, "left": true, "went": true, "came": true, "saw": true,
+	"knew": true, "slew": true, "struck": true, "held": true, "led": true, "made": true,
+	"wrote": true, "spoke": true, "broke": true, "fled": true, "won": true, "lost": true,
+	"bought": true, "sold": true, "built": true, "hid": true, "rode": true, "ran": true,
+	"became": true, "began": true, "brought": true, "caught": true, "drew": true, "fell": true,
+	"felt": true, "forgave": true, "forgot": true, "froze": true, "got": true, "swore": true,
+	"taught": true, "threw": true, "understood": true, "woke": true, "wore": true, "stood": true,
+}
@@ -0,0 +1,37 @@
+package narrative
+
+import "testing"
+
+func TestQualify(t *testing.T) {
+	cases := []struct {
+		phrase, context string
+		want            Qualifier
+	}{
+		{"killed", "Aria ", Qualifier{Affirmed, Factual, Past}},
+		{"kills", "Aria ", Qualifier{Affirmed, Factual, Present}},
+		{"did not kill", "Aria ", Qualifier{Negated, Factual, Past}},
+		{"didn't kill", "Aria ", Qualifier{Negated, Factual, Past}},
+		{"never betrayed", "Aria ", Qualifier{Negated, Factual, Past}},
+		{"might betray", "Aria ", Qualifier{Affirmed, Possible, Present}},
+		{"might have betrayed", "Aria ", Qualifier{Affirmed, Possible, Past}},
+		{"will kill", "Aria ", Qualifier{Affirmed, Factual, Future}},
+		{"won't kill", "Aria ", Qualifier{Negated, Factual, Future}},
+		{"would kill", "Aria ", Qualifier{Affirmed, Hypothetical, Present}},
+		{"kills", "If Aria ", Qualifier{Affirmed, Hypothetical, Present}},
+		{"ends", "If Aria kills him, the war ", Qualifier{Affirmed, Factual, Present}},
+		{"betray", "Aria plans to ", Qualifier{Affirmed, Intended, Present}},
+		{"kill", "Aria was going to ", Qualifier{Affirmed, Intended, Past}},
+		{"killed", "Bram said Aria ", Qualifier{Affirmed, Reported, Past}},
+		{"allegedly killed", "Aria ", Qualifier{Affirmed, Reported, Past}},
+	}
+	for _, tc := range cases {
+		got := Qualify(tc.phrase, tc.context)
+		if got != tc.want {
+			t.Errorf("Qualify(%q, %q) = %s/%s/%s, want %s/%s/%s", tc.phrase, tc.context,
+				got.Polarity, got.Modality, got.Tense, tc.want.Polarity, tc.want.Modality, tc.want.Tense)
+		}
+	}
+	if (Qualifier{Negated, Factual, Past}).IsFactual() || !(Qualifier{Affirmed, Factual, Past}).IsFactual() {
+		t.Error("IsFactual misreports polarity")
+	}
+}
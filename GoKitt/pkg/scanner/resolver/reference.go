@@ -0,0 +1,201 @@
+package resolver
+
+import (
+	"strings"
+
+	"github.com/kittclouds/gokitt/pkg/stem"
+)
+
+// =============================================================================
+// Definite Descriptions
+// =============================================================================
+
+// descriptor is what a common noun says about the entity it refers to
+type descriptor struct {
+	class  string // Kind class, see kindClass
+	gender Gender
+}
+
+// descriptors map head nouns to the kind of entity "the <noun>" can mean.
+// Nouns matching an entity's name, alias or subtype are checked first.
+var descriptors = map[string]descriptor{
+	// People
+	"man": {"CHARACTER", GenderMale}, "woman": {"CHARACTER", GenderFemale},
+	"boy": {"CHARACTER", GenderMale}, "girl": {"CHARACTER", GenderFemale},
+	"king": {"CHARACTER", GenderMale}, "queen": {"CHARACTER", GenderFemale},
+	"prince": {"CHARACTER", GenderMale}, "princess": {"CHARACTER", GenderFemale},
+	"lord": {"CHARACTER", GenderMale}, "lady": {"CHARACTER", GenderFemale},
+	"father": {"CHARACTER", GenderMale}, "mother": {"CHARACTER", GenderFemale},
+	"brother": {"CHARACTER", GenderMale}, "sister": {"CHARACTER", GenderFemale},
+	"son": {"CHARACTER", GenderMale}, "daughter": {"CHARACTER", GenderFemale},
+	"wizard": {"CHARACTER", GenderUnknown}, "witch": {"CHARACTER", GenderFemale},
+	"knight": {"CHARACTER", GenderUnknown}, "warrior": {"CHARACTER", GenderUnknown},
+	"captain": {"CHARACTER", GenderUnknown}, "soldier": {"CHARACTER", GenderUnknown},
+	"stranger": {"CHARACTER", GenderUnknown}, "thief": {"CHARACTER", GenderUnknown},
+	"mage": {"CHARACTER", GenderUnknown}, "priest": {"CHARACTER", GenderUnknown},
+	"merchant": {"CHARACTER", GenderUnknown}, "healer": {"CHARACTER", GenderUnknown},
+	"child": {"CHARACTER", GenderUnknown},
+
+	// Places
+	"city": {"LOCATION", GenderNeutral}, "town": {"LOCATION", GenderNeutral},
+	"village": {"LOCATION", GenderNeutral}, "castle": {"LOCATION", GenderNeutral},
+	"citadel": {"LOCATION", GenderNeutral}, "fortress": {"LOCATION", GenderNeutral},
+	"tower": {"LOCATION", GenderNeutral}, "keep": {"LOCATION", GenderNeutral},
+	"forest": {"LOCATION", GenderNeutral}, "mountain": {"LOCATION", GenderNeutral},
+	"river": {"LOCATION", GenderNeutral}, "kingdom": {"LOCATION", GenderNeutral},
+	"realm": {"LOCATION", GenderNeutral}, "temple": {"LOCATION", GenderNeutral},
+	"palace": {"LOCATION", GenderNeutral}, "harbor": {"LOCATION", GenderNeutral},
+
+	// Things
+	"sword": {"ITEM", GenderNeutral}, "blade": {"ITEM", GenderNeutral},
+	"ring": {"ITEM", GenderNeutral}, "crown": {"ITEM", GenderNeutral},
+	"amulet": {"ITEM", GenderNeutral}, "staff": {"ITEM", GenderNeutral},
+	"book": {"ITEM", GenderNeutral}, "map": {"ITEM", GenderNeutral},
+	"key": {"ITEM", GenderNeutral}, "relic": {"ITEM", GenderNeutral},
+	"artifact": {"ITEM", GenderNeutral}, "shield": {"ITEM", GenderNeutral},
+
+	// Organizations
+	"army": {"FACTION", GenderPlural}, "guild": {"FACTION", GenderPlural},
+	"order": {"FACTION", GenderPlural}, "council": {"FACTION", GenderPlural},
+	"house": {"FACTION", GenderPlural}, "clan": {"FACTION", GenderPlural},
+	"company": {"FACTION", GenderPlural}, "cult": {"FACTION", GenderPlural},
+}
+
+// groupNouns refer to an ad-hoc group ("the pair", "the two")
+var groupNouns = map[string]bool{
+	"pair": true, "two": true, "three": true, "both": true, "trio": true, "group": true,
+	"companions": true, "friends": true, "brothers": true, "sisters": true,
+	"siblings": true, "twins": true, "travelers": true, "party": true,
+}
+
+// definiteDeterminers lead a description that points back at a known entity
+var definiteDeterminers = map[string]bool{
+	"the": true, "this": true, "that": true, "these": true, "those": true,
+}
+
+// ResolveDescription resolves a definite description such as "the king" or
+// "the old wizard" to the most recent entity it can describe. The head noun
+// (the last word) is matched first against the words of each entity's
+// subtype, name and aliases, then against the kind and gender it implies.
+// Returns "" if text does not start with a definite determiner.
+func (r *Resolver) ResolveDescription(text string) string {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) < 2 || !definiteDeterminers[words[0]] {
+		return ""
+	}
+	head := words[len(words)-1]
+
+	if groupNouns[head] {
+		for _, id := range r.Context.history {
+			if len(r.Context.registry[id].Members) > 0 {
+				return id
+			}
+		}
+		return ""
+	}
+
+	// 1. The head names the entity: "the king" for [CHARACTER:Aldric|King]
+	term := stem.Term(head)
+	for _, id := range r.Context.history {
+		meta, ok := r.Context.registry[id]
+		if ok && len(meta.Members) == 0 && describes(meta, term) {
+			return id
+		}
+	}
+
+	// 2. The head implies a kind: "the city" for the last LOCATION
+	d, ok := descriptors[head]
+	if !ok {
+		return ""
+	}
+	for _, id := range r.Context.history {
+		meta, ok := r.Context.registry[id]
+		if !ok || len(meta.Members) > 0 || kindClass(meta.Kind) != d.class {
+			continue
+		}
+		if d.gender == GenderUnknown || meta.Gender == GenderUnknown || meta.Gender == d.gender {
+			return id
+		}
+	}
+	return ""
+}
+
+// describes reports whether a stemmed word appears in the entity's subtype, name or aliases
+func describes(meta EntityMetadata, term string) bool {
+	names := append([]string{meta.Subtype, meta.Name}, meta.Aliases...)
+	for _, name := range names {
+		for _, word := range strings.Fields(name) {
+			if stem.Term(word) == term {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// kindClass folds entity kinds that descriptions treat alike
+func kindClass(kind string) string {
+	switch strings.ToUpper(kind) {
+	case "CHARACTER", "NPC", "PERSON":
+		return "CHARACTER"
+	case "LOCATION", "PLACE":
+		return "LOCATION"
+	case "ITEM", "OBJECT":
+		return "ITEM"
+	case "FACTION", "ORGANIZATION":
+		return "FACTION"
+	default:
+		return strings.ToUpper(kind)
+	}
+}
+
+// =============================================================================
+// Groups
+// =============================================================================
+
+// GroupKind is the Kind of the ad-hoc entities made by ObserveGroup
+const GroupKind = "GROUP"
+
+const groupPrefix = "group:"
+
+// GroupID returns the ID of the group made of these members, in order
+func GroupID(memberIDs []string) string {
+	return groupPrefix + strings.Join(memberIDs, "|")
+}
+
+// ObserveGroup records a coordinated mention ("Aria and Tom") as a plural
+// entity, so a following "they" resolves to the group. Groups are kept in
+// the context only, not in the fuzzy index. Returns the group's ID.
+func (r *Resolver) ObserveGroup(memberIDs []string) string {
+	id := GroupID(memberIDs)
+	names := make([]string, len(memberIDs))
+	for i, member := range memberIDs {
+		names[i] = member
+		if meta, ok := r.Context.registry[member]; ok && meta.Name != "" {
+			names[i] = meta.Name
+		}
+	}
+	r.Context.Register(EntityMetadata{
+		ID:      id,
+		Name:    strings.Join(names, " and "),
+		Gender:  GenderPlural,
+		Kind:    GroupKind,
+		Members: append([]string(nil), memberIDs...),
+	})
+	r.Context.PushMention(id)
+	return id
+}
+
+// GroupMembers returns the member IDs encoded in a group ID, or nil if id is not a group
+func GroupMembers(id string) []string {
+	if !strings.HasPrefix(id, groupPrefix) {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(id, groupPrefix), "|")
+}
+
+// Entity returns a registered entity or group
+func (r *Resolver) Entity(id string) (EntityMetadata, bool) {
+	meta, ok := r.Context.registry[id]
+	return meta, ok
+}
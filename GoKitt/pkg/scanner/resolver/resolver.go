@@ -1,11 +1,16 @@
-// Package resolver implements coreference resolution (pronouns and aliases).
+// Package resolver implements coreference resolution: pronouns, reflexives,
+// aliases, definite descriptions ("the old wizard") and groups formed from
+// coordinated mentions ("Aria and Tom" ... "they").
 // It maintains a narrative context to track recency and gender.
 package resolver
 
 import (
+	"hash/fnv"
+	"strconv"
 	"strings"
 
 	"github.com/kittclouds/gokitt/pkg/resorank"
+	"github.com/kittclouds/gokitt/pkg/stem"
 )
 
 // Gender of an entity
@@ -26,7 +31,22 @@ type EntityMetadata struct {
 	Gender    Gender
 	Aliases   []string
 	Kind      string
+	Subtype   string // "King", "Wizard": lets "the king" find the entity
 	Embedding []float32
+	Members   []string // Entity IDs of a group's members; empty for single entities
+}
+
+// named reports whether lower is the entity's name or one of its aliases, lower-cased
+func (e EntityMetadata) named(lower string) bool {
+	if strings.ToLower(e.Name) == lower {
+		return true
+	}
+	for _, alias := range e.Aliases {
+		if strings.ToLower(alias) == lower {
+			return true
+		}
+	}
+	return false
 }
 
 // NarrativeContext tracks the state of the narrative
@@ -76,8 +96,82 @@ func (nc *NarrativeContext) PushMention(entityID string) {
 	}
 }
 
-// FindMostRecent finds the most recent entity matching the gender
+// History returns the recent mentions, most recent first
+func (nc *NarrativeContext) History() []string {
+	return append([]string(nil), nc.history...)
+}
+
+// SetHistory replaces the recent mentions, most recent first
+func (nc *NarrativeContext) SetHistory(ids []string) {
+	nc.history = append(nc.history[:0], ids...)
+	if len(nc.history) > nc.maxHistory {
+		nc.history = nc.history[:nc.maxHistory]
+	}
+}
+
+// ClearHistory forgets recent mentions but keeps registered entities
+func (nc *NarrativeContext) ClearHistory() {
+	nc.history = nc.history[:0]
+	nc.Speaker = ""
+	nc.InDialogue = false
+	nc.ActiveCharacters = nil
+}
+
+// Clone returns an independent copy of the context
+func (nc *NarrativeContext) Clone() *NarrativeContext {
+	clone := &NarrativeContext{
+		history:          append([]string(nil), nc.history...),
+		registry:         make(map[string]EntityMetadata, len(nc.registry)),
+		maxHistory:       nc.maxHistory,
+		ScenarioID:       nc.ScenarioID,
+		ActiveCharacters: append([]string(nil), nc.ActiveCharacters...),
+		Speaker:          nc.Speaker,
+		InDialogue:       nc.InDialogue,
+	}
+	for id, meta := range nc.registry {
+		clone.registry[id] = meta
+	}
+	return clone
+}
+
+// Key fingerprints everything a scan reads from the context: registered
+// entities, mention history and the conversation. Equal keys resolve alike.
+func (nc *NarrativeContext) Key() uint64 {
+	h := fnv.New64a()
+	write := func(parts ...string) {
+		for _, p := range parts {
+			h.Write([]byte(p))
+			h.Write([]byte{0})
+		}
+	}
+	write(nc.history...)
+	write(nc.ActiveCharacters...)
+	write(nc.ScenarioID, nc.Speaker, strconv.FormatBool(nc.InDialogue), strconv.Itoa(nc.maxHistory))
+
+	// Registry order is random: entries are hashed apart and summed
+	var registry uint64
+	for _, meta := range nc.registry {
+		e := fnv.New64a()
+		for _, p := range append([]string{meta.ID, meta.Name, meta.Kind, meta.Subtype, strconv.Itoa(int(meta.Gender))}, meta.Aliases...) {
+			e.Write([]byte(p))
+			e.Write([]byte{0})
+		}
+		registry += e.Sum64()
+	}
+	write(strconv.FormatUint(registry, 16))
+	return h.Sum64()
+}
+
+// FindMostRecent finds the most recent entity matching the gender.
+// Plural pronouns prefer the most recent plural entity, such as a group.
 func (nc *NarrativeContext) FindMostRecent(gender Gender) string {
+	if gender == GenderPlural {
+		for _, id := range nc.history {
+			if meta, ok := nc.registry[id]; ok && meta.Gender == GenderPlural {
+				return id
+			}
+		}
+	}
 	for _, id := range nc.history {
 		if meta, ok := nc.registry[id]; ok {
 			if gendersCompatible(meta.Gender, gender) {
@@ -121,6 +215,9 @@ func New() *Resolver {
 	cfg.FieldWeights["alias"] = 5.0 // Alias match is good
 	cfg.FieldWeights["kind"] = 1.0  // Weak signal
 	cfg.B = 0.5                     // Short text, lower length normalization penalty
+	for _, field := range []string{"name", "alias", "kind"} {
+		cfg.FieldAnalyzers[field] = stem.Lowercase // Names are never stemmed
+	}
 
 	return &Resolver{
 		Context: NewContext(),
@@ -181,7 +278,42 @@ func (r *Resolver) RegisterEntity(e EntityMetadata) {
 	r.Scorer.IndexDocument(e.ID, meta, tokens)
 }
 
-// Resolve attempts to resolve text (pronoun or alias) to an EntityID
+// Fork returns a resolver with the same entities and mention history.
+// Mentions observed on the fork do not affect the original, and vice versa.
+func (r *Resolver) Fork() *Resolver {
+	return Restore(r.Context)
+}
+
+// Restore returns a resolver continuing from a copy of ctx, such as a
+// context saved with Clone partway through a note
+func Restore(ctx *NarrativeContext) *Resolver {
+	r := New()
+	for _, meta := range ctx.registry {
+		if len(meta.Members) == 0 { // Groups live in the context only
+			r.RegisterEntity(meta)
+		}
+	}
+	r.Context = ctx.Clone()
+	return r
+}
+
+// ResetHistory clears pronoun history, keeping registered entities
+func (r *Resolver) ResetHistory() {
+	r.Context.ClearHistory()
+}
+
+// Recency returns how many other entities were mentioned since id was last
+// mentioned (0 for the latest mention), or -1 if id is not in the history
+func (r *Resolver) Recency(id string) int {
+	for i, recent := range r.Context.history {
+		if recent == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Resolve attempts to resolve text (pronoun, alias or definite description) to an EntityID
 func (r *Resolver) Resolve(text string, queryVector []float32) string {
 	if r.isPronoun(text) {
 		gender := r.inferPronounGender(text)
@@ -189,19 +321,25 @@ func (r *Resolver) Resolve(text string, queryVector []float32) string {
 	}
 
 	// 1. Direct Alias Match (Fastest)
+	// Entities may share a name ("Ash" in two worlds): the most recent wins
 	lower := strings.ToLower(text)
+	for _, id := range r.Context.history {
+		if meta, ok := r.Context.registry[id]; ok && meta.named(lower) {
+			return id
+		}
+	}
 	for _, meta := range r.Context.registry {
-		if strings.ToLower(meta.Name) == lower {
+		if meta.named(lower) {
 			return meta.ID
 		}
-		for _, alias := range meta.Aliases {
-			if strings.ToLower(alias) == lower {
-				return meta.ID
-			}
-		}
 	}
 
-	// 2. Fuzzy/Hybrid Match (ResoRank)
+	// 2. Definite Description ("the old wizard")
+	if id := r.ResolveDescription(text); id != "" {
+		return id
+	}
+
+	// 3. Fuzzy/Hybrid Match (ResoRank)
 	// Split query
 	queryTokens := strings.Fields(lower)
 
@@ -224,7 +362,8 @@ func (r *Resolver) ObserveMention(entityID string) {
 
 func (r *Resolver) isPronoun(text string) bool {
 	switch strings.ToLower(text) {
-	case "he", "him", "his", "she", "her", "hers", "it", "its", "they", "them", "their":
+	case "he", "him", "his", "she", "her", "hers", "it", "its", "they", "them", "their",
+		"himself", "herself", "itself", "themselves", "themself":
 		return true
 	default:
 		return false
@@ -233,13 +372,13 @@ func (r *Resolver) isPronoun(text string) bool {
 
 func (r *Resolver) inferPronounGender(text string) Gender {
 	switch strings.ToLower(text) {
-	case "he", "him", "his":
+	case "he", "him", "his", "himself":
 		return GenderMale
-	case "she", "her", "hers":
+	case "she", "her", "hers", "herself":
 		return GenderFemale
-	case "it", "its":
+	case "it", "its", "itself":
 		return GenderNeutral
-	case "they", "them", "their":
+	case "they", "them", "their", "themselves", "themself":
 		return GenderPlural
 	default:
 		return GenderUnknown
@@ -86,3 +86,112 @@ func TestRecencyUpdate(t *testing.T) {
 		t.Errorf("Expected e4 (Frodo), got %s", res)
 	}
 }
+
+func TestForkIsolatesHistory(t *testing.T) {
+	r := setupResolver()
+	r.ObserveMention("e1")
+
+	fork := r.Fork()
+	if res := fork.Resolve("He", nil); res != "e1" {
+		t.Errorf("fork should inherit history, got %s", res)
+	}
+	if res := fork.Resolve("Mithrandir", nil); res != "e1" {
+		t.Errorf("fork should inherit aliases, got %s", res)
+	}
+
+	fork.RegisterEntity(EntityMetadata{ID: "e4", Name: "Frodo", Gender: GenderMale})
+	fork.ObserveMention("e4")
+	if res := r.Resolve("He", nil); res != "e1" {
+		t.Errorf("fork mention leaked into original, got %s", res)
+	}
+	if res := r.Resolve("Frodo", nil); res == "e4" {
+		t.Error("fork registration leaked into original")
+	}
+
+	r.ResetHistory()
+	if res := r.Resolve("He", nil); res != "" {
+		t.Errorf("expected no antecedent after reset, got %s", res)
+	}
+	if res := r.Resolve("Gandalf", nil); res != "e1" {
+		t.Errorf("reset should keep registry, got %s", res)
+	}
+}
+
+func TestDefiniteDescription(t *testing.T) {
+	r := New()
+	r.RegisterEntity(EntityMetadata{ID: "aldric", Name: "Aldric", Kind: "CHARACTER", Subtype: "King", Gender: GenderMale})
+	r.RegisterEntity(EntityMetadata{ID: "mira", Name: "Mira", Kind: "CHARACTER", Gender: GenderFemale})
+	r.RegisterEntity(EntityMetadata{ID: "citadel", Name: "Iron Citadel", Kind: "LOCATION", Gender: GenderNeutral})
+	r.ObserveMention("aldric")
+	r.ObserveMention("citadel")
+	r.ObserveMention("mira")
+
+	cases := map[string]string{
+		"the king":          "aldric",  // Subtype
+		"The Kings":         "aldric",  // Stemmed
+		"the old citadel":   "citadel", // Name word
+		"the city":          "citadel", // Kind
+		"the woman":         "mira",    // Kind and gender
+		"the man":           "aldric",
+		"the sword":         "",
+		"king":              "", // No determiner
+		"the old wizard":    "mira",
+		"the grey stranger": "mira",
+	}
+	for text, want := range cases {
+		if got := r.ResolveDescription(text); got != want {
+			t.Errorf("ResolveDescription(%q) = %q, want %q", text, got, want)
+		}
+	}
+
+	if got := r.Resolve("the king", nil); got != "aldric" {
+		t.Errorf("Resolve should try descriptions, got %q", got)
+	}
+}
+
+func TestReflexivePronouns(t *testing.T) {
+	r := setupResolver()
+	r.ObserveMention("e2") // Galadriel
+	r.ObserveMention("e1") // Gandalf
+
+	if res := r.Resolve("himself", nil); res != "e1" {
+		t.Errorf("himself: expected e1, got %s", res)
+	}
+	if res := r.Resolve("Herself", nil); res != "e2" {
+		t.Errorf("herself: expected e2, got %s", res)
+	}
+}
+
+func TestGroupResolution(t *testing.T) {
+	r := New()
+	r.RegisterEntity(EntityMetadata{ID: "aria", Name: "Aria", Gender: GenderFemale})
+	r.RegisterEntity(EntityMetadata{ID: "tom", Name: "Tom", Gender: GenderMale})
+	r.RegisterEntity(EntityMetadata{ID: "ship", Name: "Gull", Gender: GenderNeutral})
+	r.ObserveMention("aria")
+	r.ObserveMention("tom")
+
+	id := r.ObserveGroup([]string{"aria", "tom"})
+	r.ObserveMention("ship") // Neutral entities no longer win "they"
+
+	if res := r.Resolve("they", nil); res != id {
+		t.Errorf("they: expected group %s, got %s", id, res)
+	}
+	if res := r.Resolve("themselves", nil); res != id {
+		t.Errorf("themselves: expected group %s, got %s", id, res)
+	}
+	if res := r.ResolveDescription("the pair"); res != id {
+		t.Errorf("the pair: expected group %s, got %s", id, res)
+	}
+	if got := GroupMembers(id); len(got) != 2 || got[0] != "aria" || got[1] != "tom" {
+		t.Errorf("GroupMembers = %v", got)
+	}
+	if GroupMembers("aria") != nil {
+		t.Error("a single entity has no members")
+	}
+	if meta, _ := r.Entity(id); meta.Name != "Aria and Tom" {
+		t.Errorf("group name = %q", meta.Name)
+	}
+	if res := r.Resolve("he", nil); res != "tom" {
+		t.Errorf("he: members stay resolvable, got %s", res)
+	}
+}
@@ -0,0 +1,182 @@
+package syntax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// KindCustom is the first SyntaxKind handed out by Register; each
+// registered pattern gets the next one
+const KindCustom SyntaxKind = 100
+
+// InlinePattern declares an inline construct beyond the built-in ones:
+// the text between Open and Close, split by Separator into named captures.
+//
+//	{Name: "date", Open: "{{date:", Close: "}}", Captures: []string{"date"}}
+//	{Name: "gm-note", Open: "%%", Close: "%%", Hidden: true}
+//	{Name: "alias", Open: "[[", Close: "]]", Separator: "|", Captures: []string{"target", "label"}}
+type InlinePattern struct {
+	Name      string   `json:"name"`      // Names the new kind: "date", "gm-note"
+	Open      string   `json:"open"`      // Opening delimiter
+	Close     string   `json:"close"`     // Closing delimiter
+	Separator string   `json:"separator"` // Splits the content; "" keeps it whole
+	Captures  []string `json:"captures"`  // Names of the parts in order; "label" and "target" also fill those fields
+	Hidden    bool     `json:"hidden"`    // Keep the content out of prose analysis (GM notes)
+}
+
+// customPattern is a registered pattern and the kind its matches get
+type customPattern struct {
+	InlinePattern
+	kind SyntaxKind
+}
+
+// Register adds a pattern to the scanner, or replaces the one with the same
+// name (which keeps its kind). Patterns are tried before the built-in
+// constructs at the same position, longest Open first, so a pattern for
+// "[[" takes over wikilinks. Matches never span lines.
+// Register must not run concurrently with Scan.
+func (s *SyntaxScanner) Register(p InlinePattern) (SyntaxKind, error) {
+	if p.Name == "" || p.Open == "" || p.Close == "" {
+		return 0, fmt.Errorf("inline pattern needs a name, an open and a close delimiter: %+v", p)
+	}
+	if strings.ContainsAny(p.Open+p.Close, "\r\n") {
+		return 0, fmt.Errorf("inline pattern %q: delimiters cannot contain line breaks", p.Name)
+	}
+
+	kind := KindCustom + SyntaxKind(len(s.custom))
+	for i, c := range s.custom {
+		if c.Name == p.Name {
+			kind = c.kind
+			s.custom = append(s.custom[:i], s.custom[i+1:]...)
+			break
+		}
+	}
+	s.custom = append(s.custom, customPattern{InlinePattern: p, kind: kind})
+	s.compile()
+	return kind, nil
+}
+
+// ClearPatterns removes every registered pattern; kinds are handed out afresh
+func (s *SyntaxScanner) ClearPatterns() {
+	s.custom = nil
+	s.compile()
+}
+
+// Patterns returns the registered patterns in kind order
+func (s *SyntaxScanner) Patterns() []InlinePattern {
+	out := make([]InlinePattern, len(s.custom))
+	for i, c := range s.byKind() {
+		out[i] = c.InlinePattern
+	}
+	return out
+}
+
+// KindName returns the name of a registered pattern's kind, or "" for built-in kinds
+func (s *SyntaxScanner) KindName(k SyntaxKind) string {
+	if c := s.pattern(k); c != nil {
+		return c.Name
+	}
+	return ""
+}
+
+// IsHidden reports a match of a pattern whose content stays out of prose analysis
+func (s *SyntaxScanner) IsHidden(m SyntaxMatch) bool {
+	c := s.pattern(m.Kind)
+	return c != nil && c.Hidden
+}
+
+// ============================================================================
+// Scanning
+// ============================================================================
+
+// compile rebuilds the trigger set: the built-in triggers plus the first
+// character of each Open, with patterns ordered longest Open first
+func (s *SyntaxScanner) compile() {
+	sort.SliceStable(s.custom, func(i, j int) bool {
+		return len(s.custom[i].Open) > len(s.custom[j].Open)
+	})
+	triggers := builtinTriggers
+	for _, c := range s.custom {
+		r, _ := utf8.DecodeRuneInString(c.Open)
+		if !strings.ContainsRune(triggers, r) {
+			triggers += string(r)
+		}
+	}
+	s.triggers = triggers
+}
+
+// tryCustom matches the first registered pattern opening at start
+func (s *SyntaxScanner) tryCustom(fs *fastScanner, start int) *SyntaxMatch {
+	for i := range s.custom {
+		c := &s.custom[i]
+		if !strings.HasPrefix(fs.text[start:], c.Open) {
+			continue
+		}
+		from := start + len(c.Open)
+		rest := fs.text[from:]
+		if eol := strings.IndexAny(rest, "\r\n"); eol >= 0 {
+			rest = rest[:eol]
+		}
+		end := strings.Index(rest, c.Close)
+		if end <= 0 {
+			continue
+		}
+		content := rest[:end]
+		end = from + end + len(c.Close)
+
+		m := &SyntaxMatch{
+			Start:    start,
+			End:      end,
+			Text:     fs.text[start:end],
+			Original: fs.text[start:end],
+			Kind:     c.kind,
+			Label:    content,
+		}
+		c.capture(m, content)
+		return m
+	}
+	return nil
+}
+
+// capture splits content into the pattern's named captures
+func (c *customPattern) capture(m *SyntaxMatch, content string) {
+	if len(c.Captures) == 0 {
+		return
+	}
+	parts := []string{content}
+	if c.Separator != "" {
+		parts = strings.SplitN(content, c.Separator, len(c.Captures))
+	}
+	m.Captures = make(map[string]string, len(parts))
+	for i, part := range parts {
+		name := c.Captures[i]
+		m.Captures[name] = part
+		switch name {
+		case "label":
+			m.Label = part
+		case "target":
+			m.Target = part
+		}
+	}
+	// "[[Target|display]]" without a display part reads as its target
+	if _, ok := m.Captures["label"]; !ok && m.Target != "" {
+		m.Label = m.Target
+	}
+}
+
+func (s *SyntaxScanner) pattern(k SyntaxKind) *customPattern {
+	for i := range s.custom {
+		if s.custom[i].kind == k {
+			return &s.custom[i]
+		}
+	}
+	return nil
+}
+
+func (s *SyntaxScanner) byKind() []customPattern {
+	out := append([]customPattern(nil), s.custom...)
+	sort.Slice(out, func(i, j int) bool { return out[i].kind < out[j].kind })
+	return out
+}
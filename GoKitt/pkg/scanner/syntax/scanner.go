@@ -18,13 +18,20 @@ func (s *SyntaxScanner) Scan(text string) []SyntaxMatch {
 
 	for i < fs.n {
 		// Optimization: Skip until next potential trigger
-		// Triggers: [, <, #, @
-		nextTrigger := strings.IndexAny(text[i:], "[<#@")
+		// Triggers: [, <, #, @ and the first character of each registered pattern
+		nextTrigger := strings.IndexAny(text[i:], s.triggers)
 		if nextTrigger == -1 {
 			break
 		}
 		i += nextTrigger
 
+		// Registered patterns go first: "{{date:...}}", "%%GM note%%"
+		if m := s.tryCustom(&fs, i); m != nil {
+			matches = append(matches, *m)
+			i = m.End
+			continue
+		}
+
 		// Analyze trigger
 		char := text[i]
 		switch char {
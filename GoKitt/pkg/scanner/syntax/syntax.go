@@ -1,5 +1,6 @@
 // Package syntax provides regex-based pattern detection.
-// It detects Wikilinks, Explicit Entities, Triples, Tags, and Mentions.
+// It detects Wikilinks, Explicit Entities, Triples, Tags, and Mentions,
+// plus any inline constructs registered as InlinePatterns.
 package syntax
 
 // SyntaxKind distinguishes the type of syntax match
@@ -13,6 +14,7 @@ const (
 	KindInlineRelation
 	KindTag
 	KindMention
+	// Registered patterns get KindCustom and up, see Register
 )
 
 // SyntaxMatch represents a detected pattern
@@ -26,19 +28,28 @@ type SyntaxMatch struct {
 	Target      string // For wikilink/backlink
 	Label       string // For all
 	EntityKind  string // For explicit entity
+	EntityID    string // For implicit entity: the dictionary entity chosen ("" for tags, which are named by Label)
 	Subtype     string // For explicit entity
 	Predicate   string // For triples/relations
 	Subject     string // For triples
 	SubjectKind string // For triples
 	Object      string // For triples
 	ObjectKind  string // For triples
+
+	// For registered patterns: the captured parts by name
+	Captures map[string]string
 }
 
-// SyntaxScanner holds configuration (now stateless/regex-free)
+// builtinTriggers are the characters that can open a built-in construct
+const builtinTriggers = "[<#@"
+
+// SyntaxScanner holds the registered inline patterns (regex-free)
 type SyntaxScanner struct {
+	custom   []customPattern // Longest Open first
+	triggers string          // builtinTriggers plus the first character of each Open
 }
 
 // New creates a scanner
 func New() *SyntaxScanner {
-	return &SyntaxScanner{}
+	return &SyntaxScanner{triggers: builtinTriggers}
 }
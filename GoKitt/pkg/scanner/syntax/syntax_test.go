@@ -140,3 +140,60 @@ func TestFullScan(t *testing.T) {
 		t.Errorf("Expected 3 matches, got %d", len(matches))
 	}
 }
+
+func TestRegisteredPatterns(t *testing.T) {
+	s := New()
+	date, err := s.Register(InlinePattern{Name: "date", Open: "{{date:", Close: "}}", Captures: []string{"date"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, _ := s.Register(InlinePattern{Name: "gm-note", Open: "%%", Close: "%%", Hidden: true})
+	alias, _ := s.Register(InlinePattern{Name: "alias", Open: "[[", Close: "]]", Separator: "|", Captures: []string{"target", "label"}})
+	if date != KindCustom || note != KindCustom+1 || alias != KindCustom+2 {
+		t.Fatalf("kinds = %d, %d, %d", date, note, alias)
+	}
+	if s.KindName(note) != "gm-note" || s.KindName(KindTag) != "" {
+		t.Errorf("KindName = %q, %q", s.KindName(note), s.KindName(KindTag))
+	}
+
+	text := "On {{date:1042-03-01}} %%the heir lives%% [[Aria|the girl]] met [[Tom]] #tag {{date:\n}}"
+	matches := s.Scan(text)
+
+	if m := filterKind(matches, date); len(m) != 1 || m[0].Captures["date"] != "1042-03-01" || m[0].Label != "1042-03-01" {
+		t.Errorf("date = %+v", m)
+	}
+	if m := filterKind(matches, note); len(m) != 1 || m[0].Label != "the heir lives" || !s.IsHidden(m[0]) {
+		t.Errorf("gm note = %+v", m)
+	}
+	m := filterKind(matches, alias)
+	if len(m) != 2 || m[0].Target != "Aria" || m[0].Label != "the girl" || m[1].Label != "Tom" {
+		t.Errorf("aliases = %+v", m)
+	}
+	if len(filterKind(matches, KindWikilink)) != 0 {
+		t.Error("a pattern for [[ should take over wikilinks")
+	}
+	if len(filterKind(matches, KindTag)) != 1 {
+		t.Error("built-in tags should still match")
+	}
+	if len(matches) != 5 {
+		t.Errorf("expected 5 matches (the unclosed date spans a line), got %+v", matches)
+	}
+
+	// Re-registering a name keeps its kind
+	if k, _ := s.Register(InlinePattern{Name: "gm-note", Open: "%%", Close: "%%"}); k != note || len(s.Patterns()) != 3 {
+		t.Errorf("re-registered gm-note = %d with %d patterns", k, len(s.Patterns()))
+	}
+	if _, err := s.Register(InlinePattern{Name: "broken", Open: "!!"}); err == nil {
+		t.Error("pattern without a close delimiter should be rejected")
+	}
+
+	aside, _ := s.Register(InlinePattern{Name: "aside", Open: "«", Close: "»"})
+	if m := filterKind(s.Scan("Tom «quietly» left"), aside); len(m) != 1 || m[0].Label != "quietly" {
+		t.Errorf("aside = %+v", m)
+	}
+
+	s.ClearPatterns()
+	if len(filterKind(s.Scan(text), KindWikilink)) != 2 {
+		t.Error("wikilinks should match again once patterns are cleared")
+	}
+}
@@ -0,0 +1,21 @@
+package stem
+
+import "strings"
+
+// Analyzer names how a field's words become index terms
+type Analyzer string
+
+const (
+	// Stemmed lemmatizes and stems: prose, where "fought" should find "fight"
+	Stemmed Analyzer = "stem"
+	// Lowercase only folds case: names, tags and titles, which must never be stemmed
+	Lowercase Analyzer = "lowercase"
+)
+
+// Term converts a word with this analyzer. Unknown names stem.
+func (a Analyzer) Term(word string) string {
+	if a == Lowercase {
+		return strings.ToLower(word)
+	}
+	return Term(word)
+}
@@ -0,0 +1,63 @@
+package stem
+
+// Lemma maps an irregular verb form to its base ("fought" -> "fight",
+// "was" -> "be"); other words come back unchanged. Expects lower case.
+// Forms that are also common nouns or other verbs ("rose", "wound", "lay")
+// are left out.
+func Lemma(word string) string {
+	if base, ok := irregularVerbs[word]; ok {
+		return base
+	}
+	return word
+}
+
+// irregularVerbs: past tense and past participle -> base form
+var irregularVerbs = map[string]string{
+	// be, have, do
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "having": "have",
+	"does": "do", "did": "do", "done": "do",
+
+	// Motion
+	"went": "go", "gone": "go", "came": "come", "ran": "run", "fled": "flee", "fell": "fall", "fallen": "fall",
+	"rode": "ride", "ridden": "ride", "drove": "drive", "driven": "drive", "flew": "fly", "flown": "fly",
+	"swam": "swim", "swum": "swim", "sped": "speed", "slid": "slide", "strode": "stride",
+	"sprang": "spring", "sprung": "spring", "arose": "arise", "arisen": "arise",
+	"left": "leave", "led": "lead", "sat": "sit", "stood": "stand",
+
+	// Conflict
+	"fought": "fight", "slew": "slay", "slain": "slay", "struck": "strike", "stricken": "strike",
+	"smote": "smite", "smitten": "smite", "shot": "shoot", "bled": "bleed", "bitten": "bite",
+	"overcame": "overcome", "overthrew": "overthrow", "overthrown": "overthrow",
+	"won": "win", "lost": "lose", "broke": "break", "broken": "break", "tore": "tear", "torn": "tear",
+	"threw": "throw", "thrown": "throw", "shook": "shake", "shaken": "shake", "strove": "strive", "striven": "strive",
+
+	// Possession
+	"stole": "steal", "stolen": "steal", "took": "take", "taken": "take", "gave": "give", "given": "give",
+	"got": "get", "gotten": "get", "kept": "keep", "held": "hold", "beheld": "behold", "withheld": "withhold",
+	"upheld": "uphold", "bought": "buy", "sold": "sell", "sent": "send", "lent": "lend", "paid": "pay",
+	"brought": "bring", "caught": "catch", "sought": "seek", "found": "find", "hid": "hide", "hidden": "hide",
+	"overtook": "overtake", "overtaken": "overtake", "forsook": "forsake", "forsaken": "forsake",
+	"mistook": "mistake", "mistaken": "mistake", "undertook": "undertake", "undertaken": "undertake",
+
+	// Speech and thought
+	"said": "say", "told": "tell", "spoke": "speak", "spoken": "speak", "swore": "swear", "sworn": "swear",
+	"knew": "know", "known": "know", "thought": "think", "taught": "teach", "meant": "mean",
+	"heard": "hear", "saw": "see", "seen": "see", "foresaw": "foresee", "foreseen": "foresee",
+	"forgot": "forget", "forgotten": "forget", "forgave": "forgive", "forgiven": "forgive",
+	"understood": "understand", "sang": "sing", "sung": "sing", "wrote": "write", "written": "write",
+	"chose": "choose", "chosen": "choose", "dreamt": "dream", "dealt": "deal", "learnt": "learn",
+
+	// Change and making
+	"became": "become", "began": "begin", "begun": "begin", "made": "make", "built": "build",
+	"grew": "grow", "grown": "grow", "froze": "freeze", "frozen": "freeze", "woke": "wake", "woken": "wake",
+	"awoke": "awake", "awoken": "awake", "drew": "draw", "drawn": "draw", "wove": "weave", "woven": "weave",
+	"bound": "bind", "burnt": "burn", "spun": "spin", "lit": "light", "laid": "lay", "lain": "lie", "sank": "sink", "sunk": "sink",
+	"shrank": "shrink", "shrunk": "shrink", "blew": "blow", "blown": "blow", "rang": "ring", "rung": "ring",
+
+	// Body and the rest
+	"ate": "eat", "eaten": "eat", "drank": "drink", "drunk": "drink", "fed": "feed", "bred": "breed",
+	"felt": "feel", "slept": "sleep", "wept": "weep", "swept": "sweep", "knelt": "kneel", "met": "meet",
+	"wore": "wear", "worn": "wear", "hung": "hang", "dug": "dig", "clung": "cling", "flung": "fling",
+	"stung": "sting", "swung": "swing", "stuck": "stick", "stank": "stink", "spent": "spend", "bent": "bend",
+}
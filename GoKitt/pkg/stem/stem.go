@@ -0,0 +1,332 @@
+// Package stem normalizes English words so inflections match: an
+// irregular-verb lemma table ("fought" -> "fight") followed by the Porter2
+// (Snowball English) stemmer ("arrives", "arrival" -> "arriv").
+//
+// Narrative verb lookup, search indexing and search queries all go through
+// Term, so a word is reduced the same way wherever it is matched.
+package stem
+
+import "strings"
+
+// Term lower-cases a word, maps irregular verb forms to their base and stems the result
+func Term(word string) string {
+	return Stem(Lemma(strings.ToLower(word)))
+}
+
+// Stem reduces a word to its Porter2 stem. Words with non-ASCII letters
+// are only lower-cased.
+func Stem(word string) string {
+	word = strings.ToLower(word)
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] >= 0x80 {
+			return word
+		}
+	}
+	if s, ok := exceptions[word]; ok {
+		return s
+	}
+
+	w := []byte(strings.TrimPrefix(word, "'"))
+	if len(w) == 0 {
+		return word
+	}
+	markY(w)
+	r1, r2 := regions(w)
+
+	w = step0(w)
+	w = step1a(w)
+	if keepAfter1a[string(w)] {
+		return string(w)
+	}
+	w = step1b(w, r1)
+	w = step1c(w)
+	w = step2(w, r1)
+	w = step3(w, r1, r2)
+	w = step4(w, r2)
+	w = step5(w, r1, r2)
+
+	for i, c := range w {
+		if c == 'Y' {
+			w[i] = 'y'
+		}
+	}
+	return string(w)
+}
+
+// =============================================================================
+// Steps
+// =============================================================================
+
+// step0 removes possessive apostrophes
+func step0(w []byte) []byte {
+	for _, s := range []string{"'s'", "'s", "'"} {
+		if hasSuffix(w, s) {
+			return w[:len(w)-len(s)]
+		}
+	}
+	return w
+}
+
+// step1a handles plurals: sses, ies/ied, s
+func step1a(w []byte) []byte {
+	switch {
+	case hasSuffix(w, "sses"):
+		return w[:len(w)-2]
+	case hasSuffix(w, "ied"), hasSuffix(w, "ies"):
+		if len(w) > 4 {
+			return w[:len(w)-2] // cries -> cri
+		}
+		return w[:len(w)-1] // ties -> tie
+	case hasSuffix(w, "us"), hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		if containsVowel(w[:len(w)-2]) {
+			return w[:len(w)-1] // gaps -> gap, but gas stays
+		}
+	}
+	return w
+}
+
+// step1b handles -ed and -ing, restoring an e where the stem needs one
+func step1b(w []byte, r1 int) []byte {
+	suffix := longestSuffix(w, "eedly", "ingly", "edly", "eed", "ing", "ed")
+	switch suffix {
+	case "":
+		return w
+	case "eed", "eedly":
+		if len(w)-len(suffix) >= r1 {
+			w = append(w[:len(w)-len(suffix)], "ee"...)
+		}
+		return w
+	}
+
+	stem := w[:len(w)-len(suffix)]
+	if !containsVowel(stem) {
+		return w
+	}
+	w = stem
+	switch {
+	case hasSuffix(w, "at"), hasSuffix(w, "bl"), hasSuffix(w, "iz"):
+		w = append(w, 'e')
+	case endsDouble(w):
+		w = w[:len(w)-1] // hopping -> hop
+	case isShort(w, r1):
+		w = append(w, 'e') // hoped -> hope
+	}
+	return w
+}
+
+// step1c turns a final y after a consonant into i (cry -> cri, but say stays)
+func step1c(w []byte) []byte {
+	n := len(w)
+	if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !isVowel(w[n-2]) {
+		w[n-1] = 'i'
+	}
+	return w
+}
+
+var step2Rules = [][2]string{
+	{"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"abli", "able"},
+	{"entli", "ent"}, {"izer", "ize"}, {"ization", "ize"}, {"ational", "ate"},
+	{"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"aliti", "al"},
+	{"alli", "al"}, {"fulness", "ful"}, {"ousli", "ous"}, {"ousness", "ous"},
+	{"iveness", "ive"}, {"iviti", "ive"}, {"biliti", "ble"}, {"bli", "ble"},
+	{"ogi", "og"}, {"fulli", "ful"}, {"lessli", "less"}, {"li", ""},
+}
+
+// step2 maps derivational suffixes in R1 to simpler ones
+func step2(w []byte, r1 int) []byte {
+	rule, ok := longestRule(w, step2Rules)
+	if !ok || len(w)-len(rule[0]) < r1 {
+		return w
+	}
+	stem := w[:len(w)-len(rule[0])]
+	switch rule[0] {
+	case "ogi":
+		if !hasSuffix(stem, "l") {
+			return w
+		}
+	case "li":
+		if len(stem) == 0 || !strings.ContainsRune("cdeghkmnrt", rune(stem[len(stem)-1])) {
+			return w
+		}
+	}
+	return append(stem, rule[1]...)
+}
+
+var step3Rules = [][2]string{
+	{"tional", "tion"}, {"ational", "ate"}, {"alize", "al"}, {"icate", "ic"},
+	{"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""}, {"ative", ""},
+}
+
+// step3 removes or simplifies further suffixes in R1 (ative only in R2)
+func step3(w []byte, r1, r2 int) []byte {
+	rule, ok := longestRule(w, step3Rules)
+	if !ok {
+		return w
+	}
+	start := len(w) - len(rule[0])
+	if start < r1 || (rule[0] == "ative" && start < r2) {
+		return w
+	}
+	return append(w[:start], rule[1]...)
+}
+
+// step4 deletes residual suffixes in R2
+func step4(w []byte, r2 int) []byte {
+	suffix := longestSuffix(w, "al", "ance", "ence", "er", "ic", "able", "ible", "ant",
+		"ement", "ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion")
+	if suffix == "" || len(w)-len(suffix) < r2 {
+		return w
+	}
+	stem := w[:len(w)-len(suffix)]
+	if suffix == "ion" && !hasSuffix(stem, "s") && !hasSuffix(stem, "t") {
+		return w
+	}
+	return stem
+}
+
+// step5 drops a final e or the second l of a final ll
+func step5(w []byte, r1, r2 int) []byte {
+	n := len(w)
+	switch {
+	case hasSuffix(w, "e"):
+		if n-1 >= r2 || (n-1 >= r1 && !endsShortSyllable(w[:n-1])) {
+			return w[:n-1]
+		}
+	case hasSuffix(w, "ll"):
+		if n-1 >= r2 {
+			return w[:n-1]
+		}
+	}
+	return w
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// exceptions are whole words the steps would get wrong
+var exceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli",
+	"singly": "singl", "sky": "sky", "news": "news", "howe": "howe",
+	"atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// keepAfter1a are words left alone once plurals are removed
+var keepAfter1a = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func containsVowel(w []byte) bool {
+	for _, c := range w {
+		if isVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// markY upper-cases a y that acts as a consonant: initial, or after a vowel
+func markY(w []byte) {
+	for i, c := range w {
+		if c == 'y' && (i == 0 || isVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+}
+
+// regions returns the starts of R1 and R2: each begins after the first
+// non-vowel that follows a vowel in the region before it
+func regions(w []byte) (r1, r2 int) {
+	r1 = -1
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w), prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+	if r1 < 0 {
+		r1 = regionAfter(w, 0)
+	}
+	return r1, regionAfter(w, r1)
+}
+
+func regionAfter(w []byte, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isVowel(w[i]) && isVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// endsShortSyllable: consonant-vowel-consonant (last not w, x or Y), or
+// vowel-consonant at the start of the word
+func endsShortSyllable(w []byte) bool {
+	n := len(w)
+	switch {
+	case n == 2:
+		return isVowel(w[0]) && !isVowel(w[1])
+	case n > 2:
+		last := w[n-1]
+		return !isVowel(w[n-3]) && isVowel(w[n-2]) && !isVowel(last) &&
+			last != 'w' && last != 'x' && last != 'Y'
+	}
+	return false
+}
+
+// isShort reports a word that ends in a short syllable and has an empty R1
+func isShort(w []byte, r1 int) bool {
+	return r1 >= len(w) && endsShortSyllable(w)
+}
+
+func endsDouble(w []byte) bool {
+	n := len(w)
+	if n < 2 || w[n-1] != w[n-2] {
+		return false
+	}
+	switch w[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func hasSuffix(w []byte, s string) bool {
+	return len(w) >= len(s) && string(w[len(w)-len(s):]) == s
+}
+
+func longestSuffix(w []byte, suffixes ...string) string {
+	best := ""
+	for _, s := range suffixes {
+		if len(s) > len(best) && hasSuffix(w, s) {
+			best = s
+		}
+	}
+	return best
+}
+
+func longestRule(w []byte, rules [][2]string) ([2]string, bool) {
+	var best [2]string
+	found := false
+	for _, r := range rules {
+		if (!found || len(r[0]) > len(best[0])) && hasSuffix(w, r[0]) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
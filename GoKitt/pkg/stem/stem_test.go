@@ -0,0 +1,44 @@
+package stem
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	// Outputs of the reference Snowball English stemmer
+	cases := map[string]string{
+		"caresses": "caress", "ponies": "poni", "ties": "tie", "cats": "cat", "gas": "gas",
+		"cried": "cri", "cries": "cri", "skies": "sky", "dying": "die", "news": "news",
+		"agreed": "agre", "feed": "feed", "plastered": "plaster", "motoring": "motor", "sing": "sing",
+		"hoped": "hope", "hopping": "hop", "conflated": "conflat", "troubled": "troubl", "sized": "size",
+		"tanned": "tan", "falling": "fall", "hissing": "hiss", "fizzed": "fizz", "failing": "fail", "filing": "file",
+		"happy": "happi", "say": "say", "relational": "relat", "conditional": "condit", "rational": "ration",
+		"digitizer": "digit", "operator": "oper", "feudalism": "feudal", "hopefulness": "hope",
+		"generate": "generat", "generously": "generous", "consignment": "consign", "consistency": "consist",
+		"knackeries": "knackeri", "proceed": "proceed",
+		"arrive": "arriv", "arrives": "arriv", "arrival": "arriv", "arrived": "arriv",
+		"traveled": "travel", "travelling": "travel", "betrayed": "betray", "attacking": "attack",
+		"loving": "love", "hated": "hate", "leaving": "leav", "discovered": "discov",
+	}
+	for word, want := range cases {
+		if got := Stem(word); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestTerm(t *testing.T) {
+	pairs := [][2]string{
+		{"fought", "fight"}, {"Stole", "steal"}, {"stolen", "stealing"}, {"arrives", "arrival"},
+		{"was", "be"}, {"spoke", "speaks"}, {"hid", "hiding"},
+	}
+	for _, p := range pairs {
+		if a, b := Term(p[0]), Term(p[1]); a != b {
+			t.Errorf("Term(%q) = %q, Term(%q) = %q", p[0], a, p[1], b)
+		}
+	}
+	if got := Lowercase.Term("Baggins"); got != "baggins" {
+		t.Errorf("Lowercase.Term = %q", got)
+	}
+	if got := Stemmed.Term("Baggins"); got != "baggin" {
+		t.Errorf("Stemmed.Term = %q", got)
+	}
+}